@@ -8,13 +8,16 @@ require (
 	github.com/btcsuite/btcd v0.22.0-beta
 	github.com/btcsuite/btcutil v1.0.3-0.20201208143702-a53e38424cce
 	github.com/evanphx/json-patch v4.1.0+incompatible
+	github.com/fxamacker/cbor/v2 v2.3.0
 	github.com/gorilla/mux v1.7.3
 	github.com/multiformats/go-multihash v0.0.14
 	github.com/pkg/errors v0.9.1
 	github.com/square/go-jose/v3 v3.0.0-20200630053402-0a67ce9b0693
 	github.com/stretchr/testify v1.7.0
 	github.com/trustbloc/edge-core v0.1.7-0.20210816120552-ed93662ac716
+	go.etcd.io/bbolt v1.3.6
 	golang.org/x/crypto v0.0.0-20210616213533-5ff15b29337e
+	gopkg.in/yaml.v3 v3.0.0-20210107192922-496545a6307b
 )
 
 go 1.13
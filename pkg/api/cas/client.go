@@ -6,6 +6,15 @@ SPDX-License-Identifier: Apache-2.0
 
 package cas
 
+import (
+	"errors"
+	"io"
+)
+
+// ErrContentNotFound is returned by a CAS client when no content exists for the requested address.
+// Callers should check for this error using errors.Is.
+var ErrContentNotFound = errors.New("content not found")
+
 // Client defines interface for accessing the underlying content addressable storage.
 type Client interface {
 	// Write writes the given content to CASClient.
@@ -15,3 +24,18 @@ type Client interface {
 	// returns the content of the given address.
 	Read(address string) ([]byte, error)
 }
+
+// StreamReader is an optional interface that may be implemented by a CAS client in order to allow content
+// to be read (and size limits to be enforced) without holding the entire content in memory.
+type StreamReader interface {
+	// ReadStream returns a reader for the content of the given address. The caller must close the reader.
+	// If the content doesn't match the address then the reader returns an error instead of io.EOF.
+	ReadStream(address string) (io.ReadCloser, error)
+}
+
+// Prefetcher is an optional interface that may be implemented by a (caching) CAS client in order to allow
+// content to be retrieved in the background before it is read.
+type Prefetcher interface {
+	// Prefetch retrieves the content of the given addresses in the background.
+	Prefetch(addresses ...string)
+}
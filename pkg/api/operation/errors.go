@@ -0,0 +1,18 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package operation
+
+import "errors"
+
+var (
+	// ErrNotFound is returned by an operation store when no operations exist for the requested unique suffix.
+	// Callers should check for this error using errors.Is.
+	ErrNotFound = errors.New("uniqueSuffix not found in the store")
+
+	// ErrBadRequest is returned (wrapped) if an operation request is invalid, e.g. if it can't be parsed.
+	ErrBadRequest = errors.New("bad request")
+)
@@ -7,6 +7,8 @@ SPDX-License-Identifier: Apache-2.0
 package protocol
 
 import (
+	"context"
+
 	"github.com/trustbloc/sidetree-core-go/pkg/api/operation"
 	"github.com/trustbloc/sidetree-core-go/pkg/api/txn"
 	"github.com/trustbloc/sidetree-core-go/pkg/document"
@@ -88,6 +90,13 @@ type TxnProcessor interface {
 	Process(sidetreeTxn txn.SidetreeTxn, suffixes ...string) error
 }
 
+// OperationFilter filters the operations for the given unique suffix before they are persisted by the
+// transaction processor. The returned operations are persisted (an empty result means that no operations
+// are persisted for the suffix).
+type OperationFilter interface {
+	Filter(uniqueSuffix string, ops []*operation.AnchoredOperation) ([]*operation.AnchoredOperation, error)
+}
+
 // OperationParser defines the functions for parsing operations.
 type OperationParser interface {
 	Parse(namespace string, operation []byte) (*operation.Operation, error)
@@ -108,6 +117,40 @@ type ResolutionModel struct {
 	AnchorOrigin                     interface{}
 	EquivalentReferences             []string
 	CanonicalReference               string
+	OperationHistory                 []*OperationHistoryEntry
+
+	// VersionID identifies the version of the document (the multihash of the last applied operation).
+	VersionID string
+
+	// CreatedTime is the transaction time of the create operation.
+	CreatedTime uint64
+
+	// UpdatedTime is the transaction time of the last applied operation (zero if only the create operation was applied).
+	UpdatedTime uint64
+
+	// NextVersionID and NextUpdateTime identify the next version of the document if a previous version was resolved.
+	NextVersionID  string
+	NextUpdateTime uint64
+}
+
+// OperationHistoryEntry describes how an operation was processed during resolution.
+type OperationHistoryEntry struct {
+	Type               operation.Type `json:"type"`
+	TransactionTime    uint64         `json:"transactionTime"`
+	TransactionNumber  uint64         `json:"transactionNumber"`
+	CanonicalReference string         `json:"canonicalReference,omitempty"`
+
+	// Applied indicates whether the operation was applied to the document.
+	Applied bool `json:"applied"`
+
+	// Reason is the reason why the operation was not applied.
+	Reason string `json:"reason,omitempty"`
+
+	// UpdateCommitment is the update commitment of the document after the operation was applied.
+	UpdateCommitment string `json:"updateCommitment,omitempty"`
+
+	// RecoveryCommitment is the recovery commitment of the document after the operation was applied.
+	RecoveryCommitment string `json:"recoveryCommitment,omitempty"`
 }
 
 // OperationApplier applies the given operation to the document.
@@ -147,7 +190,10 @@ type OperationHandler interface {
 
 // OperationProvider retrieves the anchored operations for the given Sidetree transaction.
 type OperationProvider interface {
-	GetTxnOperations(sidetreeTxn *txn.SidetreeTxn) ([]*operation.AnchoredOperation, error)
+	// GetTxnOperations returns the anchored operations for the given Sidetree transaction. If suffixes are
+	// provided then only the operations for those suffixes are returned. Retrieval of the operations is
+	// aborted when the given context is done.
+	GetTxnOperations(ctx context.Context, sidetreeTxn *txn.SidetreeTxn, suffixes ...string) ([]*operation.AnchoredOperation, error)
 }
 
 // DocumentValidator is an interface for validating document operations.
@@ -25,7 +25,9 @@ type OperationQueue interface {
 	// - The operations that are to be removed.
 	// - The 'Ack' function that must be called to commit the remove.
 	// - The 'Nack' function that must be called to roll back the remove.
-	Remove(num uint) (ops operation.QueuedOperationsAtTime, ack func() uint, nack func(), err error)
+	// The items at the given 'skip' positions (relative to the head of the queue) are not removed;
+	// they remain in the queue in their original order.
+	Remove(num uint, skip ...uint) (ops operation.QueuedOperationsAtTime, ack func() uint, nack func(), err error)
 	// Peek returns (up to) the given number of operations from the head of the queue but does not remove them.
 	Peek(num uint) (operation.QueuedOperationsAtTime, error)
 	// Len returns the number of operation in the queue.
@@ -44,6 +46,9 @@ type Result struct {
 	ProtocolGenesisTime uint64
 	// Pending is the number of operations remaining in the queue
 	Pending uint
+	// Skipped is the number of operations that were left in the queue (i.e. deferred to a subsequent batch)
+	// since the batch already contains an operation for the same unique suffix
+	Skipped uint
 	// Ack commits the remove from the queue and returns the number of pending operations.
 	Ack func() uint
 	// Nack rolls back the remove so that a retry may occur.
@@ -76,6 +81,9 @@ func (r *BatchCutter) Add(op *operation.QueuedOperation, protocolGenesisTime uin
 // If force is true then the batch will be cut if there is at least one Data in the batch
 // Note that the operations are removed from the queue when Result.Ack is invoked, otherwise Result.Nack should be called
 // in order to place the operations back in the queue so that they be processed again.
+// A batch contains at most one operation per unique suffix. Operations whose suffix is already in the batch are
+// skipped (in which case the batch is filled with the operations that follow them) and are left in the queue,
+// in their original order, for a subsequent batch.
 func (r *BatchCutter) Cut(force bool) (Result, error) {
 	pending := r.pendingBatch.Len()
 
@@ -89,15 +97,17 @@ func (r *BatchCutter) Cut(force bool) (Result, error) {
 		return Result{Pending: pending}, nil
 	}
 
-	batchSize := min(pending, maxOperationsPerBatch)
-	ops, err := r.pendingBatch.Peek(batchSize)
+	// peek all pending operations since operations with duplicate suffixes may have to be skipped
+	ops, err := r.pendingBatch.Peek(pending)
 	if err != nil {
 		return Result{Pending: pending}, nil
 	}
 
 	operations, protocolGenesisTime := getOperationsAtProtocolVersion(ops)
 
-	batchSize = uint(len(operations))
+	operations, numPeeked, skipped := getOperationsWithUniqueSuffix(operations, maxOperationsPerBatch)
+
+	batchSize := uint(len(operations))
 
 	if batchSize == 0 {
 		return Result{Pending: pending}, nil
@@ -105,9 +115,10 @@ func (r *BatchCutter) Cut(force bool) (Result, error) {
 
 	pending -= batchSize
 
-	logger.Infof("Pending Size: %d, MaxOperationsPerBatch: %d, Batch Size: %d", pending, maxOperationsPerBatch, batchSize)
+	logger.Infof("Pending Size: %d, MaxOperationsPerBatch: %d, Batch Size: %d, Skipped: %d",
+		pending, maxOperationsPerBatch, batchSize, len(skipped))
 
-	ops, ack, nack, err := r.pendingBatch.Remove(batchSize)
+	ops, ack, nack, err := r.pendingBatch.Remove(numPeeked, skipped...)
 	if err != nil {
 		return Result{}, fmt.Errorf("pending batch queue remove: %w", err)
 	}
@@ -116,6 +127,7 @@ func (r *BatchCutter) Cut(force bool) (Result, error) {
 		Operations:          ops.QueuedOperations(),
 		ProtocolGenesisTime: protocolGenesisTime,
 		Pending:             pending,
+		Skipped:             uint(len(skipped)),
 		Ack:                 ack,
 		Nack:                nack,
 	}, nil
@@ -150,10 +162,33 @@ func getOperationsAtProtocolVersion(opsAtTime []*operation.QueuedOperationAtTime
 	return ops, protocolGenesisTime
 }
 
-func min(i, j uint) uint {
-	if i < j {
-		return i
+// getOperationsWithUniqueSuffix returns (up to) the given maximum number of operations with unique suffixes
+// in the order of the given operations. The number of operations that were examined is returned along with
+// the positions of the examined operations that were skipped since their suffix is already in the batch.
+func getOperationsWithUniqueSuffix(ops []*operation.QueuedOperation, max uint) ([]*operation.QueuedOperation, uint, []uint) {
+	suffixes := make(map[string]bool)
+
+	var unique []*operation.QueuedOperation
+
+	var skipped []uint
+
+	for i, op := range ops {
+		if uint(len(unique)) == max {
+			return unique, uint(i), skipped
+		}
+
+		if suffixes[op.UniqueSuffix] {
+			logger.Debugf("Deferring operation for suffix [%s] to a subsequent batch since the batch already contains an operation for this suffix", op.UniqueSuffix)
+
+			skipped = append(skipped, uint(i))
+
+			continue
+		}
+
+		suffixes[op.UniqueSuffix] = true
+
+		unique = append(unique, op)
 	}
 
-	return j
+	return unique, uint(len(ops)), skipped
 }
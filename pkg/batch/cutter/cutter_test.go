@@ -123,3 +123,58 @@ func TestBatchCutter(t *testing.T) {
 
 	require.Zero(t, result.Ack())
 }
+
+func TestBatchCutter_DuplicateSuffix(t *testing.T) {
+	c := mocks.NewMockProtocolClient()
+	c.Protocol.MaxOperationCount = 3
+	c.CurrentVersion.ProtocolReturns(c.Protocol)
+
+	r := New(c, &opqueue.MemQueue{})
+
+	operation1Update := &operation.QueuedOperation{UniqueSuffix: "1", OperationBuffer: []byte("operation1-update")}
+	operation1Recover := &operation.QueuedOperation{UniqueSuffix: "1", OperationBuffer: []byte("operation1-recover")}
+
+	for _, op := range []*operation.QueuedOperation{operation1, operation2, operation1Update, operation3, operation1Recover} {
+		_, err := r.Add(op, 10)
+		require.NoError(t, err)
+	}
+
+	result, err := r.Cut(false)
+	require.NoError(t, err)
+	require.Len(t, result.Operations, 3)
+	require.Equal(t, operation1, result.Operations[0])
+	require.Equal(t, operation2, result.Operations[1])
+	require.Equal(t, operation3, result.Operations[2])
+	require.Equal(t, uint(1), result.Skipped)
+	require.Equal(t, uint(2), result.Pending)
+
+	result.Nack()
+
+	// After a rollback the same batch is cut again
+	result, err = r.Cut(true)
+	require.NoError(t, err)
+	require.Len(t, result.Operations, 3)
+	require.Equal(t, operation3, result.Operations[2])
+	require.Equal(t, uint(1), result.Skipped)
+
+	require.Equal(t, uint(2), result.Ack())
+
+	// The skipped operation is at the head of the queue
+	result, err = r.Cut(true)
+	require.NoError(t, err)
+	require.Len(t, result.Operations, 1)
+	require.Equal(t, operation1Update, result.Operations[0])
+	require.Equal(t, uint(1), result.Skipped)
+	require.Equal(t, uint(1), result.Pending)
+
+	require.Equal(t, uint(1), result.Ack())
+
+	result, err = r.Cut(true)
+	require.NoError(t, err)
+	require.Len(t, result.Operations, 1)
+	require.Equal(t, operation1Recover, result.Operations[0])
+	require.Zero(t, result.Skipped)
+	require.Zero(t, result.Pending)
+
+	require.Zero(t, result.Ack())
+}
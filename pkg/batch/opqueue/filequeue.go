@@ -0,0 +1,501 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package opqueue
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	"github.com/pkg/errors"
+	"github.com/trustbloc/edge-core/pkg/log"
+
+	"github.com/trustbloc/sidetree-core-go/pkg/api/operation"
+)
+
+var logger = log.New("sidetree-core-opqueue")
+
+const (
+	recordTypeAdd = "add"
+	recordTypeAck = "ack"
+
+	defaultCompactionThreshold = 1000
+)
+
+// logRecord is a single entry in the queue log. An 'add' record holds a queued operation and an 'ack' record
+// holds the sequence numbers of operations that were committed (removed) from the queue.
+type logRecord struct {
+	Type                string   `json:"type"`
+	Seq                 uint64   `json:"seq,omitempty"`
+	Namespace           string   `json:"namespace,omitempty"`
+	UniqueSuffix        string   `json:"uniqueSuffix,omitempty"`
+	OperationBuffer     []byte   `json:"operationBuffer,omitempty"`
+	ProtocolGenesisTime uint64   `json:"protocolGenesisTime,omitempty"`
+	Acked               []uint64 `json:"acked,omitempty"`
+}
+
+type fileItem struct {
+	seq uint64
+	op  *operation.QueuedOperationAtTime
+}
+
+// FileQueueOption is a file queue option.
+type FileQueueOption func(opts *FileQueue)
+
+// WithCompactionThreshold sets the number of acknowledged operations after which the queue log is compacted
+// (i.e. rewritten with only the operations that have not been acknowledged yet).
+func WithCompactionThreshold(threshold uint) FileQueueOption {
+	return func(opts *FileQueue) {
+		opts.compactionThreshold = threshold
+	}
+}
+
+// FileQueue implements a durable operation queue that is backed by an append-only log file. Each added
+// operation is written (and synced) to the log before Add returns. Removed operations are only deleted
+// from the log when the remove is acknowledged, so operations that were removed but not acknowledged
+// (e.g. the node crashed while the batch was being anchored) are recovered when the queue is reopened.
+type FileQueue struct {
+	path                string
+	file                *os.File
+	items               []*fileItem
+	inFlight            map[uint64]*fileItem
+	nextSeq             uint64
+	ackedSinceCompact   uint
+	compactionThreshold uint
+	mutex               sync.RWMutex
+}
+
+// NewFileQueue opens (or creates) the queue log at the given path and loads all operations that
+// have not been acknowledged.
+func NewFileQueue(path string, opts ...FileQueueOption) (*FileQueue, error) {
+	q := &FileQueue{
+		path:                path,
+		inFlight:            make(map[uint64]*fileItem),
+		nextSeq:             1,
+		compactionThreshold: defaultCompactionThreshold,
+	}
+
+	// apply options
+	for _, opt := range opts {
+		opt(q)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return nil, fmt.Errorf("create queue directory: %w", err)
+	}
+
+	file, err := os.OpenFile(filepath.Clean(path), os.O_RDWR|os.O_CREATE, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("open queue file[%s]: %w", path, err)
+	}
+
+	q.file = file
+
+	if err := q.load(); err != nil {
+		_ = file.Close() //nolint:errcheck
+
+		return nil, err
+	}
+
+	logger.Infof("Loaded %d pending operations from queue file [%s]", len(q.items), path)
+
+	return q, nil
+}
+
+// Add adds the given data to the tail of the queue and returns the new length of the queue.
+// The operation is persisted before this function returns.
+func (q *FileQueue) Add(data *operation.QueuedOperation, protocolGenesisTime uint64) (uint, error) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	if q.file == nil {
+		return 0, errors.New("operation queue is closed")
+	}
+
+	item := &fileItem{
+		seq: q.nextSeq,
+		op: &operation.QueuedOperationAtTime{
+			QueuedOperation:     *data,
+			ProtocolGenesisTime: protocolGenesisTime,
+		},
+	}
+
+	if err := q.append(newAddRecord(item)); err != nil {
+		return 0, fmt.Errorf("persist queued operation: %w", err)
+	}
+
+	q.nextSeq++
+	q.items = append(q.items, item)
+
+	return uint(len(q.items)), nil
+}
+
+// Peek returns (up to) the given number of operations from the head of the queue but does not remove them.
+func (q *FileQueue) Peek(num uint) (operation.QueuedOperationsAtTime, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	return toOperations(q.items[0:q.count(num)]), nil
+}
+
+// Remove removes (up to) the given number of items from the head of the queue. The items at the given
+// 'skip' positions remain in the queue. The operations are only removed from the queue log when 'ack'
+// is called. If 'nack' is called then the operations are placed back in the queue (at their original positions).
+func (q *FileQueue) Remove(num uint, skip ...uint) (ops operation.QueuedOperationsAtTime, ack func() uint, nack func(), err error) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	if q.file == nil {
+		return nil, nil, nil, errors.New("operation queue is closed")
+	}
+
+	n := q.count(num)
+
+	skipped := toPositions(skip)
+
+	var items, kept []*fileItem
+
+	for i, item := range q.items[0:n] {
+		if skipped[i] {
+			kept = append(kept, item)
+		} else {
+			items = append(items, item)
+		}
+	}
+
+	q.items = append(kept, q.items[n:]...)
+
+	for _, item := range items {
+		q.inFlight[item.seq] = item
+	}
+
+	return toOperations(items),
+		func() uint {
+			return q.ack(items)
+		},
+		func() {
+			q.nack(items)
+		}, nil
+}
+
+// Len returns the length of the queue.
+func (q *FileQueue) Len() uint {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	return uint(len(q.items))
+}
+
+// Close closes the queue log. Operations that have been removed but not acknowledged
+// will be available again when the queue is reopened.
+func (q *FileQueue) Close() error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	if q.file == nil {
+		return nil
+	}
+
+	err := q.file.Close()
+	q.file = nil
+
+	return err
+}
+
+func (q *FileQueue) ack(items []*fileItem) uint {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	var seqs []uint64
+
+	for _, item := range items {
+		if _, ok := q.inFlight[item.seq]; !ok {
+			continue
+		}
+
+		delete(q.inFlight, item.seq)
+
+		seqs = append(seqs, item.seq)
+	}
+
+	if len(seqs) == 0 || q.file == nil {
+		return uint(len(q.items))
+	}
+
+	if err := q.append(&logRecord{Type: recordTypeAck, Acked: seqs}); err != nil {
+		// The operations will be processed again after restart. Since operations are
+		// idempotent this results in duplicate operations being anchored but not in lost operations.
+		logger.Errorf("Error persisting acknowledgement of %d operations: %s", len(seqs), err)
+
+		return uint(len(q.items))
+	}
+
+	q.ackedSinceCompact += uint(len(seqs))
+
+	if q.ackedSinceCompact >= q.compactionThreshold {
+		if err := q.compact(); err != nil {
+			logger.Warnf("Error compacting queue file [%s]: %s", q.path, err)
+		}
+	}
+
+	return uint(len(q.items))
+}
+
+func (q *FileQueue) nack(items []*fileItem) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	var restored []*fileItem
+
+	for _, item := range items {
+		if _, ok := q.inFlight[item.seq]; !ok {
+			continue
+		}
+
+		delete(q.inFlight, item.seq)
+
+		restored = append(restored, item)
+	}
+
+	// Add the items to the head of the queue. Since sequence numbers reflect the order in which operations were
+	// added, sorting restores the original order of the items and any skipped items that are still in the queue.
+	q.items = append(restored, q.items...)
+
+	sort.SliceStable(q.items, func(i, j int) bool {
+		return q.items[i].seq < q.items[j].seq
+	})
+}
+
+func (q *FileQueue) count(num uint) int {
+	n := int(num)
+	if len(q.items) < n {
+		n = len(q.items)
+	}
+
+	return n
+}
+
+func (q *FileQueue) append(record *logRecord) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+
+	offset, err := q.file.Seek(0, io.SeekEnd)
+	if err != nil {
+		return err
+	}
+
+	if _, err := q.file.Write(append(data, '\n')); err != nil {
+		// Discard the partially written record so that subsequent records remain readable.
+		if e := q.file.Truncate(offset); e != nil {
+			logger.Errorf("Error truncating queue file [%s] to offset %d: %s", q.path, offset, e)
+		}
+
+		return err
+	}
+
+	return q.file.Sync()
+}
+
+// load reads the queue log and rebuilds the queue. A partially written record at the end of the log
+// (e.g. the node crashed during a write) is discarded.
+func (q *FileQueue) load() error {
+	pending := make(map[uint64]*fileItem)
+
+	reader := bufio.NewReader(q.file)
+
+	var offset int64
+
+	for {
+		line, err := reader.ReadBytes('\n')
+		if err != nil && err != io.EOF {
+			return fmt.Errorf("read queue file[%s]: %w", q.path, err)
+		}
+
+		if err == io.EOF {
+			if len(bytes.TrimSpace(line)) > 0 {
+				logger.Warnf("Discarding incomplete record at offset %d of queue file [%s]", offset, q.path)
+			}
+
+			break
+		}
+
+		if e := q.applyRecord(line, pending); e != nil {
+			return fmt.Errorf("queue file[%s] is corrupted at offset %d: %w", q.path, offset, e)
+		}
+
+		offset += int64(len(line))
+	}
+
+	if err := q.file.Truncate(offset); err != nil {
+		return fmt.Errorf("truncate queue file[%s]: %w", q.path, err)
+	}
+
+	if _, err := q.file.Seek(offset, io.SeekStart); err != nil {
+		return fmt.Errorf("seek queue file[%s]: %w", q.path, err)
+	}
+
+	for _, item := range pending {
+		q.items = append(q.items, item)
+	}
+
+	sort.Slice(q.items, func(i, j int) bool {
+		return q.items[i].seq < q.items[j].seq
+	})
+
+	return nil
+}
+
+func (q *FileQueue) applyRecord(line []byte, pending map[uint64]*fileItem) error {
+	record := &logRecord{}
+
+	if err := json.Unmarshal(line, record); err != nil {
+		return err
+	}
+
+	switch record.Type {
+	case recordTypeAdd:
+		pending[record.Seq] = &fileItem{
+			seq: record.Seq,
+			op: &operation.QueuedOperationAtTime{
+				QueuedOperation: operation.QueuedOperation{
+					OperationBuffer: record.OperationBuffer,
+					UniqueSuffix:    record.UniqueSuffix,
+					Namespace:       record.Namespace,
+				},
+				ProtocolGenesisTime: record.ProtocolGenesisTime,
+			},
+		}
+
+		if record.Seq >= q.nextSeq {
+			q.nextSeq = record.Seq + 1
+		}
+	case recordTypeAck:
+		for _, seq := range record.Acked {
+			delete(pending, seq)
+		}
+	default:
+		return fmt.Errorf("invalid record type [%s]", record.Type)
+	}
+
+	return nil
+}
+
+// compact rewrites the queue log so that it only contains operations that have not been acknowledged
+// (including operations that are currently in flight). The new log is written to a temporary file
+// which then atomically replaces the existing log. The temporary file is kept open so that the queue
+// either continues to use the existing log (if the log can't be replaced) or the new log.
+func (q *FileQueue) compact() error {
+	items := make([]*fileItem, 0, len(q.items)+len(q.inFlight))
+
+	for _, item := range q.inFlight {
+		items = append(items, item)
+	}
+
+	items = append(items, q.items...)
+
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].seq < items[j].seq
+	})
+
+	tmpPath := q.path + ".tmp"
+
+	file, err := writeLog(tmpPath, items)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Rename(tmpPath, q.path); err != nil {
+		discardFile(file)
+
+		return fmt.Errorf("replace queue file: %w", err)
+	}
+
+	_ = q.file.Close() //nolint:errcheck
+
+	q.file = file
+	q.ackedSinceCompact = 0
+
+	logger.Debugf("Compacted queue file [%s]. Operations in log: %d", q.path, len(items))
+
+	return nil
+}
+
+// writeLog writes the given items to a new log file at the given path. The file is returned open and
+// positioned at the end of the log.
+func writeLog(path string, items []*fileItem) (*os.File, error) {
+	file, err := os.OpenFile(filepath.Clean(path), os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("create queue file: %w", err)
+	}
+
+	if err := writeItems(file, items); err != nil {
+		discardFile(file)
+
+		return nil, fmt.Errorf("write queue file: %w", err)
+	}
+
+	return file, nil
+}
+
+func writeItems(file *os.File, items []*fileItem) error {
+	w := bufio.NewWriter(file)
+
+	for _, item := range items {
+		data, err := json.Marshal(newAddRecord(item))
+		if err != nil {
+			return err
+		}
+
+		if _, err := w.Write(append(data, '\n')); err != nil {
+			return err
+		}
+	}
+
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	return file.Sync()
+}
+
+// discardFile closes and removes the given file.
+func discardFile(file *os.File) {
+	_ = file.Close() //nolint:errcheck
+
+	if err := os.Remove(file.Name()); err != nil {
+		logger.Warnf("Error removing file [%s]: %s", file.Name(), err)
+	}
+}
+
+func newAddRecord(item *fileItem) *logRecord {
+	return &logRecord{
+		Type:                recordTypeAdd,
+		Seq:                 item.seq,
+		Namespace:           item.op.Namespace,
+		UniqueSuffix:        item.op.UniqueSuffix,
+		OperationBuffer:     item.op.OperationBuffer,
+		ProtocolGenesisTime: item.op.ProtocolGenesisTime,
+	}
+}
+
+func toOperations(items []*fileItem) operation.QueuedOperationsAtTime {
+	ops := make(operation.QueuedOperationsAtTime, len(items))
+
+	for i, item := range items {
+		ops[i] = item.op
+	}
+
+	return ops
+}
@@ -0,0 +1,395 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package opqueue
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/sidetree-core-go/pkg/api/operation"
+)
+
+func TestFileQueue(t *testing.T) {
+	path := newQueuePath(t)
+
+	q, err := NewFileQueue(path)
+	require.NoError(t, err)
+
+	defer func() {
+		require.NoError(t, q.Close())
+	}()
+
+	require.Zero(t, q.Len())
+
+	ops, err := q.Peek(1)
+	require.NoError(t, err)
+	require.Empty(t, ops)
+
+	l, err := q.Add(op1, 10)
+	require.NoError(t, err)
+	require.Equal(t, uint(1), l)
+
+	l, err = q.Add(op2, 10)
+	require.NoError(t, err)
+	require.Equal(t, uint(2), l)
+
+	l, err = q.Add(op3, 20)
+	require.NoError(t, err)
+	require.Equal(t, uint(3), l)
+	require.Equal(t, uint(3), q.Len())
+
+	ops, err = q.Peek(4)
+	require.NoError(t, err)
+	require.Len(t, ops, 3)
+	require.Equal(t, *op1, ops[0].QueuedOperation)
+	require.Equal(t, *op2, ops[1].QueuedOperation)
+	require.Equal(t, *op3, ops[2].QueuedOperation)
+	require.Equal(t, uint64(20), ops[2].ProtocolGenesisTime)
+
+	ops, ack, _, err := q.Remove(1)
+	require.NoError(t, err)
+	require.Len(t, ops, 1)
+	require.Equal(t, *op1, ops[0].QueuedOperation)
+	require.Equal(t, uint(2), ack())
+
+	// ack is idempotent
+	require.Equal(t, uint(2), ack())
+
+	ops, _, nack, err := q.Remove(5)
+	require.NoError(t, err)
+	require.Len(t, ops, 2)
+	require.Zero(t, q.Len())
+
+	nack()
+
+	require.Equal(t, uint(2), q.Len())
+
+	ops, ack, _, err = q.Remove(5)
+	require.NoError(t, err)
+	require.Len(t, ops, 2)
+	require.Equal(t, *op2, ops[0].QueuedOperation)
+	require.Equal(t, *op3, ops[1].QueuedOperation)
+	require.Zero(t, ack())
+}
+
+func TestFileQueue_RemoveWithSkip(t *testing.T) {
+	path := newQueuePath(t)
+
+	q, err := NewFileQueue(path)
+	require.NoError(t, err)
+
+	for _, op := range []*operation.QueuedOperation{op1, op2, op3} {
+		_, err = q.Add(op, 10)
+		require.NoError(t, err)
+	}
+
+	ops, _, nack, err := q.Remove(3, 1)
+	require.NoError(t, err)
+	require.Len(t, ops, 2)
+	require.Equal(t, *op1, ops[0].QueuedOperation)
+	require.Equal(t, *op3, ops[1].QueuedOperation)
+	require.Equal(t, uint(1), q.Len())
+
+	// the removed operations are placed back at their original positions
+	nack()
+
+	ops, err = q.Peek(3)
+	require.NoError(t, err)
+	require.Len(t, ops, 3)
+	require.Equal(t, *op1, ops[0].QueuedOperation)
+	require.Equal(t, *op2, ops[1].QueuedOperation)
+	require.Equal(t, *op3, ops[2].QueuedOperation)
+
+	ops, ack, _, err := q.Remove(3, 1)
+	require.NoError(t, err)
+	require.Len(t, ops, 2)
+	require.Equal(t, uint(1), ack())
+
+	require.NoError(t, q.Close())
+
+	// the skipped operation is recovered
+	q, err = NewFileQueue(path)
+	require.NoError(t, err)
+
+	ops, err = q.Peek(3)
+	require.NoError(t, err)
+	require.Len(t, ops, 1)
+	require.Equal(t, *op2, ops[0].QueuedOperation)
+
+	require.NoError(t, q.Close())
+}
+
+func TestFileQueue_Recovery(t *testing.T) {
+	t.Run("pending operations are recovered", func(t *testing.T) {
+		path := newQueuePath(t)
+
+		q, err := NewFileQueue(path)
+		require.NoError(t, err)
+
+		_, err = q.Add(op1, 10)
+		require.NoError(t, err)
+		_, err = q.Add(op2, 20)
+		require.NoError(t, err)
+
+		require.NoError(t, q.Close())
+
+		q, err = NewFileQueue(path)
+		require.NoError(t, err)
+
+		ops, err := q.Peek(5)
+		require.NoError(t, err)
+		require.Len(t, ops, 2)
+		require.Equal(t, *op1, ops[0].QueuedOperation)
+		require.Equal(t, uint64(10), ops[0].ProtocolGenesisTime)
+		require.Equal(t, *op2, ops[1].QueuedOperation)
+		require.Equal(t, uint64(20), ops[1].ProtocolGenesisTime)
+
+		// New operations are added after the recovered operations
+		l, err := q.Add(op3, 20)
+		require.NoError(t, err)
+		require.Equal(t, uint(3), l)
+
+		require.NoError(t, q.Close())
+	})
+
+	t.Run("crash between remove and ack", func(t *testing.T) {
+		path := newQueuePath(t)
+
+		q, err := NewFileQueue(path)
+		require.NoError(t, err)
+
+		_, err = q.Add(op1, 10)
+		require.NoError(t, err)
+		_, err = q.Add(op2, 10)
+		require.NoError(t, err)
+		_, err = q.Add(op3, 10)
+		require.NoError(t, err)
+
+		_, ack, _, err := q.Remove(1)
+		require.NoError(t, err)
+		require.Equal(t, uint(2), ack())
+
+		ops, _, _, err := q.Remove(2)
+		require.NoError(t, err)
+		require.Len(t, ops, 2)
+
+		// Simulate crash: neither ack nor nack is called
+		require.NoError(t, q.Close())
+
+		q, err = NewFileQueue(path)
+		require.NoError(t, err)
+
+		ops, err = q.Peek(5)
+		require.NoError(t, err)
+		require.Len(t, ops, 2)
+		require.Equal(t, *op2, ops[0].QueuedOperation)
+		require.Equal(t, *op3, ops[1].QueuedOperation)
+
+		require.NoError(t, q.Close())
+	})
+
+	t.Run("incomplete record is discarded", func(t *testing.T) {
+		path := newQueuePath(t)
+
+		q, err := NewFileQueue(path)
+		require.NoError(t, err)
+
+		_, err = q.Add(op1, 10)
+		require.NoError(t, err)
+
+		require.NoError(t, q.Close())
+
+		appendToFile(t, path, `{"type":"add","seq":2,"uniqueSuffix":"op`)
+
+		q, err = NewFileQueue(path)
+		require.NoError(t, err)
+		require.Equal(t, uint(1), q.Len())
+
+		l, err := q.Add(op2, 10)
+		require.NoError(t, err)
+		require.Equal(t, uint(2), l)
+
+		require.NoError(t, q.Close())
+
+		q, err = NewFileQueue(path)
+		require.NoError(t, err)
+
+		ops, err := q.Peek(5)
+		require.NoError(t, err)
+		require.Len(t, ops, 2)
+		require.Equal(t, *op2, ops[1].QueuedOperation)
+
+		require.NoError(t, q.Close())
+	})
+
+	t.Run("error - corrupted record", func(t *testing.T) {
+		path := newQueuePath(t)
+
+		appendToFile(t, path, "{\n")
+
+		q, err := NewFileQueue(path)
+		require.Error(t, err)
+		require.Nil(t, q)
+		require.Contains(t, err.Error(), "is corrupted at offset 0")
+	})
+
+	t.Run("error - invalid record type", func(t *testing.T) {
+		path := newQueuePath(t)
+
+		appendToFile(t, path, "{\"type\":\"other\"}\n")
+
+		q, err := NewFileQueue(path)
+		require.Error(t, err)
+		require.Nil(t, q)
+		require.Contains(t, err.Error(), "invalid record type [other]")
+	})
+}
+
+func TestFileQueue_Compaction(t *testing.T) {
+	path := newQueuePath(t)
+
+	q, err := NewFileQueue(path, WithCompactionThreshold(2))
+	require.NoError(t, err)
+
+	_, err = q.Add(op1, 10)
+	require.NoError(t, err)
+	_, err = q.Add(op2, 10)
+	require.NoError(t, err)
+	_, err = q.Add(op3, 10)
+	require.NoError(t, err)
+
+	_, ack, _, err := q.Remove(1)
+	require.NoError(t, err)
+	require.Equal(t, uint(2), ack())
+
+	// op2 is in flight while the log is compacted
+	_, _, nack, err := q.Remove(1)
+	require.NoError(t, err)
+
+	sizeBefore := fileSize(t, path)
+
+	_, err = q.Add(op1, 10)
+	require.NoError(t, err)
+
+	_, ack, _, err = q.Remove(1)
+	require.NoError(t, err)
+	require.Equal(t, uint(1), ack())
+
+	require.Less(t, fileSize(t, path), sizeBefore)
+
+	nack()
+
+	ops, err := q.Peek(5)
+	require.NoError(t, err)
+	require.Len(t, ops, 2)
+	require.Equal(t, *op2, ops[0].QueuedOperation)
+	require.Equal(t, *op1, ops[1].QueuedOperation)
+
+	require.NoError(t, q.Close())
+
+	q, err = NewFileQueue(path)
+	require.NoError(t, err)
+
+	ops, err = q.Peek(5)
+	require.NoError(t, err)
+	require.Len(t, ops, 2)
+	require.Equal(t, *op2, ops[0].QueuedOperation)
+	require.Equal(t, *op1, ops[1].QueuedOperation)
+
+	require.NoError(t, q.Close())
+}
+
+func TestFileQueue_CompactionError(t *testing.T) {
+	path := newQueuePath(t)
+
+	q, err := NewFileQueue(path, WithCompactionThreshold(1))
+	require.NoError(t, err)
+
+	// the temporary log file can't be created
+	require.NoError(t, os.MkdirAll(path+".tmp", 0o700))
+
+	_, err = q.Add(op1, 10)
+	require.NoError(t, err)
+	_, err = q.Add(op2, 10)
+	require.NoError(t, err)
+
+	_, ack, _, err := q.Remove(1)
+	require.NoError(t, err)
+	require.Equal(t, uint(1), ack())
+
+	// the queue continues to use the existing log
+	_, err = q.Add(op3, 10)
+	require.NoError(t, err)
+
+	require.NoError(t, q.Close())
+
+	q, err = NewFileQueue(path)
+	require.NoError(t, err)
+
+	ops, err := q.Peek(5)
+	require.NoError(t, err)
+	require.Len(t, ops, 2)
+	require.Equal(t, *op2, ops[0].QueuedOperation)
+	require.Equal(t, *op3, ops[1].QueuedOperation)
+
+	require.NoError(t, q.Close())
+}
+
+func TestFileQueue_Closed(t *testing.T) {
+	q, err := NewFileQueue(newQueuePath(t))
+	require.NoError(t, err)
+
+	require.NoError(t, q.Close())
+	require.NoError(t, q.Close())
+
+	_, err = q.Add(op1, 10)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "operation queue is closed")
+
+	_, _, _, err = q.Remove(1)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "operation queue is closed")
+}
+
+func newQueuePath(t *testing.T) string {
+	t.Helper()
+
+	dir, err := ioutil.TempDir("", "opqueue")
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		require.NoError(t, os.RemoveAll(dir))
+	})
+
+	return filepath.Join(dir, "queue", "operations.log")
+}
+
+func appendToFile(t *testing.T, path, content string) {
+	t.Helper()
+
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o700))
+
+	f, err := os.OpenFile(filepath.Clean(path), os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o600)
+	require.NoError(t, err)
+
+	_, err = f.WriteString(content)
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+}
+
+func fileSize(t *testing.T, path string) int64 {
+	t.Helper()
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+
+	return info.Size()
+}
@@ -44,8 +44,9 @@ func (q *MemQueue) Peek(num uint) (operation.QueuedOperationsAtTime, error) {
 	return q.items[0:n], nil
 }
 
-// Remove removes (up to) the given number of items from the head of the queue.
-func (q *MemQueue) Remove(num uint) (ops operation.QueuedOperationsAtTime, ack func() uint, nack func(), err error) {
+// Remove removes (up to) the given number of items from the head of the queue. The items at the given
+// 'skip' positions remain in the queue.
+func (q *MemQueue) Remove(num uint, skip ...uint) (ops operation.QueuedOperationsAtTime, ack func() uint, nack func(), err error) {
 	q.mutex.Lock()
 	defer q.mutex.Unlock()
 
@@ -54,8 +55,21 @@ func (q *MemQueue) Remove(num uint) (ops operation.QueuedOperationsAtTime, ack f
 		n = len(q.items)
 	}
 
-	items := q.items[0:n]
-	q.items = q.items[n:]
+	window := q.items[0:n]
+
+	skipped := toPositions(skip)
+
+	var items, kept []*operation.QueuedOperationAtTime
+
+	for i, item := range window {
+		if skipped[i] {
+			kept = append(kept, item)
+		} else {
+			items = append(items, item)
+		}
+	}
+
+	q.items = append(kept, q.items[n:]...)
 
 	return items,
 		func() uint {
@@ -68,8 +82,7 @@ func (q *MemQueue) Remove(num uint) (ops operation.QueuedOperationsAtTime, ack f
 			q.mutex.Lock()
 			defer q.mutex.Unlock()
 
-			// Add the items to the head of the queue.
-			q.items = append(items, q.items...)
+			q.restore(window, items)
 		}, nil
 }
 
@@ -80,3 +93,48 @@ func (q *MemQueue) Len() uint {
 
 	return uint(len(q.items))
 }
+
+// restore places the given removed items back at the head of the queue. The skipped items (of the given window)
+// that are still in the queue are moved back to their original positions between the removed items.
+func (q *MemQueue) restore(window, removed []*operation.QueuedOperationAtTime) {
+	inQueue := make(map[*operation.QueuedOperationAtTime]bool, len(q.items))
+	for _, item := range q.items {
+		inQueue[item] = true
+	}
+
+	isRemoved := make(map[*operation.QueuedOperationAtTime]bool, len(removed))
+	for _, item := range removed {
+		isRemoved[item] = true
+	}
+
+	inWindow := make(map[*operation.QueuedOperationAtTime]bool, len(window))
+
+	var items []*operation.QueuedOperationAtTime
+
+	for _, item := range window {
+		if isRemoved[item] || inQueue[item] {
+			inWindow[item] = true
+
+			items = append(items, item)
+		}
+	}
+
+	for _, item := range q.items {
+		if !inWindow[item] {
+			items = append(items, item)
+		}
+	}
+
+	q.items = items
+}
+
+// toPositions returns the given positions as a set.
+func toPositions(positions []uint) map[int]bool {
+	set := make(map[int]bool, len(positions))
+
+	for _, pos := range positions {
+		set[int(pos)] = true
+	}
+
+	return set
+}
@@ -87,3 +87,39 @@ func TestMemQueue(t *testing.T) {
 
 	require.Zero(t, ack())
 }
+
+func TestMemQueue_RemoveWithSkip(t *testing.T) {
+	q := &MemQueue{}
+
+	for _, op := range []*operation.QueuedOperation{op1, op2, op3} {
+		_, err := q.Add(op, 10)
+		require.NoError(t, err)
+	}
+
+	ops, _, nack, err := q.Remove(3, 1)
+	require.NoError(t, err)
+	require.Len(t, ops, 2)
+	require.Equal(t, *op1, ops[0].QueuedOperation)
+	require.Equal(t, *op3, ops[1].QueuedOperation)
+	require.Equal(t, uint(1), q.Len())
+
+	// the removed operations are placed back at their original positions
+	nack()
+
+	ops, err = q.Peek(3)
+	require.NoError(t, err)
+	require.Len(t, ops, 3)
+	require.Equal(t, *op1, ops[0].QueuedOperation)
+	require.Equal(t, *op2, ops[1].QueuedOperation)
+	require.Equal(t, *op3, ops[2].QueuedOperation)
+
+	ops, ack, _, err := q.Remove(3, 1)
+	require.NoError(t, err)
+	require.Len(t, ops, 2)
+	require.Equal(t, uint(1), ack())
+
+	ops, err = q.Peek(3)
+	require.NoError(t, err)
+	require.Len(t, ops, 1)
+	require.Equal(t, *op2, ops[0].QueuedOperation)
+}
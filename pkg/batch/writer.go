@@ -53,14 +53,15 @@ type process struct {
 
 // Writer implements batch writer.
 type Writer struct {
-	namespace    string
-	context      Context
-	batchCutter  batchCutter
-	sendChan     chan process
-	exitChan     chan struct{}
-	batchTimeout time.Duration
-	stopped      uint32
-	protocol     protocol.Client
+	namespace     string
+	context       Context
+	batchCutter   batchCutter
+	sendChan      chan process
+	exitChan      chan struct{}
+	batchTimeout  time.Duration
+	stopped       uint32
+	protocol      protocol.Client
+	statusTracker OperationStatusTracker
 }
 
 // Context contains batch writer context.
@@ -81,6 +82,16 @@ type AnchorWriter interface {
 	Read(sinceTransactionNumber int) (bool, *txn.SidetreeTxn)
 }
 
+// OperationStatusTracker records the status of operations as they progress through the batch writer.
+type OperationStatusTracker interface {
+	// Queued records that the operations were added to the queue (or placed back in the queue).
+	Queued(ops []*operation.QueuedOperation) error
+	// Batched records that the operations were cut into a batch.
+	Batched(ops []*operation.QueuedOperation) error
+	// Anchored records that the operations were anchored with the given anchor string and batch files.
+	Anchored(ops []*operation.QueuedOperation, anchorString string, artifacts []*protocol.AnchorDocument) error
+}
+
 // CompressionProvider defines an interface for handling different types of compression.
 type CompressionProvider interface {
 
@@ -103,14 +114,20 @@ func New(namespace string, context Context, options ...Option) (*Writer, error)
 		batchTimeout = rOpts.BatchTimeout
 	}
 
+	var statusTracker OperationStatusTracker = &noopStatusTracker{}
+	if rOpts.StatusTracker != nil {
+		statusTracker = rOpts.StatusTracker
+	}
+
 	return &Writer{
-		namespace:    namespace,
-		batchCutter:  cutter.New(context.Protocol(), context.OperationQueue()),
-		sendChan:     make(chan process, defaultSendChannelSize),
-		exitChan:     make(chan struct{}),
-		batchTimeout: batchTimeout,
-		context:      context,
-		protocol:     context.Protocol(),
+		namespace:     namespace,
+		batchCutter:   cutter.New(context.Protocol(), context.OperationQueue()),
+		sendChan:      make(chan process, defaultSendChannelSize),
+		exitChan:      make(chan struct{}),
+		batchTimeout:  batchTimeout,
+		context:       context,
+		protocol:      context.Protocol(),
+		statusTracker: statusTracker,
 	}, nil
 }
 
@@ -150,6 +167,10 @@ func (r *Writer) Add(op *operation.QueuedOperation, protocolGenesisTime uint64)
 		return err
 	}
 
+	if err := r.statusTracker.Queued([]*operation.QueuedOperation{op}); err != nil {
+		logger.Warnf("[%s] failed to record queued status for operation: %s", op.UniqueSuffix, err)
+	}
+
 	select {
 	case r.sendChan <- process{force: false}:
 		// Send a notification that an operation was added to the queue
@@ -242,15 +263,31 @@ func (r *Writer) cutAndProcess(forceCut bool) (numProcessed int, pending uint, e
 
 	logger.Infof("[%s] processing %d batch operations for protocol genesis time[%d]...", r.namespace, len(result.Operations), result.ProtocolGenesisTime)
 
-	err = r.process(result.Operations, result.ProtocolGenesisTime)
+	if result.Skipped > 0 {
+		logger.Infof("[%s] %d operations were deferred to the next batch since the batch already contains operations for the same suffix", r.namespace, result.Skipped)
+	}
+
+	if e := r.statusTracker.Batched(result.Operations); e != nil {
+		logger.Warnf("[%s] failed to record batched status for %d operations: %s", r.namespace, len(result.Operations), e)
+	}
+
+	anchorString, artifacts, err := r.process(result.Operations, result.ProtocolGenesisTime)
 	if err != nil {
 		logger.Errorf("[%s] Error processing %d batch operations: %s", r.namespace, len(result.Operations), err)
 
 		result.Nack()
 
+		if e := r.statusTracker.Queued(result.Operations); e != nil {
+			logger.Warnf("[%s] failed to record queued status for %d operations: %s", r.namespace, len(result.Operations), e)
+		}
+
 		return 0, result.Pending + uint(len(result.Operations)), err
 	}
 
+	if e := r.statusTracker.Anchored(result.Operations, anchorString, artifacts); e != nil {
+		logger.Warnf("[%s] failed to record anchored status for %d operations: %s", r.namespace, len(result.Operations), e)
+	}
+
 	logger.Infof("[%s] Successfully processed %d batch operations. Committing to batch cutter ...", r.namespace, len(result.Operations))
 
 	pending = result.Ack()
@@ -260,25 +297,30 @@ func (r *Writer) cutAndProcess(forceCut bool) (numProcessed int, pending uint, e
 	return len(result.Operations), pending, nil
 }
 
-func (r *Writer) process(ops []*operation.QueuedOperation, protocolGenesisTime uint64) error {
+func (r *Writer) process(ops []*operation.QueuedOperation, protocolGenesisTime uint64) (string, []*protocol.AnchorDocument, error) {
 	if len(ops) == 0 {
-		return errors.New("create batch called with no pending operations, should not happen")
+		return "", nil, errors.New("create batch called with no pending operations, should not happen")
 	}
 
 	p, err := r.protocol.Get(protocolGenesisTime)
 	if err != nil {
-		return err
+		return "", nil, err
 	}
 
 	anchorString, artifacts, dids, err := p.OperationHandler().PrepareTxnFiles(ops)
 	if err != nil {
-		return err
+		return "", nil, err
 	}
 
 	logger.Infof("[%s] writing anchor string: %s", r.namespace, anchorString)
 
 	// Create Sidetree transaction in anchoring system (write anchor string)
-	return r.context.Anchor().WriteAnchor(anchorString, artifacts, dids, protocolGenesisTime)
+	err = r.context.Anchor().WriteAnchor(anchorString, artifacts, dids, protocolGenesisTime)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return anchorString, artifacts, nil
 }
 
 // WithBatchTimeout allows for specifying batch timeout.
@@ -290,9 +332,19 @@ func WithBatchTimeout(batchTimeout time.Duration) Option {
 	}
 }
 
+// WithOperationStatusTracker sets the tracker that records queued, batched and anchored operation status.
+func WithOperationStatusTracker(tracker OperationStatusTracker) Option {
+	return func(o *Options) error {
+		o.StatusTracker = tracker
+
+		return nil
+	}
+}
+
 // Options allows the user to specify more advanced options.
 type Options struct {
-	BatchTimeout time.Duration
+	BatchTimeout  time.Duration
+	StatusTracker OperationStatusTracker
 }
 
 // prepareOptsFromOptions reads options.
@@ -307,3 +359,17 @@ func prepareOptsFromOptions(options ...Option) (Options, error) {
 
 	return rOpts, nil
 }
+
+type noopStatusTracker struct{}
+
+func (t *noopStatusTracker) Queued([]*operation.QueuedOperation) error {
+	return nil
+}
+
+func (t *noopStatusTracker) Batched([]*operation.QueuedOperation) error {
+	return nil
+}
+
+func (t *noopStatusTracker) Anchored([]*operation.QueuedOperation, string, []*protocol.AnchorDocument) error {
+	return nil
+}
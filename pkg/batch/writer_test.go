@@ -24,6 +24,7 @@ import (
 	"github.com/trustbloc/sidetree-core-go/pkg/compression"
 	"github.com/trustbloc/sidetree-core-go/pkg/jws"
 	"github.com/trustbloc/sidetree-core-go/pkg/mocks"
+	"github.com/trustbloc/sidetree-core-go/pkg/opstatus"
 	"github.com/trustbloc/sidetree-core-go/pkg/versions/1_0/client"
 	"github.com/trustbloc/sidetree-core-go/pkg/versions/1_0/doccomposer"
 	"github.com/trustbloc/sidetree-core-go/pkg/versions/1_0/operationapplier"
@@ -186,7 +187,7 @@ func TestBatchTimer(t *testing.T) {
 	require.Equal(t, 1, len(cf.Deltas))
 }
 
-func TestDiscardDuplicateSuffixInBatchFile(t *testing.T) {
+func TestDeferDuplicateSuffixToNextBatch(t *testing.T) {
 	ctx := newMockContext()
 	writer, err := New(namespace, ctx)
 	require.Nil(t, err)
@@ -204,25 +205,28 @@ func TestDiscardDuplicateSuffixInBatchFile(t *testing.T) {
 	err = writer.Add(op, 0)
 	require.Nil(t, err)
 
-	time.Sleep(time.Second)
+	// we should have 2 anchors since the second operation for the same suffix is deferred to the next batch
+	// (which is cut at the latest when the batch timer expires)
+	require.Eventually(t, func() bool {
+		return len(ctx.AnchorWriter.GetAnchors()) == 2
+	}, 5*time.Second, 50*time.Millisecond)
 
-	// we should have 1 anchors: 2 operations % max 2 operations per batch
-	require.Equal(t, 1, len(ctx.AnchorWriter.GetAnchors()))
+	for _, anchor := range ctx.AnchorWriter.GetAnchors() {
+		ad, err := txnprovider.ParseAnchorData(anchor)
+		require.NoError(t, err)
 
-	ad, err := txnprovider.ParseAnchorData(ctx.AnchorWriter.GetAnchors()[0])
-	require.NoError(t, err)
+		// Check that each anchor has one operation per batch
+		cif, pif, cf, err := getBatchFiles(ctx.ProtocolClient.CasClient, ad.CoreIndexFileURI)
+		require.Nil(t, err)
 
-	// Check that first anchor has one operation per batch; second one has been discarded
-	cif, pif, cf, err := getBatchFiles(ctx.ProtocolClient.CasClient, ad.CoreIndexFileURI)
-	require.Nil(t, err)
+		require.Equal(t, 1, len(cif.Operations.Create))
+		require.Equal(t, 0, len(cif.Operations.Recover))
+		require.Equal(t, 0, len(cif.Operations.Deactivate))
 
-	require.Equal(t, 1, len(cif.Operations.Create))
-	require.Equal(t, 0, len(cif.Operations.Recover))
-	require.Equal(t, 0, len(cif.Operations.Deactivate))
-
-	require.Nil(t, pif.Operations)
+		require.Nil(t, pif.Operations)
 
-	require.Equal(t, 1, len(cf.Deltas))
+		require.Equal(t, 1, len(cf.Deltas))
+	}
 }
 
 func TestProcessOperationsError(t *testing.T) {
@@ -315,6 +319,90 @@ func TestProcessBatchErrorRecovery(t *testing.T) {
 	require.Equal(t, numBatchesExpected, len(ctx.AnchorWriter.GetAnchors()))
 }
 
+func TestOperationStatusTracker(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		tracker := opstatus.New(opstatus.NewMemStore())
+
+		ctx := newMockContext()
+		writer, err := New(namespace, ctx, WithOperationStatusTracker(tracker))
+		require.Nil(t, err)
+
+		writer.Start()
+		defer writer.Stop()
+
+		ops := generateOperations(2)
+		for _, op := range ops {
+			require.NoError(t, writer.Add(op, 0))
+		}
+
+		time.Sleep(time.Second)
+
+		require.Equal(t, 1, len(ctx.AnchorWriter.GetAnchors()))
+
+		for _, op := range ops {
+			id, err := opstatus.GetOperationID(op.OperationBuffer)
+			require.NoError(t, err)
+
+			status, err := tracker.Get(id)
+			require.NoError(t, err)
+			require.Equal(t, opstatus.StatusAnchored, status.Status)
+			require.Equal(t, op.UniqueSuffix, status.UniqueSuffix)
+			require.Equal(t, ctx.AnchorWriter.GetAnchors()[0], status.AnchorString)
+			require.NotEmpty(t, status.AnchorDocuments)
+		}
+	})
+
+	t.Run("anchoring error - operations are queued again", func(t *testing.T) {
+		tracker := opstatus.New(opstatus.NewMemStore())
+
+		ctx := newMockContext()
+		ctx.ProtocolClient.CasClient.SetError(fmt.Errorf("CAS Error"))
+
+		writer, err := New(namespace, ctx, WithOperationStatusTracker(tracker))
+		require.Nil(t, err)
+
+		writer.Start()
+		defer writer.Stop()
+
+		ops := generateOperations(2)
+		for _, op := range ops {
+			require.NoError(t, writer.Add(op, 0))
+		}
+
+		time.Sleep(time.Second)
+
+		require.Empty(t, ctx.AnchorWriter.GetAnchors())
+
+		for _, op := range ops {
+			id, err := opstatus.GetOperationID(op.OperationBuffer)
+			require.NoError(t, err)
+
+			status, err := tracker.Get(id)
+			require.NoError(t, err)
+			require.Equal(t, opstatus.StatusQueued, status.Status)
+			require.Empty(t, status.AnchorString)
+		}
+	})
+
+	t.Run("tracker error - operations are still anchored", func(t *testing.T) {
+		ctx := newMockContext()
+
+		writer, err := New(namespace, ctx, WithOperationStatusTracker(&mockStatusTracker{err: errors.New("tracker error")}))
+		require.Nil(t, err)
+
+		writer.Start()
+		defer writer.Stop()
+
+		for _, op := range generateOperations(2) {
+			require.NoError(t, writer.Add(op, 0))
+		}
+
+		time.Sleep(time.Second)
+
+		require.Equal(t, 1, len(ctx.AnchorWriter.GetAnchors()))
+	})
+}
+
 func TestAddError(t *testing.T) {
 	errExpected := errors.New("injected operation queue error")
 	q := &mocks.OperationQueue{}
@@ -562,3 +650,19 @@ func newMockProtocolClient() *mocks.MockProtocolClient {
 
 	return pc
 }
+
+type mockStatusTracker struct {
+	err error
+}
+
+func (m *mockStatusTracker) Queued([]*operation.QueuedOperation) error {
+	return m.err
+}
+
+func (m *mockStatusTracker) Batched([]*operation.QueuedOperation) error {
+	return m.err
+}
+
+func (m *mockStatusTracker) Anchored([]*operation.QueuedOperation, string, []*protocol.AnchorDocument) error {
+	return m.err
+}
@@ -0,0 +1,383 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package cachedcas implements a read-through caching decorator for a content addressable storage (CAS) client.
+//
+// Content is cached in an in-memory LRU cache and (optionally) in an LRU cache on local disk. Since CAS
+// content is immutable, cached content never needs to be invalidated. Addresses are base64url encoded multihashes
+// of the content, and content that doesn't match its address is never cached. Concurrent reads of the same address
+// are deduplicated so that the content is only retrieved once from the underlying CAS.
+package cachedcas
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"sync"
+	"sync/atomic"
+
+	"github.com/trustbloc/edge-core/pkg/log"
+
+	"github.com/trustbloc/sidetree-core-go/pkg/api/cas"
+	"github.com/trustbloc/sidetree-core-go/pkg/encoder"
+	"github.com/trustbloc/sidetree-core-go/pkg/hashing"
+)
+
+var logger = log.New("sidetree-core-cachedcas")
+
+const (
+	defaultMaxMemorySize       = 32 * 1024 * 1024
+	defaultMaxObjectSize       = 10 * 1024 * 1024
+	defaultPrefetchConcurrency = 4
+)
+
+// Stats contains the cache statistics.
+type Stats struct {
+	// Hits is the number of reads that were served from the cache.
+	Hits uint64
+	// Misses is the number of reads that were served from the underlying CAS.
+	Misses uint64
+}
+
+// Option is a caching CAS client option.
+type Option func(opts *Client)
+
+// WithMaxMemorySize sets the maximum total size (in bytes) of the content that is cached in memory.
+// A size of zero disables the memory cache.
+func WithMaxMemorySize(maxSize int64) Option {
+	return func(opts *Client) {
+		opts.maxMemorySize = maxSize
+	}
+}
+
+// WithDiskCache enables caching of content in the given directory with the given maximum total size (in bytes).
+func WithDiskCache(dir string, maxSize int64) Option {
+	return func(opts *Client) {
+		opts.diskDir = dir
+		opts.maxDiskSize = maxSize
+	}
+}
+
+// WithMaxObjectSize sets the maximum size (in bytes) of an object that is read from the underlying CAS
+// if the underlying CAS supports streaming reads.
+func WithMaxObjectSize(maxSize int64) Option {
+	return func(opts *Client) {
+		opts.maxObjectSize = maxSize
+	}
+}
+
+// WithPrefetchConcurrency sets the maximum number of concurrent reads from the underlying CAS that
+// are performed by Prefetch.
+func WithPrefetchConcurrency(concurrency int) Option {
+	return func(opts *Client) {
+		opts.prefetchConcurrency = concurrency
+	}
+}
+
+// Client implements a CAS client that caches content that is read from (or written to) another CAS client.
+type Client struct {
+	client cas.Client
+
+	maxMemorySize       int64
+	maxObjectSize       int64
+	diskDir             string
+	maxDiskSize         int64
+	prefetchConcurrency int
+
+	memory      *lru
+	memoryMutex sync.Mutex
+	disk        *diskCache
+	flights     *flightGroup
+	prefetchSem chan struct{}
+
+	hits   uint64
+	misses uint64
+}
+
+// New returns a new caching CAS client that reads content from the given CAS client.
+func New(client cas.Client, opts ...Option) (*Client, error) {
+	c := &Client{
+		client:              client,
+		maxMemorySize:       defaultMaxMemorySize,
+		maxObjectSize:       defaultMaxObjectSize,
+		prefetchConcurrency: defaultPrefetchConcurrency,
+		flights:             newFlightGroup(),
+	}
+
+	// apply options
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	if c.prefetchConcurrency <= 0 {
+		return nil, fmt.Errorf("invalid prefetch concurrency %d", c.prefetchConcurrency)
+	}
+
+	c.prefetchSem = make(chan struct{}, c.prefetchConcurrency)
+
+	if c.maxMemorySize > 0 {
+		c.memory = newLRU(c.maxMemorySize, nil)
+	}
+
+	if c.diskDir != "" {
+		disk, err := newDiskCache(c.diskDir, c.maxDiskSize)
+		if err != nil {
+			return nil, err
+		}
+
+		c.disk = disk
+	}
+
+	return c, nil
+}
+
+// Read returns the content of the given address from the cache or, if the content isn't cached,
+// from the underlying CAS.
+func (c *Client) Read(address string) ([]byte, error) {
+	if content, ok := c.get(address); ok {
+		atomic.AddUint64(&c.hits, 1)
+
+		return content, nil
+	}
+
+	atomic.AddUint64(&c.misses, 1)
+
+	return c.fetch(address)
+}
+
+// Write writes the given content to the underlying CAS and adds the content to the cache.
+func (c *Client) Write(content []byte) (string, error) {
+	address, err := c.client.Write(content)
+	if err != nil {
+		return "", err
+	}
+
+	if err := verify(address, content); err != nil {
+		logger.Warnf("Not caching written content for address[%s]: %s", address, err)
+
+		return address, nil
+	}
+
+	c.put(address, content)
+
+	return address, nil
+}
+
+// Prefetch retrieves the content of the given addresses from the underlying CAS in the background
+// (in parallel, up to the prefetch concurrency) so that subsequent reads are served from the cache.
+// Errors are logged and ignored.
+func (c *Client) Prefetch(addresses ...string) {
+	for _, address := range addresses {
+		if _, ok := c.get(address); ok {
+			continue
+		}
+
+		go func(address string) {
+			c.prefetchSem <- struct{}{}
+			defer func() { <-c.prefetchSem }()
+
+			if _, err := c.fetch(address); err != nil {
+				logger.Debugf("Error prefetching content for address[%s]: %s", address, err)
+			}
+		}(address)
+	}
+}
+
+// ReadStream returns a reader for the content of the given address. The content is read in the same way as
+// by Read, i.e. concurrent reads of the same address are deduplicated and no more than the maximum object
+// size is read from the underlying CAS.
+func (c *Client) ReadStream(address string) (io.ReadCloser, error) {
+	content, err := c.Read(address)
+	if err != nil {
+		return nil, err
+	}
+
+	return ioutil.NopCloser(bytes.NewReader(content)), nil
+}
+
+// Stats returns the cache statistics.
+func (c *Client) Stats() Stats {
+	return Stats{
+		Hits:   atomic.LoadUint64(&c.hits),
+		Misses: atomic.LoadUint64(&c.misses),
+	}
+}
+
+// fetch reads the content from the underlying CAS and adds it to the cache. Concurrent fetches of the same
+// address are deduplicated.
+func (c *Client) fetch(address string) ([]byte, error) {
+	return c.flights.do(address, func() ([]byte, error) {
+		// the content may have been cached by a fetch that completed in the meantime
+		if content, ok := c.get(address); ok {
+			return content, nil
+		}
+
+		content, err := c.readFromCAS(address)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := verify(address, content); err != nil {
+			return nil, fmt.Errorf("address[%s]: %w", address, err)
+		}
+
+		c.put(address, content)
+
+		return content, nil
+	})
+}
+
+// readFromCAS reads the content from the underlying CAS. If the underlying CAS supports streaming then
+// no more than the maximum object size is read.
+func (c *Client) readFromCAS(address string) ([]byte, error) {
+	sr, ok := c.client.(cas.StreamReader)
+	if !ok {
+		return c.client.Read(address)
+	}
+
+	reader, err := sr.ReadStream(address)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		if e := reader.Close(); e != nil {
+			logger.Warnf("Error closing reader for address[%s]: %s", address, e)
+		}
+	}()
+
+	content, err := ioutil.ReadAll(io.LimitReader(reader, c.maxObjectSize+1))
+	if err != nil {
+		return nil, err
+	}
+
+	if int64(len(content)) > c.maxObjectSize {
+		return nil, fmt.Errorf("address[%s]: content size exceeded maximum size %d", address, c.maxObjectSize)
+	}
+
+	return content, nil
+}
+
+func (c *Client) get(address string) ([]byte, bool) {
+	if c.memory != nil {
+		c.memoryMutex.Lock()
+		entry, ok := c.memory.get(address)
+		c.memoryMutex.Unlock()
+
+		if ok {
+			return entry.value, true
+		}
+	}
+
+	if c.disk == nil {
+		return nil, false
+	}
+
+	content, ok := c.disk.get(address)
+	if !ok {
+		return nil, false
+	}
+
+	if err := verify(address, content); err != nil {
+		logger.Warnf("Removing cached content for address[%s] from disk: %s", address, err)
+
+		c.disk.remove(address)
+
+		return nil, false
+	}
+
+	c.putInMemory(address, content)
+
+	return content, true
+}
+
+func (c *Client) put(address string, content []byte) {
+	c.putInMemory(address, content)
+
+	if c.disk != nil {
+		if err := c.disk.put(address, content); err != nil {
+			logger.Warnf("Error caching content on disk: %s", err)
+		}
+	}
+}
+
+func (c *Client) putInMemory(address string, content []byte) {
+	if c.memory == nil {
+		return
+	}
+
+	c.memoryMutex.Lock()
+	defer c.memoryMutex.Unlock()
+
+	c.memory.add(address, content, int64(len(content)))
+}
+
+// verify verifies that the given address is the encoded multihash of the given content.
+func verify(address string, content []byte) error {
+	code, err := hashing.GetMultihashCode(address)
+	if err != nil {
+		return err
+	}
+
+	mh, err := hashing.ComputeMultihash(uint(code), content)
+	if err != nil {
+		return err
+	}
+
+	if encoder.EncodeToString(mh) != address {
+		return errors.New("hash of content doesn't match address")
+	}
+
+	return nil
+}
+
+// flightGroup deduplicates concurrent calls for the same key.
+type flightGroup struct {
+	calls map[string]*flight
+	mutex sync.Mutex
+}
+
+type flight struct {
+	wg      sync.WaitGroup
+	content []byte
+	err     error
+}
+
+func newFlightGroup() *flightGroup {
+	return &flightGroup{calls: make(map[string]*flight)}
+}
+
+// do invokes the given function for the given key. If a call for the same key is in progress then
+// do waits for that call to complete and returns its result.
+func (g *flightGroup) do(key string, fn func() ([]byte, error)) ([]byte, error) {
+	g.mutex.Lock()
+
+	if f, ok := g.calls[key]; ok {
+		g.mutex.Unlock()
+
+		f.wg.Wait()
+
+		return f.content, f.err
+	}
+
+	f := &flight{}
+	f.wg.Add(1)
+	g.calls[key] = f
+
+	g.mutex.Unlock()
+
+	f.content, f.err = fn()
+
+	g.mutex.Lock()
+	delete(g.calls, key)
+	g.mutex.Unlock()
+
+	f.wg.Done()
+
+	return f.content, f.err
+}
@@ -0,0 +1,589 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package cachedcas
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/sidetree-core-go/pkg/api/cas"
+	"github.com/trustbloc/sidetree-core-go/pkg/cas/filecas"
+	"github.com/trustbloc/sidetree-core-go/pkg/mocks"
+)
+
+func TestClient_Read(t *testing.T) {
+	casClient := newCountingCAS()
+
+	address, err := casClient.Write([]byte("content"))
+	require.NoError(t, err)
+
+	c, err := New(casClient)
+	require.NoError(t, err)
+
+	for i := 0; i < 3; i++ {
+		content, err := c.Read(address)
+		require.NoError(t, err)
+		require.Equal(t, []byte("content"), content)
+	}
+
+	require.Equal(t, 1, casClient.reads(address))
+	require.Equal(t, Stats{Hits: 2, Misses: 1}, c.Stats())
+
+	t.Run("not found", func(t *testing.T) {
+		content, err := c.Read("unknown")
+		require.Error(t, err)
+		require.Nil(t, content)
+		require.True(t, errors.Is(err, cas.ErrContentNotFound))
+
+		// errors are not cached
+		_, err = c.Read("unknown")
+		require.Error(t, err)
+		require.Equal(t, 2, casClient.reads("unknown"))
+	})
+}
+
+func TestClient_Write(t *testing.T) {
+	casClient := newCountingCAS()
+
+	c, err := New(casClient)
+	require.NoError(t, err)
+
+	address, err := c.Write([]byte("content"))
+	require.NoError(t, err)
+
+	content, err := c.Read(address)
+	require.NoError(t, err)
+	require.Equal(t, []byte("content"), content)
+
+	require.Equal(t, 0, casClient.reads(address))
+	require.Equal(t, Stats{Hits: 1}, c.Stats())
+
+	t.Run("error", func(t *testing.T) {
+		c, err := New(mocks.NewMockCasClient(errors.New("write error")))
+		require.NoError(t, err)
+
+		address, err := c.Write([]byte("content"))
+		require.EqualError(t, err, "write error")
+		require.Empty(t, address)
+	})
+}
+
+func TestClient_Eviction(t *testing.T) {
+	casClient := newCountingCAS()
+
+	address1, err := casClient.Write([]byte("content1"))
+	require.NoError(t, err)
+
+	address2, err := casClient.Write([]byte("content2"))
+	require.NoError(t, err)
+
+	c, err := New(casClient, WithMaxMemorySize(10))
+	require.NoError(t, err)
+
+	_, err = c.Read(address1)
+	require.NoError(t, err)
+
+	_, err = c.Read(address2)
+	require.NoError(t, err)
+
+	// address1 was evicted
+	_, err = c.Read(address1)
+	require.NoError(t, err)
+
+	require.Equal(t, 2, casClient.reads(address1))
+	require.Equal(t, Stats{Misses: 3}, c.Stats())
+}
+
+func TestClient_DiskCache(t *testing.T) {
+	dir := newDir(t)
+
+	casClient := newCountingCAS()
+
+	address, err := casClient.Write([]byte("content"))
+	require.NoError(t, err)
+
+	c, err := New(casClient, WithMaxMemorySize(0), WithDiskCache(dir, 1024))
+	require.NoError(t, err)
+
+	content, err := c.Read(address)
+	require.NoError(t, err)
+	require.Equal(t, []byte("content"), content)
+
+	// the disk cache survives restarts
+	c, err = New(casClient, WithDiskCache(dir, 1024))
+	require.NoError(t, err)
+
+	content, err = c.Read(address)
+	require.NoError(t, err)
+	require.Equal(t, []byte("content"), content)
+
+	// content is now also cached in memory
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, fileKey(address)), []byte("other"), 0o600))
+
+	content, err = c.Read(address)
+	require.NoError(t, err)
+	require.Equal(t, []byte("content"), content)
+
+	require.Equal(t, 1, casClient.reads(address))
+	require.Equal(t, Stats{Hits: 2}, c.Stats())
+
+	t.Run("error - invalid directory", func(t *testing.T) {
+		file := filepath.Join(newDir(t), "file")
+		require.NoError(t, ioutil.WriteFile(file, []byte("file"), 0o600))
+
+		c, err := New(casClient, WithDiskCache(file, 1024))
+		require.Error(t, err)
+		require.Nil(t, c)
+		require.Contains(t, err.Error(), "create cache directory")
+	})
+}
+
+func TestClient_ConcurrentReads(t *testing.T) {
+	casClient := newCountingCAS()
+	casClient.delay = 50 * time.Millisecond
+
+	address, err := casClient.Write([]byte("content"))
+	require.NoError(t, err)
+
+	c, err := New(casClient)
+	require.NoError(t, err)
+
+	const numReaders = 10
+
+	var wg sync.WaitGroup
+
+	errs := make(chan error, numReaders)
+
+	for i := 0; i < numReaders; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			content, err := c.Read(address)
+			if err == nil && string(content) != "content" {
+				err = fmt.Errorf("unexpected content: %s", content)
+			}
+
+			errs <- err
+		}()
+	}
+
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		require.NoError(t, err)
+	}
+
+	require.Equal(t, 1, casClient.reads(address))
+	require.Equal(t, uint64(numReaders), c.Stats().Hits+c.Stats().Misses)
+}
+
+func TestClient_StreamReader(t *testing.T) {
+	fileCAS, err := filecas.New(newDir(t))
+	require.NoError(t, err)
+
+	address, err := fileCAS.Write([]byte("content"))
+	require.NoError(t, err)
+
+	t.Run("success", func(t *testing.T) {
+		c, err := New(fileCAS)
+		require.NoError(t, err)
+
+		content, err := c.Read(address)
+		require.NoError(t, err)
+		require.Equal(t, []byte("content"), content)
+	})
+
+	t.Run("error - content exceeds maximum size", func(t *testing.T) {
+		c, err := New(fileCAS, WithMaxObjectSize(5))
+		require.NoError(t, err)
+
+		content, err := c.Read(address)
+		require.Error(t, err)
+		require.Nil(t, content)
+		require.Contains(t, err.Error(), "content size exceeded maximum size 5")
+	})
+
+	t.Run("error - not found", func(t *testing.T) {
+		other, err := mocks.NewMockCasClient(nil).Write([]byte("other"))
+		require.NoError(t, err)
+
+		c, err := New(fileCAS)
+		require.NoError(t, err)
+
+		content, err := c.Read(other)
+		require.Error(t, err)
+		require.Nil(t, content)
+		require.True(t, errors.Is(err, cas.ErrContentNotFound))
+	})
+}
+
+func TestClient_ReadStream(t *testing.T) {
+	t.Run("streaming CAS", func(t *testing.T) {
+		fileCAS, err := filecas.New(newDir(t))
+		require.NoError(t, err)
+
+		address, err := fileCAS.Write([]byte("content"))
+		require.NoError(t, err)
+
+		c, err := New(fileCAS)
+		require.NoError(t, err)
+
+		content := readStream(t, c, address)
+		require.Equal(t, []byte("content"), content)
+		require.Equal(t, Stats{Hits: 0, Misses: 1}, c.Stats())
+
+		content = readStream(t, c, address)
+		require.Equal(t, []byte("content"), content)
+		require.Equal(t, Stats{Hits: 1, Misses: 1}, c.Stats())
+
+		content, err = c.Read(address)
+		require.NoError(t, err)
+		require.Equal(t, []byte("content"), content)
+		require.Equal(t, Stats{Hits: 2, Misses: 1}, c.Stats())
+	})
+
+	t.Run("non-streaming CAS", func(t *testing.T) {
+		casClient := newCountingCAS()
+
+		address, err := casClient.Write([]byte("content"))
+		require.NoError(t, err)
+
+		c, err := New(casClient)
+		require.NoError(t, err)
+
+		for i := 0; i < 3; i++ {
+			content := readStream(t, c, address)
+			require.Equal(t, []byte("content"), content)
+		}
+
+		require.Equal(t, 1, casClient.reads(address))
+		require.Equal(t, Stats{Hits: 2, Misses: 1}, c.Stats())
+	})
+
+	t.Run("concurrent reads", func(t *testing.T) {
+		fileCAS, err := filecas.New(newDir(t))
+		require.NoError(t, err)
+
+		address, err := fileCAS.Write([]byte("content"))
+		require.NoError(t, err)
+
+		streamCAS := &countingStreamCAS{Client: fileCAS, delay: 50 * time.Millisecond}
+
+		c, err := New(streamCAS)
+		require.NoError(t, err)
+
+		const numReaders = 10
+
+		var wg sync.WaitGroup
+
+		errs := make(chan error, numReaders)
+
+		for i := 0; i < numReaders; i++ {
+			wg.Add(1)
+
+			go func() {
+				defer wg.Done()
+
+				reader, err := c.ReadStream(address)
+				if err != nil {
+					errs <- err
+
+					return
+				}
+
+				content, err := ioutil.ReadAll(reader)
+				if err == nil && string(content) != "content" {
+					err = fmt.Errorf("unexpected content: %s", content)
+				}
+
+				errs <- err
+			}()
+		}
+
+		wg.Wait()
+		close(errs)
+
+		for err := range errs {
+			require.NoError(t, err)
+		}
+
+		require.Equal(t, int32(1), atomic.LoadInt32(&streamCAS.opens))
+	})
+
+	t.Run("error - content exceeds maximum size", func(t *testing.T) {
+		fileCAS, err := filecas.New(newDir(t))
+		require.NoError(t, err)
+
+		address, err := fileCAS.Write([]byte("content"))
+		require.NoError(t, err)
+
+		c, err := New(fileCAS, WithMaxObjectSize(5))
+		require.NoError(t, err)
+
+		reader, err := c.ReadStream(address)
+		require.Error(t, err)
+		require.Nil(t, reader)
+		require.Contains(t, err.Error(), "content size exceeded maximum size 5")
+
+		_, ok := c.get(address)
+		require.False(t, ok)
+	})
+
+	t.Run("error - not found", func(t *testing.T) {
+		fileCAS, err := filecas.New(newDir(t))
+		require.NoError(t, err)
+
+		c, err := New(fileCAS)
+		require.NoError(t, err)
+
+		other, err := mocks.NewMockCasClient(nil).Write([]byte("other"))
+		require.NoError(t, err)
+
+		reader, err := c.ReadStream(other)
+		require.Error(t, err)
+		require.Nil(t, reader)
+		require.True(t, errors.Is(err, cas.ErrContentNotFound))
+
+		c, err = New(newCountingCAS())
+		require.NoError(t, err)
+
+		reader, err = c.ReadStream(other)
+		require.Error(t, err)
+		require.Nil(t, reader)
+		require.True(t, errors.Is(err, cas.ErrContentNotFound))
+	})
+}
+
+func TestClient_Prefetch(t *testing.T) {
+	casClient := newCountingCAS()
+	casClient.delay = 20 * time.Millisecond
+
+	var addresses []string
+
+	for i := 0; i < 5; i++ {
+		address, err := casClient.Write([]byte(fmt.Sprintf("content%d", i)))
+		require.NoError(t, err)
+
+		addresses = append(addresses, address)
+	}
+
+	c, err := New(casClient, WithPrefetchConcurrency(2))
+	require.NoError(t, err)
+
+	_, err = c.Read(addresses[0])
+	require.NoError(t, err)
+
+	c.Prefetch(append(addresses, "unknown")...)
+
+	// wait for the prefetched content to be cached
+	require.Eventually(t, func() bool {
+		for _, address := range addresses {
+			if _, ok := c.get(address); !ok {
+				return false
+			}
+		}
+
+		return casClient.reads("unknown") == 1
+	}, time.Second, 10*time.Millisecond)
+
+	// the content was read concurrently (up to the prefetch concurrency)
+	require.Equal(t, int32(2), casClient.maxConcurrentReads())
+
+	for i, address := range addresses {
+		content, err := c.Read(address)
+		require.NoError(t, err)
+		require.Equal(t, []byte(fmt.Sprintf("content%d", i)), content)
+		require.Equal(t, 1, casClient.reads(address))
+	}
+
+	require.Equal(t, Stats{Hits: 5, Misses: 1}, c.Stats())
+
+	t.Run("error - invalid concurrency", func(t *testing.T) {
+		c, err := New(casClient, WithPrefetchConcurrency(0))
+		require.Error(t, err)
+		require.Nil(t, c)
+		require.Contains(t, err.Error(), "invalid prefetch concurrency 0")
+	})
+}
+
+func TestClient_Verify(t *testing.T) {
+	t.Run("content that doesn't match the address isn't cached", func(t *testing.T) {
+		casClient := newCountingCAS()
+
+		address, err := casClient.Write([]byte("content"))
+		require.NoError(t, err)
+
+		casClient.content = []byte("tampered")
+
+		c, err := New(casClient)
+		require.NoError(t, err)
+
+		for i := 0; i < 2; i++ {
+			content, err := c.Read(address)
+			require.Error(t, err)
+			require.Nil(t, content)
+			require.Contains(t, err.Error(), "hash of content doesn't match address")
+		}
+
+		require.Equal(t, 2, casClient.reads(address))
+	})
+
+	t.Run("written content that doesn't match the address isn't cached", func(t *testing.T) {
+		casClient := newCountingCAS()
+		casClient.address = "other"
+
+		c, err := New(casClient)
+		require.NoError(t, err)
+
+		address, err := c.Write([]byte("content"))
+		require.NoError(t, err)
+		require.Equal(t, "other", address)
+
+		_, ok := c.get(address)
+		require.False(t, ok)
+	})
+
+	t.Run("tampered content is removed from the disk cache", func(t *testing.T) {
+		dir := newDir(t)
+
+		casClient := newCountingCAS()
+
+		address, err := casClient.Write([]byte("content"))
+		require.NoError(t, err)
+
+		c, err := New(casClient, WithMaxMemorySize(0), WithDiskCache(dir, 1024))
+		require.NoError(t, err)
+
+		_, err = c.Read(address)
+		require.NoError(t, err)
+
+		require.NoError(t, ioutil.WriteFile(filepath.Join(dir, fileKey(address)), []byte("other"), 0o600))
+
+		content, err := c.Read(address)
+		require.NoError(t, err)
+		require.Equal(t, []byte("content"), content)
+
+		require.Equal(t, 2, casClient.reads(address))
+
+		// the content was cached again
+		content, err = c.Read(address)
+		require.NoError(t, err)
+		require.Equal(t, []byte("content"), content)
+
+		require.Equal(t, 2, casClient.reads(address))
+	})
+}
+
+func readStream(t *testing.T, c *Client, address string) []byte {
+	t.Helper()
+
+	reader, err := c.ReadStream(address)
+	require.NoError(t, err)
+
+	defer func() {
+		require.NoError(t, reader.Close())
+	}()
+
+	content, err := ioutil.ReadAll(reader)
+	require.NoError(t, err)
+
+	return content
+}
+
+// countingCAS is a CAS client that counts the number of reads per address and the maximum number of
+// concurrent reads. The content and address that are returned may be overridden.
+type countingCAS struct {
+	*mocks.MockCasClient
+
+	delay      time.Duration
+	content    []byte
+	address    string
+	counts     map[string]int
+	concurrent int32
+	max        int32
+	mutex      sync.Mutex
+}
+
+func newCountingCAS() *countingCAS {
+	return &countingCAS{
+		MockCasClient: mocks.NewMockCasClient(nil),
+		counts:        make(map[string]int),
+	}
+}
+
+func (m *countingCAS) Write(content []byte) (string, error) {
+	address, err := m.MockCasClient.Write(content)
+	if err != nil || m.address == "" {
+		return address, err
+	}
+
+	return m.address, nil
+}
+
+func (m *countingCAS) Read(address string) ([]byte, error) {
+	n := atomic.AddInt32(&m.concurrent, 1)
+	defer atomic.AddInt32(&m.concurrent, -1)
+
+	for {
+		max := atomic.LoadInt32(&m.max)
+		if n <= max || atomic.CompareAndSwapInt32(&m.max, max, n) {
+			break
+		}
+	}
+
+	m.mutex.Lock()
+	m.counts[address]++
+	m.mutex.Unlock()
+
+	time.Sleep(m.delay)
+
+	content, err := m.MockCasClient.Read(address)
+	if err != nil || m.content == nil {
+		return content, err
+	}
+
+	return m.content, nil
+}
+
+func (m *countingCAS) reads(address string) int {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	return m.counts[address]
+}
+
+func (m *countingCAS) maxConcurrentReads() int32 {
+	return atomic.LoadInt32(&m.max)
+}
+
+// countingStreamCAS is a streaming CAS client that counts the number of opened streams.
+type countingStreamCAS struct {
+	*filecas.Client
+
+	delay time.Duration
+	opens int32
+}
+
+func (m *countingStreamCAS) ReadStream(address string) (io.ReadCloser, error) {
+	atomic.AddInt32(&m.opens, 1)
+
+	time.Sleep(m.delay)
+
+	return m.Client.ReadStream(address)
+}
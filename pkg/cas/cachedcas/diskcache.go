@@ -0,0 +1,186 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package cachedcas
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+const tmpFilePrefix = ".tmp-"
+
+// diskCache is an LRU cache that stores content in files in a local directory. The file name of the content
+// is the (hex encoded) SHA-256 hash of the CAS address so that arbitrary addresses may be cached.
+type diskCache struct {
+	dir   string
+	lru   *lru
+	mutex sync.Mutex
+}
+
+// newDiskCache returns a new disk cache in the given directory. Files that were cached previously are
+// loaded (in the order of their modification time) so that the cache survives restarts.
+func newDiskCache(dir string, maxSize int64) (*diskCache, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("create cache directory: %w", err)
+	}
+
+	c := &diskCache{dir: dir}
+
+	c.lru = newLRU(maxSize, c.removeFile)
+
+	if err := c.load(); err != nil {
+		return nil, fmt.Errorf("load cache directory[%s]: %w", dir, err)
+	}
+
+	return c, nil
+}
+
+// get returns the cached content for the given address.
+func (c *diskCache) get(address string) ([]byte, bool) {
+	key := fileKey(address)
+
+	c.mutex.Lock()
+	_, ok := c.lru.get(key)
+	c.mutex.Unlock()
+
+	if !ok {
+		return nil, false
+	}
+
+	content, err := ioutil.ReadFile(c.path(key))
+	if err != nil {
+		// the file may have been evicted concurrently
+		if !os.IsNotExist(err) {
+			logger.Warnf("Error reading cached content for address[%s]: %s", address, err)
+		}
+
+		c.mutex.Lock()
+		c.lru.remove(key)
+		c.mutex.Unlock()
+
+		return nil, false
+	}
+
+	return content, true
+}
+
+// put stores the given content.
+func (c *diskCache) put(address string, content []byte) error {
+	key := fileKey(address)
+	size := int64(len(content))
+
+	c.mutex.Lock()
+	exists := c.lru.contains(key)
+	c.mutex.Unlock()
+
+	if exists || size > c.lru.maxSize {
+		return nil
+	}
+
+	if err := writeFile(c.dir, c.path(key), content); err != nil {
+		return fmt.Errorf("write cached content for address[%s]: %w", address, err)
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.lru.add(key, nil, size)
+
+	return nil
+}
+
+// remove removes the cached content for the given address.
+func (c *diskCache) remove(address string) {
+	key := fileKey(address)
+
+	c.mutex.Lock()
+	c.lru.remove(key)
+	c.mutex.Unlock()
+
+	c.removeFile(key)
+}
+
+func (c *diskCache) load() error {
+	infos, err := ioutil.ReadDir(c.dir)
+	if err != nil {
+		return err
+	}
+
+	// least recently written files are added first so that they're evicted first
+	sort.SliceStable(infos, func(i, j int) bool {
+		return infos[i].ModTime().Before(infos[j].ModTime())
+	})
+
+	for _, info := range infos {
+		if info.IsDir() {
+			continue
+		}
+
+		if strings.HasPrefix(info.Name(), tmpFilePrefix) {
+			c.removeFile(info.Name())
+
+			continue
+		}
+
+		c.lru.add(info.Name(), nil, info.Size())
+	}
+
+	logger.Debugf("Loaded %d cached files from [%s]", c.lru.len(), c.dir)
+
+	return nil
+}
+
+func (c *diskCache) path(key string) string {
+	return filepath.Join(c.dir, key)
+}
+
+func (c *diskCache) removeFile(key string) {
+	if err := os.Remove(c.path(key)); err != nil && !os.IsNotExist(err) {
+		logger.Warnf("Error removing cached file [%s]: %s", key, err)
+	}
+}
+
+func fileKey(address string) string {
+	hash := sha256.Sum256([]byte(address))
+
+	return hex.EncodeToString(hash[:])
+}
+
+// writeFile atomically writes the given content by writing it to a temporary file which is then renamed.
+func writeFile(dir, path string, content []byte) error {
+	tmp, err := ioutil.TempFile(dir, tmpFilePrefix)
+	if err != nil {
+		return err
+	}
+
+	_, err = tmp.Write(content)
+
+	if e := tmp.Close(); err == nil {
+		err = e
+	}
+
+	if err == nil {
+		err = os.Rename(tmp.Name(), path)
+	}
+
+	if err != nil {
+		if e := os.Remove(tmp.Name()); e != nil {
+			logger.Warnf("Error removing temporary file [%s]: %s", tmp.Name(), e)
+		}
+
+		return err
+	}
+
+	return nil
+}
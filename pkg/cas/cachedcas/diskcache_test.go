@@ -0,0 +1,150 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package cachedcas
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiskCache(t *testing.T) {
+	dir := newDir(t)
+
+	c, err := newDiskCache(dir, 10)
+	require.NoError(t, err)
+
+	content, ok := c.get("addr1")
+	require.False(t, ok)
+	require.Nil(t, content)
+
+	require.NoError(t, c.put("addr1", []byte("content1")))
+
+	content, ok = c.get("addr1")
+	require.True(t, ok)
+	require.Equal(t, []byte("content1"), content)
+
+	// content that is larger than the cache is not stored
+	require.NoError(t, c.put("large", []byte("content that is too large")))
+
+	_, ok = c.get("large")
+	require.False(t, ok)
+
+	// adding content evicts the least recently used content and removes its file
+	require.NoError(t, c.put("addr2", []byte("addr2")))
+
+	_, ok = c.get("addr1")
+	require.False(t, ok)
+
+	_, err = os.Stat(filepath.Join(dir, fileKey("addr1")))
+	require.True(t, os.IsNotExist(err))
+
+	// file names don't depend on the format of the address
+	require.NoError(t, c.put("../../etc", []byte("etc")))
+
+	content, ok = c.get("../../etc")
+	require.True(t, ok)
+	require.Equal(t, []byte("etc"), content)
+
+	files, err := ioutil.ReadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, files, 2)
+}
+
+func TestDiskCache_Load(t *testing.T) {
+	dir := newDir(t)
+
+	c, err := newDiskCache(dir, 10)
+	require.NoError(t, err)
+
+	require.NoError(t, c.put("addr1", []byte("1111")))
+	require.NoError(t, c.put("addr2", []byte("2222")))
+
+	// make sure that 'addr1' is the least recently written file
+	past := time.Now().Add(-time.Hour)
+	require.NoError(t, os.Chtimes(filepath.Join(dir, fileKey("addr1")), past, past))
+
+	// leftover temporary files are removed
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, tmpFilePrefix+"123"), []byte("tmp"), 0o600))
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "subdir"), 0o700))
+
+	c, err = newDiskCache(dir, 10)
+	require.NoError(t, err)
+	require.Equal(t, 2, c.lru.len())
+
+	content, ok := c.get("addr2")
+	require.True(t, ok)
+	require.Equal(t, []byte("2222"), content)
+
+	_, err = os.Stat(filepath.Join(dir, tmpFilePrefix+"123"))
+	require.True(t, os.IsNotExist(err))
+
+	// 'addr1' is evicted first
+	require.NoError(t, c.put("addr3", []byte("3333")))
+
+	_, ok = c.get("addr1")
+	require.False(t, ok)
+
+	_, ok = c.get("addr2")
+	require.True(t, ok)
+}
+
+func TestDiskCache_Error(t *testing.T) {
+	t.Run("error - create directory", func(t *testing.T) {
+		file := filepath.Join(newDir(t), "file")
+		require.NoError(t, ioutil.WriteFile(file, []byte("file"), 0o600))
+
+		c, err := newDiskCache(filepath.Join(file, "cache"), 10)
+		require.Error(t, err)
+		require.Nil(t, c)
+		require.Contains(t, err.Error(), "create cache directory")
+	})
+
+	t.Run("cached file was removed", func(t *testing.T) {
+		dir := newDir(t)
+
+		c, err := newDiskCache(dir, 10)
+		require.NoError(t, err)
+
+		require.NoError(t, c.put("addr1", []byte("1111")))
+		require.NoError(t, os.Remove(filepath.Join(dir, fileKey("addr1"))))
+
+		_, ok := c.get("addr1")
+		require.False(t, ok)
+		require.Equal(t, 0, c.lru.len())
+	})
+
+	t.Run("error - write file", func(t *testing.T) {
+		dir := newDir(t)
+
+		c, err := newDiskCache(dir, 10)
+		require.NoError(t, err)
+
+		require.NoError(t, os.RemoveAll(dir))
+
+		err = c.put("addr1", []byte("1111"))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "write cached content for address[addr1]")
+	})
+}
+
+func newDir(t *testing.T) string {
+	t.Helper()
+
+	dir, err := ioutil.TempDir("", "cachedcas")
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		require.NoError(t, os.RemoveAll(dir))
+	})
+
+	return dir
+}
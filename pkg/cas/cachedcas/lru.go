@@ -0,0 +1,110 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package cachedcas
+
+import (
+	"container/list"
+)
+
+// lruEntry is an entry in the LRU cache. The value is nil for entries that only track the size
+// of content that is stored elsewhere (e.g. on disk).
+type lruEntry struct {
+	key   string
+	value []byte
+	size  int64
+}
+
+// lru is a size-bounded least-recently-used cache. The LRU is not thread-safe.
+type lru struct {
+	maxSize int64
+	size    int64
+	items   map[string]*list.Element
+	order   *list.List
+	onEvict func(key string)
+}
+
+func newLRU(maxSize int64, onEvict func(key string)) *lru {
+	return &lru{
+		maxSize: maxSize,
+		items:   make(map[string]*list.Element),
+		order:   list.New(),
+		onEvict: onEvict,
+	}
+}
+
+// get returns the entry for the given key and marks it as most recently used.
+func (c *lru) get(key string) (*lruEntry, bool) {
+	e, ok := c.items[key]
+	if !ok {
+		return nil, false
+	}
+
+	c.order.MoveToFront(e)
+
+	return e.Value.(*lruEntry), true
+}
+
+// contains returns true if an entry exists for the given key (without affecting the order of entries).
+func (c *lru) contains(key string) bool {
+	_, ok := c.items[key]
+
+	return ok
+}
+
+// add adds (or replaces) an entry and evicts the least recently used entries until the cache fits into
+// its maximum size. False is returned if the entry is larger than the maximum size of the cache.
+func (c *lru) add(key string, value []byte, size int64) bool {
+	if size > c.maxSize {
+		return false
+	}
+
+	if e, ok := c.items[key]; ok {
+		entry := e.Value.(*lruEntry)
+
+		c.size += size - entry.size
+		entry.value = value
+		entry.size = size
+
+		c.order.MoveToFront(e)
+	} else {
+		c.items[key] = c.order.PushFront(&lruEntry{key: key, value: value, size: size})
+		c.size += size
+	}
+
+	for c.size > c.maxSize {
+		c.evict(c.order.Back())
+	}
+
+	return true
+}
+
+// remove removes the entry for the given key without invoking the eviction callback.
+func (c *lru) remove(key string) {
+	e, ok := c.items[key]
+	if !ok {
+		return
+	}
+
+	c.order.Remove(e)
+	delete(c.items, key)
+
+	c.size -= e.Value.(*lruEntry).size
+}
+
+func (c *lru) len() int {
+	return c.order.Len()
+}
+
+func (c *lru) evict(e *list.Element) {
+	entry := e.Value.(*lruEntry)
+
+	c.remove(entry.key)
+
+	if c.onEvict != nil {
+		c.onEvict(entry.key)
+	}
+}
@@ -0,0 +1,57 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package cachedcas
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLRU(t *testing.T) {
+	var evicted []string
+
+	c := newLRU(10, func(key string) {
+		evicted = append(evicted, key)
+	})
+
+	require.True(t, c.add("a", []byte("aaaa"), 4))
+	require.True(t, c.add("b", []byte("bbbb"), 4))
+	require.Equal(t, 2, c.len())
+
+	// 'a' becomes the most recently used entry
+	entry, ok := c.get("a")
+	require.True(t, ok)
+	require.Equal(t, []byte("aaaa"), entry.value)
+
+	// adding 'c' evicts the least recently used entry ('b')
+	require.True(t, c.add("c", []byte("cccc"), 4))
+	require.Equal(t, []string{"b"}, evicted)
+	require.Equal(t, int64(8), c.size)
+
+	_, ok = c.get("b")
+	require.False(t, ok)
+	require.True(t, c.contains("a"))
+	require.True(t, c.contains("c"))
+
+	// replacing an entry updates the size
+	require.True(t, c.add("c", []byte("cc"), 2))
+	require.Equal(t, int64(6), c.size)
+	require.Equal(t, 2, c.len())
+
+	// entries that are larger than the cache are not added
+	require.False(t, c.add("d", make([]byte, 11), 11))
+	require.False(t, c.contains("d"))
+	require.Equal(t, 2, c.len())
+
+	// removing an entry doesn't invoke the eviction callback
+	c.remove("a")
+	c.remove("x")
+	require.False(t, c.contains("a"))
+	require.Equal(t, int64(2), c.size)
+	require.Equal(t, []string{"b"}, evicted)
+}
@@ -0,0 +1,336 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package filecas implements a content addressable storage (CAS) client that stores content in a local directory.
+//
+// Content is addressed by the base64url encoded multihash (sha2-256 by default) of the content, which is the same
+// scheme that is used by the Sidetree batch writer and observer. Each object is stored in a file named after its
+// address. In order to avoid large directories, files are sharded into sub-directories that are named after the
+// first byte (hex encoded) of the content hash.
+package filecas
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/multiformats/go-multihash"
+	"github.com/trustbloc/edge-core/pkg/log"
+
+	"github.com/trustbloc/sidetree-core-go/pkg/api/cas"
+	"github.com/trustbloc/sidetree-core-go/pkg/encoder"
+	"github.com/trustbloc/sidetree-core-go/pkg/hashing"
+)
+
+var logger = log.New("sidetree-core-filecas")
+
+const (
+	sha2_256 = 18
+
+	defaultMaxObjectSize = 10 * 1024 * 1024
+)
+
+// Option is a file CAS client option.
+type Option func(opts *Client)
+
+// WithMaxObjectSize sets the maximum size (in bytes) of an object that may be written to or read from CAS.
+func WithMaxObjectSize(maxSize int64) Option {
+	return func(opts *Client) {
+		opts.maxObjectSize = maxSize
+	}
+}
+
+// WithMultihashCode sets the multihash algorithm that is used for computing the address of written content.
+func WithMultihashCode(code uint) Option {
+	return func(opts *Client) {
+		opts.multihashCode = code
+	}
+}
+
+// Client implements a CAS client that stores content in a local directory.
+type Client struct {
+	dir           string
+	maxObjectSize int64
+	multihashCode uint
+}
+
+// New returns a new file CAS client that stores content in the given directory. The directory is created
+// if it doesn't exist.
+func New(dir string, opts ...Option) (*Client, error) {
+	c := &Client{
+		dir:           dir,
+		maxObjectSize: defaultMaxObjectSize,
+		multihashCode: sha2_256,
+	}
+
+	// apply options
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	if _, err := hashing.GetHashFromMultihash(c.multihashCode); err != nil {
+		return nil, fmt.Errorf("multihash code[%d]: %w", c.multihashCode, err)
+	}
+
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("create CAS directory: %w", err)
+	}
+
+	return c, nil
+}
+
+// Write writes the given content to CAS and returns the address of the content. Writing content that
+// already exists is a no-op.
+func (c *Client) Write(content []byte) (string, error) {
+	if int64(len(content)) > c.maxObjectSize {
+		return "", fmt.Errorf("content size %d exceeded maximum size %d", len(content), c.maxObjectSize)
+	}
+
+	mh, err := hashing.ComputeMultihash(c.multihashCode, content)
+	if err != nil {
+		return "", err
+	}
+
+	address := encoder.EncodeToString(mh)
+
+	path, err := c.path(address)
+	if err != nil {
+		return "", err
+	}
+
+	if _, err := os.Stat(path); err == nil {
+		logger.Debugf("Content already exists at address[%s]", address)
+
+		return address, nil
+	}
+
+	if err := writeFile(path, content); err != nil {
+		return "", fmt.Errorf("write content for address[%s]: %w", address, err)
+	}
+
+	logger.Debugf("Wrote %d bytes to address[%s]", len(content), address)
+
+	return address, nil
+}
+
+// Read reads the content of the given address. An error is returned if the hash of the stored content doesn't
+// match the address. cas.ErrContentNotFound is returned if no content exists for the address.
+func (c *Client) Read(address string) ([]byte, error) {
+	path, err := c.path(address)
+	if err != nil {
+		return nil, err
+	}
+
+	content, err := c.readFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("address[%s]: %w", address, cas.ErrContentNotFound)
+		}
+
+		return nil, fmt.Errorf("read content for address[%s]: %w", address, err)
+	}
+
+	if err := verify(address, content); err != nil {
+		return nil, fmt.Errorf("address[%s]: %w", address, err)
+	}
+
+	return content, nil
+}
+
+// ReadStream returns a reader for the content of the given address. The hash of the content is verified
+// while reading and the reader returns an error (instead of io.EOF) if the content doesn't match the address.
+// cas.ErrContentNotFound is returned if no content exists for the address.
+func (c *Client) ReadStream(address string) (io.ReadCloser, error) {
+	path, err := c.path(address)
+	if err != nil {
+		return nil, err
+	}
+
+	code, err := hashing.GetMultihashCode(address)
+	if err != nil {
+		return nil, fmt.Errorf("address[%s]: %w", address, err)
+	}
+
+	hash, err := hashing.GetHashFromMultihash(uint(code))
+	if err != nil {
+		return nil, fmt.Errorf("address[%s]: %w", address, err)
+	}
+
+	file, err := c.openFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("address[%s]: %w", address, cas.ErrContentNotFound)
+		}
+
+		return nil, fmt.Errorf("read content for address[%s]: %w", address, err)
+	}
+
+	return &verifyingReader{
+		address: address,
+		code:    uint(code),
+		file:    file,
+		reader:  io.LimitReader(file, c.maxObjectSize+1),
+		hash:    hash.New(),
+	}, nil
+}
+
+// path returns the path of the file for the given address. An error is returned if the address is not
+// a valid multihash.
+func (c *Client) path(address string) (string, error) {
+	mhBytes, err := encoder.DecodeString(address)
+	if err != nil {
+		return "", fmt.Errorf("invalid address[%s]: %w", address, err)
+	}
+
+	// ensure that the file name only contains characters of the canonical (base64url) encoding
+	if encoder.EncodeToString(mhBytes) != address {
+		return "", fmt.Errorf("invalid address[%s]: not canonically encoded", address)
+	}
+
+	mh, err := multihash.Decode(mhBytes)
+	if err != nil {
+		return "", fmt.Errorf("invalid address[%s]: %w", address, err)
+	}
+
+	if len(mh.Digest) == 0 {
+		return "", fmt.Errorf("invalid address[%s]: empty digest", address)
+	}
+
+	return filepath.Join(c.dir, hex.EncodeToString(mh.Digest[:1]), address), nil
+}
+
+func (c *Client) readFile(path string) ([]byte, error) {
+	file, err := c.openFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		if e := file.Close(); e != nil {
+			logger.Warnf("Error closing file [%s]: %s", path, e)
+		}
+	}()
+
+	return ioutil.ReadAll(io.LimitReader(file, c.maxObjectSize+1))
+}
+
+// openFile opens the file at the given path. An error is returned if the size of the file exceeds the
+// maximum object size.
+func (c *Client) openFile(path string) (*os.File, error) {
+	file, err := os.Open(filepath.Clean(path))
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := file.Stat()
+	if err == nil && info.Size() > c.maxObjectSize {
+		err = fmt.Errorf("content size %d exceeded maximum size %d", info.Size(), c.maxObjectSize)
+	}
+
+	if err != nil {
+		if e := file.Close(); e != nil {
+			logger.Warnf("Error closing file [%s]: %s", path, e)
+		}
+
+		return nil, err
+	}
+
+	return file, nil
+}
+
+// verifyingReader computes the hash of the content while it's being read and returns an error
+// at the end of the content if the hash doesn't match the address.
+type verifyingReader struct {
+	address string
+	code    uint
+	file    *os.File
+	reader  io.Reader
+	hash    hash.Hash
+}
+
+func (r *verifyingReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+
+	r.hash.Write(p[:n]) //nolint:errcheck
+
+	if err == io.EOF {
+		mh, e := multihash.Encode(r.hash.Sum(nil), uint64(r.code))
+		if e != nil {
+			return n, fmt.Errorf("address[%s]: %w", r.address, e)
+		}
+
+		if encoder.EncodeToString(mh) != r.address {
+			return n, fmt.Errorf("address[%s]: hash of content doesn't match address", r.address)
+		}
+	}
+
+	return n, err
+}
+
+func (r *verifyingReader) Close() error {
+	return r.file.Close()
+}
+
+func verify(address string, content []byte) error {
+	code, err := hashing.GetMultihashCode(address)
+	if err != nil {
+		return err
+	}
+
+	mh, err := hashing.ComputeMultihash(uint(code), content)
+	if err != nil {
+		return err
+	}
+
+	if encoder.EncodeToString(mh) != address {
+		return errors.New("hash of content doesn't match address")
+	}
+
+	return nil
+}
+
+// writeFile atomically writes the given content to the file at the given path by writing the content
+// to a temporary file in the same directory and then renaming the temporary file.
+func writeFile(path string, content []byte) error {
+	dir := filepath.Dir(path)
+
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return err
+	}
+
+	tmp, err := ioutil.TempFile(dir, ".tmp-")
+	if err != nil {
+		return err
+	}
+
+	_, err = tmp.Write(content)
+	if err == nil {
+		err = tmp.Sync()
+	}
+
+	if e := tmp.Close(); err == nil {
+		err = e
+	}
+
+	if err == nil {
+		err = os.Rename(tmp.Name(), path)
+	}
+
+	if err != nil {
+		if e := os.Remove(tmp.Name()); e != nil {
+			logger.Warnf("Error removing temporary file [%s]: %s", tmp.Name(), e)
+		}
+
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,326 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package filecas
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/sidetree-core-go/pkg/api/cas"
+	"github.com/trustbloc/sidetree-core-go/pkg/api/operation"
+	"github.com/trustbloc/sidetree-core-go/pkg/api/txn"
+	"github.com/trustbloc/sidetree-core-go/pkg/commitment"
+	"github.com/trustbloc/sidetree-core-go/pkg/compression"
+	"github.com/trustbloc/sidetree-core-go/pkg/encoder"
+	"github.com/trustbloc/sidetree-core-go/pkg/jws"
+	"github.com/trustbloc/sidetree-core-go/pkg/mocks"
+	"github.com/trustbloc/sidetree-core-go/pkg/versions/1_0/client"
+	"github.com/trustbloc/sidetree-core-go/pkg/versions/1_0/operationparser"
+	"github.com/trustbloc/sidetree-core-go/pkg/versions/1_0/txnprovider"
+)
+
+const (
+	sha2_512  = 19
+	namespace = "did:sidetree"
+)
+
+func TestClient(t *testing.T) {
+	dir := newDir(t)
+
+	c, err := New(dir)
+	require.NoError(t, err)
+
+	content := []byte("content")
+
+	address, err := c.Write(content)
+	require.NoError(t, err)
+
+	mockAddress, err := mocks.NewMockCasClient(nil).Write(content)
+	require.NoError(t, err)
+	require.Equal(t, mockAddress, address)
+
+	read, err := c.Read(address)
+	require.NoError(t, err)
+	require.Equal(t, content, read)
+
+	// content is stored in a shard directory
+	files, err := filepath.Glob(filepath.Join(dir, "*", address))
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	require.Len(t, filepath.Base(filepath.Dir(files[0])), 2)
+
+	// writing the same content again is a no-op
+	address2, err := c.Write(content)
+	require.NoError(t, err)
+	require.Equal(t, address, address2)
+
+	// content is available to a new client
+	c, err = New(dir)
+	require.NoError(t, err)
+
+	read, err = c.Read(address)
+	require.NoError(t, err)
+	require.Equal(t, content, read)
+
+	// no temporary files are left behind
+	tmpFiles, err := filepath.Glob(filepath.Join(dir, "*", ".tmp-*"))
+	require.NoError(t, err)
+	require.Empty(t, tmpFiles)
+}
+
+func TestClient_MultihashCode(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		c, err := New(newDir(t), WithMultihashCode(sha2_512))
+		require.NoError(t, err)
+
+		address, err := c.Write([]byte("content"))
+		require.NoError(t, err)
+
+		read, err := c.Read(address)
+		require.NoError(t, err)
+		require.Equal(t, []byte("content"), read)
+	})
+
+	t.Run("error - unsupported multihash code", func(t *testing.T) {
+		c, err := New(newDir(t), WithMultihashCode(55))
+		require.Error(t, err)
+		require.Nil(t, c)
+		require.Contains(t, err.Error(), "multihash code[55]")
+	})
+}
+
+func TestClient_Read(t *testing.T) {
+	dir := newDir(t)
+
+	c, err := New(dir, WithMaxObjectSize(10))
+	require.NoError(t, err)
+
+	address, err := c.Write([]byte("content"))
+	require.NoError(t, err)
+
+	t.Run("not found", func(t *testing.T) {
+		other, err := mocks.NewMockCasClient(nil).Write([]byte("other"))
+		require.NoError(t, err)
+
+		read, err := c.Read(other)
+		require.Error(t, err)
+		require.Nil(t, read)
+		require.True(t, errors.Is(err, cas.ErrContentNotFound))
+	})
+
+	t.Run("error - invalid address", func(t *testing.T) {
+		for _, invalid := range []string{"", "../../etc", "abc", encoder.EncodeToString([]byte{18, 0})} {
+			read, err := c.Read(invalid)
+			require.Error(t, err)
+			require.Nil(t, read)
+			require.Contains(t, err.Error(), "invalid address")
+		}
+	})
+
+	t.Run("error - content doesn't match address", func(t *testing.T) {
+		path, err := c.path(address)
+		require.NoError(t, err)
+
+		require.NoError(t, ioutil.WriteFile(path, []byte("tampered"), 0o600))
+
+		read, err := c.Read(address)
+		require.Error(t, err)
+		require.Nil(t, read)
+		require.Contains(t, err.Error(), "hash of content doesn't match address")
+	})
+
+	t.Run("error - content exceeds maximum size", func(t *testing.T) {
+		path, err := c.path(address)
+		require.NoError(t, err)
+
+		require.NoError(t, ioutil.WriteFile(path, []byte("content that is too large"), 0o600))
+
+		read, err := c.Read(address)
+		require.Error(t, err)
+		require.Nil(t, read)
+		require.Contains(t, err.Error(), "content size 25 exceeded maximum size 10")
+	})
+}
+
+func TestClient_ReadStream(t *testing.T) {
+	dir := newDir(t)
+
+	c, err := New(dir, WithMaxObjectSize(10))
+	require.NoError(t, err)
+
+	address, err := c.Write([]byte("content"))
+	require.NoError(t, err)
+
+	t.Run("success", func(t *testing.T) {
+		r, err := c.ReadStream(address)
+		require.NoError(t, err)
+
+		read, err := ioutil.ReadAll(r)
+		require.NoError(t, err)
+		require.Equal(t, []byte("content"), read)
+		require.NoError(t, r.Close())
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		other, err := mocks.NewMockCasClient(nil).Write([]byte("other"))
+		require.NoError(t, err)
+
+		r, err := c.ReadStream(other)
+		require.Error(t, err)
+		require.Nil(t, r)
+		require.True(t, errors.Is(err, cas.ErrContentNotFound))
+	})
+
+	t.Run("error - invalid address", func(t *testing.T) {
+		r, err := c.ReadStream("../../etc")
+		require.Error(t, err)
+		require.Nil(t, r)
+		require.Contains(t, err.Error(), "invalid address")
+	})
+
+	t.Run("error - content doesn't match address", func(t *testing.T) {
+		path, err := c.path(address)
+		require.NoError(t, err)
+
+		require.NoError(t, ioutil.WriteFile(path, []byte("tampered"), 0o600))
+
+		r, err := c.ReadStream(address)
+		require.NoError(t, err)
+
+		read, err := ioutil.ReadAll(r)
+		require.Error(t, err)
+		require.Equal(t, []byte("tampered"), read)
+		require.Contains(t, err.Error(), "hash of content doesn't match address")
+		require.NoError(t, r.Close())
+	})
+
+	t.Run("error - content exceeds maximum size", func(t *testing.T) {
+		path, err := c.path(address)
+		require.NoError(t, err)
+
+		require.NoError(t, ioutil.WriteFile(path, []byte("content that is too large"), 0o600))
+
+		r, err := c.ReadStream(address)
+		require.Error(t, err)
+		require.Nil(t, r)
+		require.Contains(t, err.Error(), "content size 25 exceeded maximum size 10")
+	})
+}
+
+func TestClient_Write(t *testing.T) {
+	t.Run("error - content exceeds maximum size", func(t *testing.T) {
+		c, err := New(newDir(t), WithMaxObjectSize(5))
+		require.NoError(t, err)
+
+		address, err := c.Write([]byte("content"))
+		require.Error(t, err)
+		require.Empty(t, address)
+		require.Contains(t, err.Error(), "content size 7 exceeded maximum size 5")
+	})
+
+	t.Run("error - create shard directory", func(t *testing.T) {
+		dir := newDir(t)
+
+		c, err := New(dir)
+		require.NoError(t, err)
+
+		address, err := mocks.NewMockCasClient(nil).Write([]byte("content"))
+		require.NoError(t, err)
+
+		path, err := c.path(address)
+		require.NoError(t, err)
+
+		// a file with the name of the shard directory prevents the directory from being created
+		require.NoError(t, ioutil.WriteFile(filepath.Dir(path), []byte("file"), 0o600))
+
+		address, err = c.Write([]byte("content"))
+		require.Error(t, err)
+		require.Empty(t, address)
+		require.Contains(t, err.Error(), "write content for address")
+	})
+
+	t.Run("error - create directory", func(t *testing.T) {
+		dir := newDir(t)
+
+		require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "file"), []byte("file"), 0o600))
+
+		c, err := New(filepath.Join(dir, "file", "cas"))
+		require.Error(t, err)
+		require.Nil(t, c)
+		require.Contains(t, err.Error(), "create CAS directory")
+	})
+}
+
+func TestClient_BatchFiles(t *testing.T) {
+	c, err := New(newDir(t))
+	require.NoError(t, err)
+
+	p := mocks.GetDefaultProtocolParameters()
+	parser := operationparser.New(p)
+	cp := compression.New(compression.WithDefaultAlgorithms())
+
+	op, err := parser.Parse(namespace, newCreateRequest(t))
+	require.NoError(t, err)
+
+	handler := txnprovider.NewOperationHandler(p, c, cp, parser)
+
+	anchorString, _, _, err := handler.PrepareTxnFiles([]*operation.QueuedOperation{
+		{Namespace: namespace, UniqueSuffix: op.UniqueSuffix, OperationBuffer: op.OperationBuffer},
+	})
+	require.NoError(t, err)
+
+	provider := txnprovider.NewOperationProvider(p, parser, c, cp)
+
+	ops, err := provider.GetTxnOperations(context.Background(), &txn.SidetreeTxn{
+		Namespace:         namespace,
+		AnchorString:      anchorString,
+		TransactionTime:   1,
+		TransactionNumber: 1,
+	})
+	require.NoError(t, err)
+	require.Len(t, ops, 1)
+	require.Equal(t, op.UniqueSuffix, ops[0].UniqueSuffix)
+}
+
+func newCreateRequest(t *testing.T) []byte {
+	t.Helper()
+
+	updateCommitment, err := commitment.GetCommitment(&jws.JWK{Crv: "crv", Kty: "kty", X: "x"}, sha2_256)
+	require.NoError(t, err)
+
+	recoveryCommitment, err := commitment.GetCommitment(&jws.JWK{Crv: "crv", Kty: "kty", X: "x", Y: "y"}, sha2_256)
+	require.NoError(t, err)
+
+	request, err := client.NewCreateRequest(&client.CreateRequestInfo{
+		OpaqueDocument:     `{"test":1}`,
+		RecoveryCommitment: recoveryCommitment,
+		UpdateCommitment:   updateCommitment,
+		MultihashCode:      sha2_256,
+	})
+	require.NoError(t, err)
+
+	return request
+}
+
+func newDir(t *testing.T) string {
+	t.Helper()
+
+	dir, err := ioutil.TempDir("", "filecas")
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		require.NoError(t, os.RemoveAll(dir))
+	})
+
+	return dir
+}
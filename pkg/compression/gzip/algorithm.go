@@ -10,6 +10,7 @@ import (
 	"bytes"
 	"compress/gzip"
 	"fmt"
+	"io"
 	"io/ioutil"
 )
 
@@ -62,6 +63,16 @@ func (a *Algorithm) Decompress(data []byte) ([]byte, error) {
 	return bytes, nil
 }
 
+// NewReader returns a reader that decompresses the data that is read from the given reader.
+func (a *Algorithm) NewReader(r io.Reader) (io.ReadCloser, error) {
+	zr, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create new reader: %s", err.Error())
+	}
+
+	return zr, nil
+}
+
 // Accept algorithm.
 func (a *Algorithm) Accept(alg string) bool {
 	return alg == algName
@@ -7,7 +7,10 @@ SPDX-License-Identifier: Apache-2.0
 package compression
 
 import (
+	"bytes"
 	"fmt"
+	"io"
+	"io/ioutil"
 
 	"github.com/trustbloc/sidetree-core-go/pkg/compression/gzip"
 )
@@ -28,6 +31,12 @@ type Algorithm interface {
 	Close() error
 }
 
+// StreamingAlgorithm is an optional interface that is implemented by compression algorithms that
+// support streaming decompression.
+type StreamingAlgorithm interface {
+	NewReader(r io.Reader) (io.ReadCloser, error)
+}
+
 // New return new instance of compression algorithm registry.
 func New(opts ...Option) *Registry {
 	registry := &Registry{}
@@ -74,6 +83,45 @@ func (r *Registry) Decompress(alg string, data []byte) ([]byte, error) {
 	return result, nil
 }
 
+// NewDecompressionReader returns a reader that decompresses the data that is read from the given reader using
+// the specified algorithm. If the algorithm doesn't support streaming then all data is read from the given reader
+// and decompressed before the returned reader is returned.
+func (r *Registry) NewDecompressionReader(alg string, reader io.Reader) (io.ReadCloser, error) {
+	// resolve compression algorithm
+	algorithm, err := r.resolveAlgorithm(alg)
+	if err != nil {
+		return nil, err
+	}
+
+	if sa, ok := algorithm.(StreamingAlgorithm); ok {
+		dr, e := sa.NewReader(reader)
+		if e != nil {
+			return nil, fmt.Errorf("decompression failed for alg[%s]: %s", alg, e.Error())
+		}
+
+		return dr, nil
+	}
+
+	data, err := ioutil.ReadAll(reader)
+	if err != nil {
+		return nil, fmt.Errorf("read compressed data: %w", err)
+	}
+
+	result, err := algorithm.Decompress(data)
+	if err != nil {
+		return nil, fmt.Errorf("decompression failed for alg[%s]: %s", alg, err.Error())
+	}
+
+	return ioutil.NopCloser(bytes.NewReader(result)), nil
+}
+
+// IsSupported returns true if the specified compression algorithm is registered.
+func (r *Registry) IsSupported(alg string) bool {
+	_, err := r.resolveAlgorithm(alg)
+
+	return err == nil
+}
+
 // Close frees resources being maintained by compression algorithm.
 func (r *Registry) Close() error {
 	for _, v := range r.algorithms {
@@ -7,7 +7,9 @@ SPDX-License-Identifier: Apache-2.0
 package compression
 
 import (
+	"bytes"
 	"errors"
+	"io/ioutil"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -104,6 +106,77 @@ func TestRegistry_Decompress(t *testing.T) {
 	})
 }
 
+func TestRegistry_NewDecompressionReader(t *testing.T) {
+	test := []byte("hello world")
+
+	t.Run("success - streaming algorithm", func(t *testing.T) {
+		registry := New(WithAlgorithm(gzip.New()))
+
+		compressed, err := registry.Compress(algGZIP, test)
+		require.NoError(t, err)
+
+		r, err := registry.NewDecompressionReader(algGZIP, bytes.NewReader(compressed))
+		require.NoError(t, err)
+
+		data, err := ioutil.ReadAll(r)
+		require.NoError(t, err)
+		require.Equal(t, test, data)
+		require.NoError(t, r.Close())
+	})
+
+	t.Run("success - non-streaming algorithm", func(t *testing.T) {
+		registry := New(WithAlgorithm(&mockAlgorithm{}))
+
+		r, err := registry.NewDecompressionReader("mock", bytes.NewReader(test))
+		require.NoError(t, err)
+
+		data, err := ioutil.ReadAll(r)
+		require.NoError(t, err)
+		require.Equal(t, test, data)
+		require.NoError(t, r.Close())
+	})
+
+	t.Run("error - algorithm not supported", func(t *testing.T) {
+		r, err := New().NewDecompressionReader("alg", bytes.NewReader(test))
+		require.Error(t, err)
+		require.Nil(t, r)
+		require.Contains(t, err.Error(), "compression algorithm 'alg' not supported")
+	})
+
+	t.Run("error - streaming decompression error", func(t *testing.T) {
+		r, err := New(WithAlgorithm(gzip.New())).NewDecompressionReader(algGZIP, bytes.NewReader(test))
+		require.Error(t, err)
+		require.Nil(t, r)
+		require.Contains(t, err.Error(), "decompression failed for alg[GZIP]")
+	})
+
+	t.Run("error - decompression error", func(t *testing.T) {
+		registry := New(WithAlgorithm(&mockAlgorithm{DecompressErr: errors.New("test error")}))
+
+		r, err := registry.NewDecompressionReader("mock", bytes.NewReader(test))
+		require.Error(t, err)
+		require.Nil(t, r)
+		require.Contains(t, err.Error(), "test error")
+	})
+
+	t.Run("error - read error", func(t *testing.T) {
+		registry := New(WithAlgorithm(&mockAlgorithm{}))
+
+		r, err := registry.NewDecompressionReader("mock", &errReader{err: errors.New("read error")})
+		require.Error(t, err)
+		require.Nil(t, r)
+		require.Contains(t, err.Error(), "read compressed data: read error")
+	})
+}
+
+func TestRegistry_IsSupported(t *testing.T) {
+	registry := New(WithDefaultAlgorithms())
+	require.True(t, registry.IsSupported(algGZIP))
+	require.False(t, registry.IsSupported("invalid"))
+
+	require.False(t, New().IsSupported(algGZIP))
+}
+
 func TestRegistry_Close(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		registry := New(WithAlgorithm(gzip.New()), WithAlgorithm(&mockAlgorithm{}))
@@ -149,3 +222,11 @@ func (m *mockAlgorithm) Accept(alg string) bool {
 func (m *mockAlgorithm) Close() error {
 	return m.CloseErr
 }
+
+type errReader struct {
+	err error
+}
+
+func (r *errReader) Read([]byte) (int, error) {
+	return 0, r.err
+}
@@ -0,0 +1,276 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package dereferencer dereferences DID URLs.
+//
+// The DID of the DID URL is resolved (taking the 'versionId' and 'versionTime' parameters into account) and
+// the resource identified by the DID URL is selected from the resolved DID document:
+// 1) No fragment and no 'service' parameter - the DID document is returned.
+//
+// 2) Fragment - the verification method or service with the fragment as (relative) ID is returned.
+//
+// 3) 'service' parameter - the endpoint URL of the service with the given (relative) ID is returned. The optional
+// 'relativeRef' parameter is appended to the service endpoint URL and the optional fragment is added to
+// the resulting URL.
+//
+// DID URL paths are not supported. Errors match (using errors.Is) one of the resolution errors of
+// the document package.
+package dereferencer
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/trustbloc/edge-core/pkg/log"
+
+	"github.com/trustbloc/sidetree-core-go/pkg/document"
+)
+
+var logger = log.New("sidetree-core-dereferencer")
+
+const (
+	// DIDLDJSONMediaType is the media type of a DID document or of an object that was selected from a DID document.
+	DIDLDJSONMediaType = "application/did+ld+json"
+
+	// URIListMediaType is the media type of a service endpoint URL.
+	URIListMediaType = "text/uri-list"
+)
+
+// ErrNotFound is returned if the resource that is identified by a DID URL doesn't exist in the DID document.
+// It matches document.ErrNotFound when using errors.Is.
+var ErrNotFound = document.NewResolutionError(document.NotFoundErrorCode, "DID URL resource not found")
+
+// Resolver resolves DID documents.
+type Resolver interface {
+	ResolveDocument(did string, opts ...document.ResolutionOption) (*document.ResolutionResult, error)
+}
+
+// Dereferencer dereferences DID URLs.
+type Dereferencer struct {
+	resolver Resolver
+}
+
+// New returns a new DID URL dereferencer that uses the given resolver to resolve DID documents.
+func New(resolver Resolver) *Dereferencer {
+	return &Dereferencer{
+		resolver: resolver,
+	}
+}
+
+// Dereference dereferences the given DID URL. The given resolution options take precedence over
+// the options in the DID URL query.
+func (d *Dereferencer) Dereference(didURL string, opts ...document.ResolutionOption) (*document.DereferencingResult, error) {
+	u, err := ParseDIDURL(didURL)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid DID URL [%s]: %s", document.ErrInvalidDID, didURL, err.Error())
+	}
+
+	if u.Path != "" {
+		return nil, fmt.Errorf("%w: dereferencing of DID URL path [%s] is not supported", document.ErrInvalidDID, u.Path)
+	}
+
+	queryOpts, err := document.ParseResolutionOptions(u.Query)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", document.ErrInvalidOptions, err.Error())
+	}
+
+	logger.Debugf("Dereferencing DID URL [%s]", didURL)
+
+	resolutionResult, err := d.resolver.ResolveDocument(u.DID, append(queryOpts, opts...)...)
+	if err != nil {
+		return nil, err
+	}
+
+	return dereference(u, resolutionResult)
+}
+
+// dereference selects the resource that is identified by the DID URL from the resolved DID document.
+func dereference(u *DIDURL, resolutionResult *document.ResolutionResult) (*document.DereferencingResult, error) {
+	if u.Query.Get(document.RelativeRefParam) != "" && u.Query.Get(document.ServiceParam) == "" {
+		return nil, fmt.Errorf("%w: parameter '%s' requires parameter '%s'", document.ErrInvalidOptions,
+			document.RelativeRefParam, document.ServiceParam)
+	}
+
+	if u.Query.Get(document.ServiceParam) == "" && u.Fragment == "" {
+		return newResult(resolutionResult.Document.JSONLdObject(), DIDLDJSONMediaType, resolutionResult.DocumentMetadata), nil
+	}
+
+	// the resources of a deactivated document are gone
+	if deactivated, ok := resolutionResult.DocumentMetadata[document.DeactivatedProperty].(bool); ok && deactivated {
+		return nil, fmt.Errorf("%w: %s", document.ErrDeactivated, u.DID)
+	}
+
+	if u.Query.Get(document.ServiceParam) != "" {
+		return dereferenceService(u, resolutionResult)
+	}
+
+	return dereferenceFragment(u, resolutionResult)
+}
+
+// dereferenceFragment selects the verification method or service that is identified by the fragment.
+func dereferenceFragment(u *DIDURL, resolutionResult *document.ResolutionResult) (*document.DereferencingResult, error) {
+	doc := document.DidDocumentFromJSONLDObject(resolutionResult.Document.JSONLdObject())
+	dids := getDIDs(u, resolutionResult)
+
+	for _, obj := range getObjects(doc) {
+		if matches(obj, dids, u.Fragment) {
+			return newResult(obj, DIDLDJSONMediaType, resolutionResult.DocumentMetadata), nil
+		}
+	}
+
+	return nil, fmt.Errorf("verification method or service [#%s]: %w", u.Fragment, ErrNotFound)
+}
+
+// dereferenceService selects the service that is identified by the 'service' parameter and returns its
+// endpoint URL (or the service itself if its endpoint is not a URL).
+func dereferenceService(u *DIDURL, resolutionResult *document.ResolutionResult) (*document.DereferencingResult, error) {
+	doc := document.DidDocumentFromJSONLDObject(resolutionResult.Document.JSONLdObject())
+	dids := getDIDs(u, resolutionResult)
+	serviceID := u.Query.Get(document.ServiceParam)
+	relativeRef := u.Query.Get(document.RelativeRefParam)
+
+	for _, service := range doc.Services() {
+		if !matches(service, dids, serviceID) {
+			continue
+		}
+
+		endpoint, ok := service.ServiceEndpoint().(string)
+		if !ok {
+			if relativeRef != "" || u.Fragment != "" {
+				return nil, fmt.Errorf("%w: endpoint of service [#%s] is not a URL", document.ErrInvalidOptions, serviceID)
+			}
+
+			return newResult(service.JSONLdObject(), DIDLDJSONMediaType, resolutionResult.DocumentMetadata), nil
+		}
+
+		endpointURL, err := getServiceEndpointURL(endpoint, relativeRef, u.Fragment)
+		if err != nil {
+			return nil, fmt.Errorf("service [#%s]: %w", serviceID, err)
+		}
+
+		return newResult(endpointURL, URIListMediaType, resolutionResult.DocumentMetadata), nil
+	}
+
+	return nil, fmt.Errorf("service [#%s]: %w", serviceID, ErrNotFound)
+}
+
+// getServiceEndpointURL appends the relative reference to the path of the service endpoint URL
+// (e.g. 'https://example.com/files' and '/resume.pdf' result in 'https://example.com/files/resume.pdf')
+// and adds the fragment.
+func getServiceEndpointURL(endpoint, relativeRef, fragment string) (string, error) {
+	endpointURL, err := url.Parse(endpoint)
+	if err != nil {
+		return "", fmt.Errorf("invalid service endpoint: %s", err.Error())
+	}
+
+	if relativeRef != "" {
+		ref, err := url.Parse(relativeRef)
+		if err != nil {
+			return "", fmt.Errorf("%w: invalid %s: %s", document.ErrInvalidOptions, document.RelativeRefParam, err.Error())
+		}
+
+		if ref.IsAbs() || ref.Host != "" {
+			return "", fmt.Errorf("%w: %s[%s] must be a relative reference", document.ErrInvalidOptions,
+				document.RelativeRefParam, relativeRef)
+		}
+
+		if ref.Path != "" {
+			endpointURL.Path = strings.TrimSuffix(endpointURL.Path, "/") + "/" + strings.TrimPrefix(ref.Path, "/")
+			endpointURL.RawPath = ""
+		}
+
+		if ref.RawQuery != "" {
+			endpointURL.RawQuery = ref.RawQuery
+		}
+
+		if ref.Fragment != "" {
+			endpointURL.Fragment = ref.Fragment
+		}
+	}
+
+	if fragment != "" {
+		endpointURL.Fragment = fragment
+	}
+
+	return endpointURL.String(), nil
+}
+
+// getObjects returns the verification methods (including the ones that are embedded in verification
+// relationships) and the services of the given document.
+func getObjects(doc document.DIDDocument) []map[string]interface{} {
+	var objects []map[string]interface{}
+
+	for _, pk := range append(doc.VerificationMethods(), doc.PublicKeys()...) {
+		objects = append(objects, pk.JSONLdObject())
+	}
+
+	relationships := [][]interface{}{
+		doc.Authentications(), doc.AssertionMethods(), doc.AgreementKeys(), doc.DelegationKeys(), doc.InvocationKeys(),
+	}
+
+	for _, relationship := range relationships {
+		for _, entry := range relationship {
+			if obj, ok := entry.(map[string]interface{}); ok {
+				objects = append(objects, obj)
+			}
+		}
+	}
+
+	for _, service := range doc.Services() {
+		objects = append(objects, service.JSONLdObject())
+	}
+
+	return objects
+}
+
+// getDIDs returns the DIDs that may be used as the base of an absolute object ID.
+func getDIDs(u *DIDURL, resolutionResult *document.ResolutionResult) []string {
+	dids := []string{u.DID}
+
+	if id := resolutionResult.Document.ID(); id != "" && id != u.DID {
+		dids = append(dids, id)
+	}
+
+	if canonicalID, ok := resolutionResult.DocumentMetadata[document.CanonicalIDProperty].(string); ok {
+		dids = append(dids, canonicalID)
+	}
+
+	return dids
+}
+
+// matches returns true if the ID of the given object is either the relative ID '#<fragment>' or the
+// absolute ID '<did>#<fragment>' for one of the given DIDs.
+func matches(obj map[string]interface{}, dids []string, fragment string) bool {
+	id, ok := obj[document.IDProperty].(string)
+	if !ok {
+		return false
+	}
+
+	relativeID := "#" + fragment
+	if id == relativeID {
+		return true
+	}
+
+	for _, did := range dids {
+		if id == did+relativeID {
+			return true
+		}
+	}
+
+	return false
+}
+
+func newResult(content interface{}, contentType string, contentMetadata document.Metadata) *document.DereferencingResult {
+	return &document.DereferencingResult{
+		Context: document.DIDResolutionContext,
+		DereferencingMetadata: document.Metadata{
+			document.ContentTypeProperty: contentType,
+		},
+		ContentStream:   content,
+		ContentMetadata: contentMetadata,
+	}
+}
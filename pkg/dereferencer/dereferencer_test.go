@@ -0,0 +1,184 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package dereferencer
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/sidetree-core-go/pkg/document"
+)
+
+const did = "did:sidetree:abc"
+
+func TestDereference(t *testing.T) {
+	resolver := &mockResolver{result: &document.ResolutionResult{
+		Document: document.Document{
+			document.IDProperty: did,
+			document.VerificationMethodProperty: []interface{}{
+				map[string]interface{}{document.IDProperty: "#key-1", document.TypeProperty: "JsonWebKey2020"},
+			},
+			document.AuthenticationProperty: []interface{}{
+				"#key-1",
+				map[string]interface{}{document.IDProperty: did + "#auth-key", document.TypeProperty: "JsonWebKey2020"},
+			},
+			document.ServiceProperty: []interface{}{
+				map[string]interface{}{document.IDProperty: "#hub", document.ServiceEndpointProperty: "https://example.com/hub/"},
+				map[string]interface{}{
+					document.IDProperty:              did + "#agent",
+					document.ServiceEndpointProperty: map[string]interface{}{"origins": []interface{}{"https://example.com"}},
+				},
+			},
+		},
+		DocumentMetadata: document.Metadata{document.CanonicalIDProperty: "did:sidetree:canonical"},
+	}}
+
+	d := New(resolver)
+
+	t.Run("success - DID document", func(t *testing.T) {
+		result, err := d.Dereference(did + "?versionId=2")
+		require.NoError(t, err)
+		require.Equal(t, resolver.result.Document.JSONLdObject(), result.ContentStream)
+		require.Equal(t, resolver.result.DocumentMetadata, result.ContentMetadata)
+		require.Equal(t, DIDLDJSONMediaType, result.DereferencingMetadata[document.ContentTypeProperty])
+		require.Equal(t, did, resolver.did)
+		require.Equal(t, uint64(2), document.GetResolutionOptions(resolver.opts...).VersionID)
+	})
+
+	t.Run("success - options take precedence over query", func(t *testing.T) {
+		_, err := d.Dereference(did+"?versionId=2", document.WithVersionID(3))
+		require.NoError(t, err)
+		require.Equal(t, uint64(3), document.GetResolutionOptions(resolver.opts...).VersionID)
+	})
+
+	t.Run("success - verification method", func(t *testing.T) {
+		result, err := d.Dereference(did + "#key-1")
+		require.NoError(t, err)
+		require.Equal(t, "#key-1", result.ContentStream.(map[string]interface{})[document.IDProperty])
+		require.Equal(t, DIDLDJSONMediaType, result.DereferencingMetadata[document.ContentTypeProperty])
+	})
+
+	t.Run("success - embedded verification method", func(t *testing.T) {
+		result, err := d.Dereference("did:sidetree:canonical#auth-key")
+		require.NoError(t, err)
+		require.Equal(t, did+"#auth-key", result.ContentStream.(map[string]interface{})[document.IDProperty])
+	})
+
+	t.Run("success - service by fragment", func(t *testing.T) {
+		result, err := d.Dereference(did + "#agent")
+		require.NoError(t, err)
+		require.Equal(t, did+"#agent", result.ContentStream.(map[string]interface{})[document.IDProperty])
+	})
+
+	t.Run("success - service endpoint", func(t *testing.T) {
+		result, err := d.Dereference(did + "?service=hub")
+		require.NoError(t, err)
+		require.Equal(t, "https://example.com/hub/", result.ContentStream)
+		require.Equal(t, URIListMediaType, result.DereferencingMetadata[document.ContentTypeProperty])
+
+		result, err = d.Dereference(did + "?service=hub&relativeRef=%2Ffiles%2Fresume.pdf%3Fx%3D1#page-2")
+		require.NoError(t, err)
+		require.Equal(t, "https://example.com/hub/files/resume.pdf?x=1#page-2", result.ContentStream)
+	})
+
+	t.Run("success - service with non-URL endpoint", func(t *testing.T) {
+		result, err := d.Dereference(did + "?service=agent")
+		require.NoError(t, err)
+		require.Equal(t, did+"#agent", result.ContentStream.(map[string]interface{})[document.IDProperty])
+		require.Equal(t, DIDLDJSONMediaType, result.DereferencingMetadata[document.ContentTypeProperty])
+	})
+
+	t.Run("error - not found", func(t *testing.T) {
+		result, err := d.Dereference(did + "#key-2")
+		require.True(t, errors.Is(err, ErrNotFound))
+		require.True(t, errors.Is(err, document.ErrNotFound))
+		require.Nil(t, result)
+
+		result, err = d.Dereference(did + "?service=other")
+		require.True(t, errors.Is(err, ErrNotFound))
+		require.Nil(t, result)
+	})
+
+	t.Run("error - invalid DID URL or options", func(t *testing.T) {
+		tests := []struct {
+			didURL   string
+			err      error
+			expected string
+		}{
+			{didURL: "#key-1", err: document.ErrInvalidDID, expected: "invalid DID URL"},
+			{didURL: did + "/path", err: document.ErrInvalidDID, expected: "dereferencing of DID URL path [/path] is not supported"},
+			{didURL: did + "?versionId=0", err: document.ErrInvalidOptions, expected: "invalid versionId"},
+			{didURL: did + "?relativeRef=/files", err: document.ErrInvalidOptions, expected: "parameter 'relativeRef' requires parameter 'service'"},
+			{didURL: did + "?service=agent&relativeRef=/files", err: document.ErrInvalidOptions, expected: "endpoint of service [#agent] is not a URL"},
+			{didURL: did + "?service=hub&relativeRef=https%3A%2F%2Fother.com", err: document.ErrInvalidOptions, expected: "must be a relative reference"},
+		}
+
+		for _, test := range tests {
+			result, err := d.Dereference(test.didURL)
+			require.Error(t, err, test.didURL)
+			require.Nil(t, result)
+			require.True(t, errors.Is(err, test.err), test.didURL)
+			require.Contains(t, err.Error(), test.expected)
+		}
+	})
+
+	t.Run("error - deactivated", func(t *testing.T) {
+		deactivated := New(&mockResolver{result: &document.ResolutionResult{
+			Document:         document.Document{document.IDProperty: did},
+			DocumentMetadata: document.Metadata{document.DeactivatedProperty: true},
+		}})
+
+		result, err := deactivated.Dereference(did + "#key-1")
+		require.True(t, errors.Is(err, document.ErrDeactivated))
+		require.Nil(t, result)
+
+		result, err = deactivated.Dereference(did)
+		require.NoError(t, err)
+		require.Equal(t, true, result.ContentMetadata[document.DeactivatedProperty])
+	})
+
+	t.Run("error - resolver error", func(t *testing.T) {
+		result, err := New(&mockResolver{err: errors.New("injected resolver error")}).Dereference(did + "#key-1")
+		require.Error(t, err)
+		require.Nil(t, result)
+		require.Contains(t, err.Error(), "injected resolver error")
+	})
+}
+
+func TestGetServiceEndpointURL(t *testing.T) {
+	endpointURL, err := getServiceEndpointURL("https://example.com/files", "/resume.pdf", "")
+	require.NoError(t, err)
+	require.Equal(t, "https://example.com/files/resume.pdf", endpointURL)
+
+	endpointURL, err = getServiceEndpointURL("https://example.com/files?x=1", "?y=2", "frag")
+	require.NoError(t, err)
+	require.Equal(t, "https://example.com/files?y=2#frag", endpointURL)
+
+	_, err = getServiceEndpointURL("https://example.com/%zz", "", "")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "invalid service endpoint")
+
+	_, err = getServiceEndpointURL("https://example.com", "%zz", "")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "invalid relativeRef")
+}
+
+type mockResolver struct {
+	result *document.ResolutionResult
+	err    error
+	did    string
+	opts   []document.ResolutionOption
+}
+
+func (m *mockResolver) ResolveDocument(did string, opts ...document.ResolutionOption) (*document.ResolutionResult, error) {
+	m.did = did
+	m.opts = opts
+
+	return m.result, m.err
+}
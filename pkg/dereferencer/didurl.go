@@ -0,0 +1,69 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package dereferencer
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// DIDURL contains the components of a DID URL.
+type DIDURL struct {
+	// DID is the (short or long-form) DID without path, query and fragment.
+	DID string
+
+	// Path is the optional DID URL path (including the leading '/').
+	Path string
+
+	// Query contains the DID URL parameters.
+	Query url.Values
+
+	// Fragment is the optional DID URL fragment (without the leading '#').
+	Fragment string
+}
+
+// ParseDIDURL splits the given DID URL into DID, path, query and fragment.
+func ParseDIDURL(didURL string) (*DIDURL, error) {
+	result := &DIDURL{Query: make(url.Values)}
+
+	remaining := didURL
+
+	if pos := strings.Index(remaining, "#"); pos >= 0 {
+		fragment, err := url.PathUnescape(remaining[pos+1:])
+		if err != nil {
+			return nil, fmt.Errorf("invalid fragment: %s", err.Error())
+		}
+
+		result.Fragment = fragment
+		remaining = remaining[:pos]
+	}
+
+	if pos := strings.Index(remaining, "?"); pos >= 0 {
+		query, err := url.ParseQuery(remaining[pos+1:])
+		if err != nil {
+			return nil, fmt.Errorf("invalid query: %s", err.Error())
+		}
+
+		result.Query = query
+		remaining = remaining[:pos]
+	}
+
+	if pos := strings.Index(remaining, "/"); pos >= 0 {
+		result.Path = remaining[pos:]
+		remaining = remaining[:pos]
+	}
+
+	if remaining == "" {
+		return nil, errors.New("missing DID")
+	}
+
+	result.DID = remaining
+
+	return result, nil
+}
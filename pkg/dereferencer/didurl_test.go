@@ -0,0 +1,62 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package dereferencer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDIDURL(t *testing.T) {
+	t.Run("DID", func(t *testing.T) {
+		u, err := ParseDIDURL("did:sidetree:abc")
+		require.NoError(t, err)
+		require.Equal(t, "did:sidetree:abc", u.DID)
+		require.Empty(t, u.Path)
+		require.Empty(t, u.Query)
+		require.Empty(t, u.Fragment)
+	})
+
+	t.Run("long-form DID with fragment", func(t *testing.T) {
+		u, err := ParseDIDURL("did:sidetree:abc:eyJkZWx0YSI6e319#key-1")
+		require.NoError(t, err)
+		require.Equal(t, "did:sidetree:abc:eyJkZWx0YSI6e319", u.DID)
+		require.Equal(t, "key-1", u.Fragment)
+	})
+
+	t.Run("path, query and fragment", func(t *testing.T) {
+		u, err := ParseDIDURL("did:sidetree:abc/path?service=hub&relativeRef=%2Ffiles%3Fx%3D1#frag%20ment")
+		require.NoError(t, err)
+		require.Equal(t, "did:sidetree:abc", u.DID)
+		require.Equal(t, "/path", u.Path)
+		require.Equal(t, "hub", u.Query.Get("service"))
+		require.Equal(t, "/files?x=1", u.Query.Get("relativeRef"))
+		require.Equal(t, "frag ment", u.Fragment)
+	})
+
+	t.Run("error - missing DID", func(t *testing.T) {
+		u, err := ParseDIDURL("#key-1")
+		require.Error(t, err)
+		require.Nil(t, u)
+		require.Contains(t, err.Error(), "missing DID")
+	})
+
+	t.Run("error - invalid query", func(t *testing.T) {
+		u, err := ParseDIDURL("did:sidetree:abc?service=%zz")
+		require.Error(t, err)
+		require.Nil(t, u)
+		require.Contains(t, err.Error(), "invalid query")
+	})
+
+	t.Run("error - invalid fragment", func(t *testing.T) {
+		u, err := ParseDIDURL("did:sidetree:abc#%zz")
+		require.Error(t, err)
+		require.Nil(t, u)
+		require.Contains(t, err.Error(), "invalid fragment")
+	})
+}
@@ -21,7 +21,9 @@ package dochandler
 import (
 	"errors"
 	"fmt"
+	"net/url"
 	"strings"
+	"sync"
 
 	"github.com/trustbloc/edge-core/pkg/log"
 
@@ -37,7 +39,7 @@ var logger = log.New("sidetree-core-dochandler")
 const (
 	keyID = "id"
 
-	badRequest = "bad request"
+	defaultMaxConcurrentResolutions = 10
 )
 
 // DocumentHandler implements document handler.
@@ -49,11 +51,14 @@ type DocumentHandler struct {
 	aliases   []string // namespace aliases
 	domain    string
 	label     string
+
+	unpublishedCache         UnpublishedCache
+	maxConcurrentResolutions int
 }
 
 // OperationProcessor is an interface which resolves the document based on the ID.
 type OperationProcessor interface {
-	Resolve(uniqueSuffix string) (*protocol.ResolutionModel, error)
+	Resolve(uniqueSuffix string, opts ...document.ResolutionOption) (*protocol.ResolutionModel, error)
 }
 
 // BatchWriter is an interface to add an operation to the batch.
@@ -61,6 +66,12 @@ type BatchWriter interface {
 	Add(operation *operation.QueuedOperation, protocolGenesisTime uint64) error
 }
 
+// UnpublishedCache caches the resolution results of unpublished (long-form) DIDs.
+type UnpublishedCache interface {
+	Get(longFormDID string) (*document.ResolutionResult, bool)
+	Put(longFormDID string, result *document.ResolutionResult)
+}
+
 // Option is an option for document handler.
 type Option func(opts *DocumentHandler)
 
@@ -71,6 +82,14 @@ func WithDomain(domain string) Option {
 	}
 }
 
+// WithUnpublishedCache sets an optional cache for the resolution results of unpublished (long-form) DIDs.
+// The cache is only consulted if the document was not found in the operation store.
+func WithUnpublishedCache(cache UnpublishedCache) Option {
+	return func(opts *DocumentHandler) {
+		opts.unpublishedCache = cache
+	}
+}
+
 // WithLabel sets optional label for unpublished/interim documents.
 func WithLabel(label string) Option {
 	return func(opts *DocumentHandler) {
@@ -78,6 +97,14 @@ func WithLabel(label string) Option {
 	}
 }
 
+// WithMaxConcurrentResolutions sets the maximum number of DIDs that are resolved concurrently
+// during a batch resolution (default 10).
+func WithMaxConcurrentResolutions(max int) Option {
+	return func(opts *DocumentHandler) {
+		opts.maxConcurrentResolutions = max
+	}
+}
+
 // New creates a new document handler with the context.
 func New(namespace string, aliases []string, pc protocol.Client, writer BatchWriter, processor OperationProcessor, opts ...Option) *DocumentHandler {
 	dh := &DocumentHandler{
@@ -86,6 +113,8 @@ func New(namespace string, aliases []string, pc protocol.Client, writer BatchWri
 		writer:    writer,
 		namespace: namespace,
 		aliases:   aliases,
+
+		maxConcurrentResolutions: defaultMaxConcurrentResolutions,
 	}
 
 	// apply options
@@ -93,6 +122,10 @@ func New(namespace string, aliases []string, pc protocol.Client, writer BatchWri
 		opt(dh)
 	}
 
+	if dh.maxConcurrentResolutions < 1 {
+		dh.maxConcurrentResolutions = 1
+	}
+
 	return dh
 }
 
@@ -110,7 +143,7 @@ func (r *DocumentHandler) ProcessOperation(operationBuffer []byte, protocolGenes
 
 	op, err := pv.OperationParser().Parse(r.namespace, operationBuffer)
 	if err != nil {
-		return nil, fmt.Errorf("%s: %s", badRequest, err.Error())
+		return nil, fmt.Errorf("%w: %s", operation.ErrBadRequest, err.Error())
 	}
 
 	// perform validation for operation request
@@ -218,10 +251,21 @@ func (r *DocumentHandler) getTransformationInfoForUnpublished(suffix string, cre
 // If the DID Document cannot be found, the <suffix-data-object> and <delta-object> are used
 // to generate and return resolved DID Document. In this case the supplied delta and suffix objects
 // are subject to the same validation as during processing create operation.
-func (r *DocumentHandler) ResolveDocument(shortOrLongFormDID string) (*document.ResolutionResult, error) {
+//
+// A specific version of a published DID Document may be resolved by providing resolution options or by adding
+// the 'versionId' and/or 'versionTime' parameters to the DID, e.g. did:METHOD:<did-suffix>?versionId=2.
+//
+// Resolution errors match (using errors.Is) one of the resolution errors of the document package
+// (e.g. document.ErrInvalidDID or document.ErrNotFound) so that the DID Resolution error code can be determined.
+func (r *DocumentHandler) ResolveDocument(shortOrLongFormDID string, opts ...document.ResolutionOption) (*document.ResolutionResult, error) {
+	shortOrLongFormDID, opts, err := getResolutionOptions(shortOrLongFormDID, opts)
+	if err != nil {
+		return nil, err
+	}
+
 	ns, err := r.getNamespace(shortOrLongFormDID)
 	if err != nil {
-		return nil, fmt.Errorf("%s: %s", badRequest, err.Error())
+		return nil, err
 	}
 
 	pv, err := r.protocol.Current()
@@ -232,28 +276,137 @@ func (r *DocumentHandler) ResolveDocument(shortOrLongFormDID string) (*document.
 	// extract did and optional initial document value
 	shortFormDID, createReq, err := pv.OperationParser().ParseDID(ns, shortOrLongFormDID)
 	if err != nil {
-		return nil, fmt.Errorf("%s: %s", badRequest, err.Error())
+		return nil, fmt.Errorf("%w: %s", document.ErrInvalidDID, err.Error())
 	}
 
 	uniquePortion, err := getSuffix(ns, shortFormDID)
 	if err != nil {
-		return nil, fmt.Errorf("%s: %s", badRequest, err.Error())
+		return nil, fmt.Errorf("%w: %s", document.ErrInvalidDID, err.Error())
 	}
 
 	// resolve document from the blockchain
-	doc, err := r.resolveRequestWithID(shortFormDID, uniquePortion, pv)
+	doc, err := r.resolveRequestWithID(shortFormDID, uniquePortion, pv, opts...)
 	if err == nil {
 		return doc, nil
 	}
 
 	// if document was not found on the blockchain and initial value has been provided resolve using initial value
-	if createReq != nil && strings.Contains(err.Error(), "not found") {
-		return r.resolveRequestWithInitialState(uniquePortion, shortOrLongFormDID, createReq, pv)
+	// (versions can only be resolved for published documents)
+	if createReq != nil && !document.GetResolutionOptions(opts...).VersionRequested() && errors.Is(err, operation.ErrNotFound) {
+		return r.resolveUnpublished(uniquePortion, shortOrLongFormDID, createReq, pv)
+	}
+
+	if errors.Is(err, operation.ErrNotFound) {
+		return nil, fmt.Errorf("%w: %s", document.ErrNotFound, err.Error())
 	}
 
 	return nil, err
 }
 
+// ResolveDocuments resolves the given short or long-form DIDs concurrently using at most the configured number
+// of workers (see WithMaxConcurrentResolutions). The same resolution options are used for all DIDs.
+//
+// A result is returned for each of the given DIDs (in the same order) that contains either the resolution result
+// or the resolution error of the DID (see ResolveDocument). Duplicate DIDs are resolved only once and share
+// the same result.
+func (r *DocumentHandler) ResolveDocuments(shortOrLongFormDIDs []string, opts ...document.ResolutionOption) []*document.BatchResolutionResult {
+	resultsByDID := make(map[string]*document.BatchResolutionResult)
+
+	var unique []*document.BatchResolutionResult
+
+	for _, did := range shortOrLongFormDIDs {
+		if _, ok := resultsByDID[did]; !ok {
+			result := &document.BatchResolutionResult{ID: did}
+
+			resultsByDID[did] = result
+			unique = append(unique, result)
+		}
+	}
+
+	logger.Debugf("Resolving %d DIDs (%d unique)", len(shortOrLongFormDIDs), len(unique))
+
+	r.resolveConcurrently(unique, opts)
+
+	results := make([]*document.BatchResolutionResult, len(shortOrLongFormDIDs))
+	for i, did := range shortOrLongFormDIDs {
+		results[i] = resultsByDID[did]
+	}
+
+	return results
+}
+
+// resolveConcurrently resolves the DIDs of the given results using a bounded pool of workers.
+func (r *DocumentHandler) resolveConcurrently(results []*document.BatchResolutionResult, opts []document.ResolutionOption) {
+	numWorkers := r.maxConcurrentResolutions
+	if len(results) < numWorkers {
+		numWorkers = len(results)
+	}
+
+	tasks := make(chan *document.BatchResolutionResult)
+
+	var wg sync.WaitGroup
+
+	wg.Add(numWorkers)
+
+	for i := 0; i < numWorkers; i++ {
+		go func() {
+			defer wg.Done()
+
+			for result := range tasks {
+				result.Result, result.Error = r.ResolveDocument(result.ID, opts...)
+			}
+		}()
+	}
+
+	for _, result := range results {
+		tasks <- result
+	}
+
+	close(tasks)
+
+	wg.Wait()
+}
+
+func (r *DocumentHandler) resolveUnpublished(uniqueSuffix, longFormDID string, initialBytes []byte, pv protocol.Version) (*document.ResolutionResult, error) {
+	if r.unpublishedCache == nil {
+		return r.resolveRequestWithInitialState(uniqueSuffix, longFormDID, initialBytes, pv)
+	}
+
+	if result, ok := r.unpublishedCache.Get(longFormDID); ok {
+		return result, nil
+	}
+
+	result, err := r.resolveRequestWithInitialState(uniqueSuffix, longFormDID, initialBytes, pv)
+	if err != nil {
+		return nil, err
+	}
+
+	r.unpublishedCache.Put(longFormDID, result)
+
+	return result, nil
+}
+
+// getResolutionOptions strips the optional query from the given DID URL and returns the DID along with
+// the resolution options for the query parameters (followed by the given options).
+func getResolutionOptions(didURL string, opts []document.ResolutionOption) (string, []document.ResolutionOption, error) {
+	pos := strings.Index(didURL, "?")
+	if pos < 0 {
+		return didURL, opts, nil
+	}
+
+	params, err := url.ParseQuery(didURL[pos+1:])
+	if err != nil {
+		return "", nil, fmt.Errorf("%w: invalid DID URL query: %s", document.ErrInvalidDID, err.Error())
+	}
+
+	queryOpts, err := document.ParseResolutionOptions(params)
+	if err != nil {
+		return "", nil, fmt.Errorf("%w: %s", document.ErrInvalidOptions, err.Error())
+	}
+
+	return didURL[:pos], append(queryOpts, opts...), nil
+}
+
 func (r *DocumentHandler) getNamespace(shortOrLongFormDID string) (string, error) {
 	// check aliases first (if configured)
 	for _, ns := range r.aliases {
@@ -267,11 +420,19 @@ func (r *DocumentHandler) getNamespace(shortOrLongFormDID string) (string, error
 		return r.namespace, nil
 	}
 
-	return "", fmt.Errorf("did must start with configured namespace[%s] or aliases%v", r.namespace, r.aliases)
+	// a DID of another method (i.e. with a method-specific ID) is valid but not supported
+	if strings.Count(shortOrLongFormDID, docutil.NamespaceDelimiter) >= 2 {
+		return "", fmt.Errorf("%w: did must start with configured namespace[%s] or aliases%v",
+			document.ErrMethodNotSupported, r.namespace, r.aliases)
+	}
+
+	return "", fmt.Errorf("%w: did must start with configured namespace[%s] or aliases%v",
+		document.ErrInvalidDID, r.namespace, r.aliases)
 }
 
-func (r *DocumentHandler) resolveRequestWithID(shortFormDid, uniquePortion string, pv protocol.Version) (*document.ResolutionResult, error) {
-	internalResult, err := r.processor.Resolve(uniquePortion)
+func (r *DocumentHandler) resolveRequestWithID(shortFormDid, uniquePortion string, pv protocol.Version,
+	opts ...document.ResolutionOption) (*document.ResolutionResult, error) {
+	internalResult, err := r.processor.Resolve(uniquePortion, opts...)
 	if err != nil {
 		logger.Debugf("Failed to resolve uniquePortion[%s]: %s", uniquePortion, err.Error())
 
@@ -309,11 +470,11 @@ func (r *DocumentHandler) resolveRequestWithID(shortFormDid, uniquePortion strin
 func (r *DocumentHandler) resolveRequestWithInitialState(uniqueSuffix, longFormDID string, initialBytes []byte, pv protocol.Version) (*document.ResolutionResult, error) {
 	op, err := pv.OperationParser().Parse(r.namespace, initialBytes)
 	if err != nil {
-		return nil, fmt.Errorf("%s: %s", badRequest, err.Error())
+		return nil, fmt.Errorf("%w: %s", document.ErrInvalidDID, err.Error())
 	}
 
 	if uniqueSuffix != op.UniqueSuffix {
-		return nil, fmt.Errorf("%s: provided did doesn't match did created from initial state", badRequest)
+		return nil, fmt.Errorf("%w: provided did doesn't match did created from initial state", document.ErrInvalidDID)
 	}
 
 	rm, err := r.getCreateResult(op, pv)
@@ -328,7 +489,7 @@ func (r *DocumentHandler) resolveRequestWithInitialState(uniqueSuffix, longFormD
 
 	err = pv.DocumentValidator().IsValidOriginalDocument(docBytes)
 	if err != nil {
-		return nil, fmt.Errorf("%s: validate initial document: %s", badRequest, err.Error())
+		return nil, fmt.Errorf("%w: validate initial document: %s", document.ErrInvalidDID, err.Error())
 	}
 
 	createRequestJCS := longFormDID[strings.LastIndex(longFormDID, docutil.NamespaceDelimiter)+1:]
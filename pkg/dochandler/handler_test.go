@@ -10,7 +10,9 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
@@ -31,6 +33,7 @@ import (
 	"github.com/trustbloc/sidetree-core-go/pkg/mocks"
 	"github.com/trustbloc/sidetree-core-go/pkg/patch"
 	"github.com/trustbloc/sidetree-core-go/pkg/processor"
+	"github.com/trustbloc/sidetree-core-go/pkg/resolutioncache"
 	"github.com/trustbloc/sidetree-core-go/pkg/versions/1_0/doccomposer"
 	"github.com/trustbloc/sidetree-core-go/pkg/versions/1_0/doctransformer/didtransformer"
 	"github.com/trustbloc/sidetree-core-go/pkg/versions/1_0/doctransformer/doctransformer"
@@ -60,6 +63,17 @@ func TestDocumentHandler_New(t *testing.T) {
 	require.Equal(t, namespace, dh.Namespace())
 	require.Equal(t, domain, dh.domain)
 	require.Equal(t, label, dh.label)
+
+	cache := resolutioncache.NewUnpublishedCache(10, time.Minute)
+	dh = New(namespace, nil, nil, nil, nil, WithUnpublishedCache(cache))
+	require.Equal(t, cache, dh.unpublishedCache)
+	require.Equal(t, defaultMaxConcurrentResolutions, dh.maxConcurrentResolutions)
+
+	dh = New(namespace, nil, nil, nil, nil, WithMaxConcurrentResolutions(3))
+	require.Equal(t, 3, dh.maxConcurrentResolutions)
+
+	dh = New(namespace, nil, nil, nil, nil, WithMaxConcurrentResolutions(0))
+	require.Equal(t, 1, dh.maxConcurrentResolutions)
 }
 
 func TestDocumentHandler_Protocol(t *testing.T) {
@@ -154,6 +168,8 @@ func TestDocumentHandler_ResolveDocument_DID(t *testing.T) {
 	require.Error(t, err)
 	require.Nil(t, result)
 	require.Contains(t, err.Error(), "not found")
+	require.True(t, errors.Is(err, document.ErrNotFound))
+	require.Equal(t, document.NotFoundErrorCode, document.GetErrorCode(err))
 
 	// insert document in the store
 	err = store.Put(getAnchoredCreateOperation())
@@ -185,6 +201,14 @@ func TestDocumentHandler_ResolveDocument_DID(t *testing.T) {
 	require.Error(t, err)
 	require.Nil(t, result)
 	require.Contains(t, err.Error(), "must start with configured namespace")
+	require.True(t, errors.Is(err, document.ErrInvalidDID))
+
+	// scenario: DID of another method
+	result, err = dochandler.ResolveDocument("did:other:abc")
+	require.Error(t, err)
+	require.Nil(t, result)
+	require.Contains(t, err.Error(), "must start with configured namespace")
+	require.True(t, errors.Is(err, document.ErrMethodNotSupported))
 
 	// scenario: invalid id
 	result, err = dochandler.ResolveDocument(namespace + docutil.NamespaceDelimiter)
@@ -193,6 +217,164 @@ func TestDocumentHandler_ResolveDocument_DID(t *testing.T) {
 	require.Contains(t, err.Error(), "did suffix is empty")
 }
 
+func TestDocumentHandler_ResolveDocuments(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		store := mocks.NewMockOperationStore(nil)
+		dochandler, cleanup := getDocumentHandler(store)
+		require.NotNil(t, dochandler)
+		defer cleanup()
+
+		err := store.Put(getAnchoredCreateOperation())
+		require.NoError(t, err)
+
+		docID := getCreateOperation().ID
+		aliasID := alias + ":" + getCreateOperation().UniqueSuffix
+		notFoundID := namespace + ":notfound"
+
+		results := dochandler.ResolveDocuments([]string{docID, "doc:invalid", notFoundID, aliasID, docID})
+		require.Len(t, results, 5)
+
+		require.Equal(t, docID, results[0].ID)
+		require.NoError(t, results[0].Error)
+		require.NotNil(t, results[0].Result)
+		require.Equal(t, docID, results[0].Result.Document.ID())
+
+		require.Equal(t, "doc:invalid", results[1].ID)
+		require.Nil(t, results[1].Result)
+		require.True(t, errors.Is(results[1].Error, document.ErrInvalidDID))
+
+		require.Equal(t, notFoundID, results[2].ID)
+		require.Nil(t, results[2].Result)
+		require.True(t, errors.Is(results[2].Error, document.ErrNotFound))
+
+		require.Equal(t, aliasID, results[3].ID)
+		require.NoError(t, results[3].Error)
+		require.Equal(t, aliasID, results[3].Result.Document.ID())
+
+		// duplicate DIDs share the same result
+		require.True(t, results[0] == results[4])
+	})
+
+	t.Run("resolution options", func(t *testing.T) {
+		store := mocks.NewMockOperationStore(nil)
+		pc := newMockProtocolClient()
+
+		p := &optionsRecordingProcessor{OperationProcessor: processor.New("test", store, pc)}
+		dochandler := New(namespace, []string{alias}, pc, nil, p)
+
+		results := dochandler.ResolveDocuments([]string{namespace + ":abc"}, document.WithVersionID(2))
+		require.Len(t, results, 1)
+		require.True(t, errors.Is(results[0].Error, document.ErrNotFound))
+		require.Equal(t, uint64(2), p.opts.VersionID)
+	})
+
+	t.Run("bounded concurrency", func(t *testing.T) {
+		store := mocks.NewMockOperationStore(nil)
+		pc := newMockProtocolClient()
+
+		p := &concurrencyTrackingProcessor{OperationProcessor: processor.New("test", store, pc)}
+		dochandler := New(namespace, nil, pc, nil, p, WithMaxConcurrentResolutions(3))
+
+		var ids []string
+		for i := 0; i < 10; i++ {
+			ids = append(ids, fmt.Sprintf("%s:suffix%d", namespace, i))
+		}
+
+		results := dochandler.ResolveDocuments(append(ids, ids...))
+		require.Len(t, results, 20)
+
+		for i, result := range results {
+			require.Equal(t, ids[i%10], result.ID)
+			require.True(t, errors.Is(result.Error, document.ErrNotFound))
+		}
+
+		require.Equal(t, 10, p.total)
+		require.True(t, p.maxActive <= 3)
+	})
+
+	t.Run("no DIDs", func(t *testing.T) {
+		dochandler := New(namespace, nil, nil, nil, nil)
+
+		require.Empty(t, dochandler.ResolveDocuments(nil))
+	})
+}
+
+func TestDocumentHandler_ResolveDocument_Version(t *testing.T) {
+	store := mocks.NewMockOperationStore(nil)
+	pc := newMockProtocolClient()
+
+	p := &optionsRecordingProcessor{OperationProcessor: processor.New("test", store, pc)}
+	dochandler := New(namespace, []string{alias}, pc, nil, p)
+
+	docID := getCreateOperation().ID
+
+	createRequest, err := getCreateRequest()
+	require.NoError(t, err)
+
+	createReq, err := canonicalizer.MarshalCanonical(createRequest)
+	require.NoError(t, err)
+
+	longFormDID := docID + ":" + encoder.EncodeToString(createReq)
+
+	t.Run("error - version of unpublished document", func(t *testing.T) {
+		result, err := dochandler.ResolveDocument(longFormDID + "?versionId=1")
+		require.Error(t, err)
+		require.Nil(t, result)
+		require.True(t, errors.Is(err, document.ErrNotFound))
+
+		// other options don't prevent resolution from the initial state
+		result, err = dochandler.ResolveDocument(longFormDID, document.WithOperationHistory())
+		require.NoError(t, err)
+		require.NotNil(t, result)
+	})
+
+	require.NoError(t, store.Put(getAnchoredCreateOperation()))
+
+	t.Run("success - DID URL parameters", func(t *testing.T) {
+		result, err := dochandler.ResolveDocument(docID + "?versionId=1&versionTime=1612173600")
+		require.NoError(t, err)
+		require.Equal(t, docID, result.Document[keyID])
+		require.Equal(t, uint64(1), p.opts.VersionID)
+		require.Equal(t, uint64(1612173600), p.opts.VersionTime.TransactionTime)
+	})
+
+	t.Run("error - version timestamp not supported by processor", func(t *testing.T) {
+		result, err := dochandler.ResolveDocument(docID + "?versionTime=2021-02-01T10:00:00Z")
+		require.Error(t, err)
+		require.Nil(t, result)
+		require.True(t, errors.Is(err, document.ErrInvalidOptions))
+		require.NotNil(t, p.opts.VersionTimestamp)
+	})
+
+	t.Run("success - resolution options", func(t *testing.T) {
+		result, err := dochandler.ResolveDocument(docID, document.WithVersionTime(5))
+		require.NoError(t, err)
+		require.Equal(t, docID, result.Document[keyID])
+		require.Zero(t, p.opts.VersionID)
+		require.Equal(t, uint64(5), p.opts.VersionTime.TransactionTime)
+
+		// the given options take precedence over DID URL parameters
+		_, err = dochandler.ResolveDocument(docID+"?versionId=1", document.WithVersionID(2))
+		require.Error(t, err)
+		require.True(t, errors.Is(err, document.ErrNotFound))
+		require.Equal(t, uint64(2), p.opts.VersionID)
+	})
+
+	t.Run("error - invalid DID URL parameters", func(t *testing.T) {
+		result, err := dochandler.ResolveDocument(docID + "?versionId=0")
+		require.Error(t, err)
+		require.Nil(t, result)
+		require.Contains(t, err.Error(), "invalid versionId[0]")
+		require.True(t, errors.Is(err, document.ErrInvalidOptions))
+
+		result, err = dochandler.ResolveDocument(docID + "?versionId=%zz")
+		require.Error(t, err)
+		require.Nil(t, result)
+		require.Contains(t, err.Error(), "invalid DID URL query")
+		require.True(t, errors.Is(err, document.ErrInvalidDID))
+	})
+}
+
 func TestDocumentHandler_ResolveDocument_DID_With_References(t *testing.T) {
 	store := mocks.NewMockOperationStore(nil)
 	dochandler, cleanup := getDocumentHandler(store)
@@ -294,7 +476,7 @@ func TestDocumentHandler_ResolveDocument_InitialValue(t *testing.T) {
 		result, err := dochandler.ResolveDocument(docID + ":payload")
 		require.Error(t, err)
 		require.Nil(t, result)
-		require.Contains(t, err.Error(), "bad request: invalid character")
+		require.Contains(t, err.Error(), "invalid DID: invalid character")
 	})
 
 	t.Run("error - did doesn't match the one created by parsing original create request", func(t *testing.T) {
@@ -335,7 +517,8 @@ func TestDocumentHandler_ResolveDocument_InitialValue(t *testing.T) {
 		result, err := dochandlerWithValidator.ResolveDocument(docID + longFormPart)
 		require.Error(t, err)
 		require.Nil(t, result)
-		require.Equal(t, err.Error(), "bad request: validate initial document: test error")
+		require.Equal(t, err.Error(), "invalid DID: validate initial document: test error")
+		require.True(t, errors.Is(err, document.ErrInvalidDID))
 	})
 
 	t.Run("error - protocol error", func(t *testing.T) {
@@ -350,6 +533,28 @@ func TestDocumentHandler_ResolveDocument_InitialValue(t *testing.T) {
 		require.EqualError(t, err, pc.Err.Error())
 		require.Nil(t, result)
 	})
+
+	t.Run("success - unpublished cache", func(t *testing.T) {
+		dochandler, cleanup := getDocumentHandler(mocks.NewMockOperationStore(nil))
+		require.NotNil(t, dochandler)
+		defer cleanup()
+
+		dochandler.unpublishedCache = resolutioncache.NewUnpublishedCache(10, time.Minute)
+
+		result, err := dochandler.ResolveDocument(docID + longFormPart)
+		require.NoError(t, err)
+
+		cachedResult, err := dochandler.ResolveDocument(docID + longFormPart)
+		require.NoError(t, err)
+		require.Same(t, result, cachedResult)
+
+		// errors are not cached
+		_, err = dochandler.ResolveDocument(dochandler.namespace + ":someID" + longFormPart)
+		require.Error(t, err)
+
+		_, ok := dochandler.unpublishedCache.Get(dochandler.namespace + ":someID" + longFormPart)
+		require.False(t, ok)
+	})
 }
 
 func TestDocumentHandler_ResolveDocument_Interop(t *testing.T) {
@@ -402,7 +607,7 @@ func TestDocumentHandler_ResolveDocument_InitialDocumentNotValid(t *testing.T) {
 	result, err := dochandler.ResolveDocument(docID + longFormPart)
 	require.Error(t, err)
 	require.Nil(t, result)
-	require.Contains(t, err.Error(), "bad request: key 'type' is required for public key")
+	require.Contains(t, err.Error(), "invalid DID: key 'type' is required for public key")
 }
 
 func TestGetUniquePortion(t *testing.T) {
@@ -439,6 +644,7 @@ func TestProcessOperation_ParseOperationError(t *testing.T) {
 	require.Error(t, err)
 	require.Nil(t, doc)
 	require.Contains(t, err.Error(), "bad request: missing signed data")
+	require.True(t, errors.Is(err, operation.ErrBadRequest))
 }
 
 // BatchContext implements batch writer context.
@@ -471,6 +677,51 @@ func (m *BatchContext) OperationQueue() cutter.OperationQueue {
 
 type cleanup func()
 
+// optionsRecordingProcessor records the resolution options of the last resolution.
+type optionsRecordingProcessor struct {
+	OperationProcessor
+
+	opts document.ResolutionOptions
+}
+
+func (p *optionsRecordingProcessor) Resolve(uniqueSuffix string, opts ...document.ResolutionOption) (*protocol.ResolutionModel, error) {
+	p.opts = document.GetResolutionOptions(opts...)
+
+	return p.OperationProcessor.Resolve(uniqueSuffix, opts...)
+}
+
+// concurrencyTrackingProcessor records the total number of resolutions and the maximum number of
+// concurrent resolutions.
+type concurrencyTrackingProcessor struct {
+	OperationProcessor
+
+	mutex     sync.Mutex
+	active    int
+	maxActive int
+	total     int
+}
+
+func (p *concurrencyTrackingProcessor) Resolve(uniqueSuffix string, opts ...document.ResolutionOption) (*protocol.ResolutionModel, error) {
+	p.mutex.Lock()
+	p.active++
+	p.total++
+
+	if p.active > p.maxActive {
+		p.maxActive = p.active
+	}
+	p.mutex.Unlock()
+
+	defer func() {
+		p.mutex.Lock()
+		p.active--
+		p.mutex.Unlock()
+	}()
+
+	time.Sleep(5 * time.Millisecond)
+
+	return p.OperationProcessor.Resolve(uniqueSuffix, opts...)
+}
+
 func getDocumentHandler(store processor.OperationStoreClient) (*DocumentHandler, cleanup) {
 	return getDocumentHandlerWithProtocolClient(store, newMockProtocolClient())
 }
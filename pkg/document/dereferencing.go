@@ -0,0 +1,29 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package document
+
+// DereferencingResult describes the result of dereferencing a DID URL.
+type DereferencingResult struct {
+	Context               string      `json:"@context"`
+	DereferencingMetadata Metadata    `json:"dereferencingMetadata"`
+	ContentStream         interface{} `json:"contentStream,omitempty"`
+	ContentMetadata       Metadata    `json:"contentMetadata,omitempty"`
+}
+
+const (
+	// ContentTypeProperty is the media type of the content stream in the dereferencing metadata.
+	ContentTypeProperty = "contentType"
+)
+
+const (
+	// ServiceParam is the DID URL parameter that selects a service from the DID document by its ID (fragment).
+	ServiceParam = "service"
+
+	// RelativeRefParam is the DID URL parameter that contains a relative URI reference which is appended
+	// to the endpoint of the selected service.
+	RelativeRefParam = "relativeRef"
+)
@@ -0,0 +1,112 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package document
+
+import "errors"
+
+// Error codes as defined by the DID Resolution spec. The code of a failed resolution (or dereferencing)
+// is returned in the 'error' property of the DID resolution (or dereferencing) metadata.
+const (
+	// InvalidDIDErrorCode is returned if the DID (URL) is not valid.
+	InvalidDIDErrorCode = "invalidDid"
+
+	// NotFoundErrorCode is returned if the DID document (or the resource identified by a DID URL) was not found.
+	NotFoundErrorCode = "notFound"
+
+	// MethodNotSupportedErrorCode is returned if the DID method (namespace) is not supported.
+	MethodNotSupportedErrorCode = "methodNotSupported"
+
+	// DeactivatedErrorCode is returned if the DID document was deactivated.
+	DeactivatedErrorCode = "deactivated"
+
+	// InvalidOptionsErrorCode is returned if the resolution options (DID URL parameters) are not valid.
+	InvalidOptionsErrorCode = "invalidOptions"
+
+	// RepresentationNotSupportedErrorCode is returned if none of the requested representations (media types)
+	// of the DID document is supported.
+	RepresentationNotSupportedErrorCode = "representationNotSupported"
+
+	// InternalErrorCode is returned for all other errors.
+	InternalErrorCode = "internalError"
+)
+
+const (
+	// ErrorProperty is the error code in the DID resolution (or dereferencing) metadata.
+	ErrorProperty = "error"
+
+	// ErrorMessageProperty is the error message in the DID resolution (or dereferencing) metadata.
+	ErrorMessageProperty = "errorMessage"
+)
+
+var (
+	// ErrInvalidDID is returned if the DID (URL) is not valid.
+	ErrInvalidDID = NewResolutionError(InvalidDIDErrorCode, "invalid DID")
+
+	// ErrNotFound is returned if the DID document was not found.
+	ErrNotFound = NewResolutionError(NotFoundErrorCode, "DID document not found")
+
+	// ErrMethodNotSupported is returned if the DID method (namespace) is not supported.
+	ErrMethodNotSupported = NewResolutionError(MethodNotSupportedErrorCode, "DID method not supported")
+
+	// ErrDeactivated is returned if the DID document was deactivated.
+	ErrDeactivated = NewResolutionError(DeactivatedErrorCode, "DID document is deactivated")
+
+	// ErrInvalidOptions is returned if the resolution options are not valid.
+	ErrInvalidOptions = NewResolutionError(InvalidOptionsErrorCode, "invalid resolution options")
+
+	// ErrRepresentationNotSupported is returned if none of the requested representations is supported.
+	ErrRepresentationNotSupported = NewResolutionError(RepresentationNotSupportedErrorCode, "representation not supported")
+
+	// ErrInternal is returned for all other resolution errors.
+	ErrInternal = NewResolutionError(InternalErrorCode, "internal error")
+)
+
+// ResolutionError is a resolution error with a DID Resolution error code. Errors with the same code match
+// when using errors.Is, e.g. errors.Is(err, ErrNotFound) is true for every error with code 'notFound'.
+type ResolutionError struct {
+	Code    string
+	Message string
+}
+
+// NewResolutionError returns a new resolution error with the given code and message.
+func NewResolutionError(code, message string) *ResolutionError {
+	return &ResolutionError{
+		Code:    code,
+		Message: message,
+	}
+}
+
+// Error returns the error message.
+func (e *ResolutionError) Error() string {
+	return e.Message
+}
+
+// Is returns true if the target is a resolution error with the same code.
+func (e *ResolutionError) Is(target error) bool {
+	t, ok := target.(*ResolutionError)
+
+	return ok && t.Code == e.Code
+}
+
+// GetErrorCode returns the DID Resolution error code of the given error. The internal error code is
+// returned if the error (chain) doesn't contain a resolution error.
+func GetErrorCode(err error) string {
+	var re *ResolutionError
+	if errors.As(err, &re) {
+		return re.Code
+	}
+
+	return InternalErrorCode
+}
+
+// NewErrorMetadata returns the DID resolution (or dereferencing) metadata for the given error.
+func NewErrorMetadata(err error) Metadata {
+	return Metadata{
+		ErrorProperty:        GetErrorCode(err),
+		ErrorMessageProperty: err.Error(),
+	}
+}
@@ -0,0 +1,42 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package document
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolutionError(t *testing.T) {
+	err := fmt.Errorf("%w: did:other:abc", ErrMethodNotSupported)
+	require.Equal(t, "DID method not supported: did:other:abc", err.Error())
+	require.True(t, errors.Is(err, ErrMethodNotSupported))
+	require.False(t, errors.Is(err, ErrNotFound))
+	require.Equal(t, MethodNotSupportedErrorCode, GetErrorCode(err))
+
+	// errors with the same code match
+	err = fmt.Errorf("resolve: %w", NewResolutionError(NotFoundErrorCode, "uniqueSuffix not found in the store"))
+	require.True(t, errors.Is(err, ErrNotFound))
+	require.Equal(t, NotFoundErrorCode, GetErrorCode(err))
+
+	require.False(t, errors.Is(errors.New("not found"), ErrNotFound))
+	require.Equal(t, InternalErrorCode, GetErrorCode(errors.New("injected error")))
+	require.Equal(t, InternalErrorCode, GetErrorCode(ErrInternal))
+}
+
+func TestNewErrorMetadata(t *testing.T) {
+	metadata := NewErrorMetadata(fmt.Errorf("%w: invalid versionId", ErrInvalidOptions))
+	require.Equal(t, InvalidOptionsErrorCode, metadata[ErrorProperty])
+	require.Equal(t, "invalid resolution options: invalid versionId", metadata[ErrorMessageProperty])
+
+	metadata = NewErrorMetadata(ErrDeactivated)
+	require.Equal(t, DeactivatedErrorCode, metadata[ErrorProperty])
+	require.Equal(t, InvalidDIDErrorCode, GetErrorCode(ErrInvalidDID))
+}
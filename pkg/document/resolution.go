@@ -6,11 +6,31 @@ SPDX-License-Identifier: Apache-2.0
 
 package document
 
+import (
+	"fmt"
+	"math"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// DIDResolutionContext is the JSON-LD context of DID resolution and DID URL dereferencing results.
+const DIDResolutionContext = "https://w3id.org/did-resolution/v1"
+
 // ResolutionResult describes resolution result.
 type ResolutionResult struct {
-	Context          string   `json:"@context"`
-	Document         Document `json:"didDocument"`
-	DocumentMetadata Metadata `json:"didDocumentMetadata,omitempty"`
+	Context               string   `json:"@context"`
+	Document              Document `json:"didDocument"`
+	DocumentMetadata      Metadata `json:"didDocumentMetadata,omitempty"`
+	DIDResolutionMetadata Metadata `json:"didResolutionMetadata,omitempty"`
+}
+
+// BatchResolutionResult contains the resolution result (or the resolution error) for one of the DIDs
+// of a batch resolution.
+type BatchResolutionResult struct {
+	ID     string
+	Result *ResolutionResult
+	Error  error
 }
 
 // Metadata can contains various metadata such as document metadata and method metadata..
@@ -40,4 +60,199 @@ const (
 
 	// MethodProperty is used for method metadata within did document metadata.
 	MethodProperty = "method"
+
+	// OperationHistoryProperty is the method metadata key for the operation history of a document.
+	OperationHistoryProperty = "operationHistory"
+
+	// CreatedProperty is the timestamp of the create operation.
+	CreatedProperty = "created"
+
+	// UpdatedProperty is the timestamp of the last applied operation.
+	UpdatedProperty = "updated"
+
+	// VersionIDProperty identifies the resolved version of the document.
+	VersionIDProperty = "versionId"
+
+	// NextUpdateProperty is the timestamp of the next version if a previous version was resolved.
+	NextUpdateProperty = "nextUpdate"
+
+	// NextVersionIDProperty identifies the next version if a previous version was resolved.
+	NextVersionIDProperty = "nextVersionId"
+)
+
+const (
+	// VersionIDParam is the DID URL parameter for resolving a specific version of a document.
+	VersionIDParam = "versionId"
+
+	// VersionTimeParam is the DID URL parameter for resolving the version of a document that was valid
+	// at a certain time.
+	VersionTimeParam = "versionTime"
+
+	// OperationHistoryParam is the DID URL parameter for including the operation history of a document
+	// in the method metadata.
+	OperationHistoryParam = "operationHistory"
 )
+
+// ResolutionOption is an option for document resolution.
+type ResolutionOption func(opts *ResolutionOptions)
+
+// ResolutionOptions contains the options for document resolution. If no options are set then the latest
+// version of the document is resolved.
+type ResolutionOptions struct {
+	// VersionID (if greater than zero) is the number of applied operations (in the order in which they were
+	// anchored, starting with the create operation) after which processing stops. Operations that are not
+	// applied (e.g. rejected operations) are not counted.
+	VersionID uint64
+
+	// VersionHash (if set) is a version ID as returned in the document metadata (i.e. the multihash of an
+	// operation). The operations up to and including that operation are processed.
+	VersionHash string
+
+	// VersionTime (if set) is the point in time after which anchored operations are not processed.
+	VersionTime *VersionTime
+
+	// VersionTimestamp (if set) is the point in time after which anchored operations are not processed. The
+	// resolver converts the timestamp into a transaction time; the timestamp is rejected if the resolver isn't
+	// able to map timestamps to transaction times (e.g. for ledgers that use block numbers as transaction times).
+	VersionTimestamp *time.Time
+
+	// OperationHistory indicates whether the history of all operations that were considered during
+	// resolution (including the reasons why operations were not applied) should be returned.
+	OperationHistory bool
+}
+
+// VersionRequested returns true if a specific version (rather than the latest version) of the document
+// was requested.
+func (o ResolutionOptions) VersionRequested() bool {
+	return o.VersionID > 0 || o.VersionHash != "" || o.VersionTime != nil || o.VersionTimestamp != nil
+}
+
+// VersionTime identifies the point in time of a version by transaction time and transaction number.
+type VersionTime struct {
+	TransactionTime   uint64
+	TransactionNumber uint64
+}
+
+// WithVersionID resolves the version of the document after the given number of operations were processed.
+func WithVersionID(versionID uint64) ResolutionOption {
+	return func(opts *ResolutionOptions) {
+		opts.VersionID = versionID
+	}
+}
+
+// WithVersionHash resolves the version of the document with the given version ID (as returned in the
+// document metadata).
+func WithVersionHash(versionHash string) ResolutionOption {
+	return func(opts *ResolutionOptions) {
+		opts.VersionHash = versionHash
+	}
+}
+
+// WithVersionTime resolves the version of the document that includes all operations that were anchored
+// at or before the given transaction time.
+func WithVersionTime(transactionTime uint64) ResolutionOption {
+	return WithVersionTransaction(transactionTime, math.MaxUint64)
+}
+
+// WithVersionTransaction resolves the version of the document that includes all operations that were anchored
+// at or before the given transaction (identified by transaction time and number).
+func WithVersionTransaction(transactionTime, transactionNumber uint64) ResolutionOption {
+	return func(opts *ResolutionOptions) {
+		opts.VersionTime = &VersionTime{
+			TransactionTime:   transactionTime,
+			TransactionNumber: transactionNumber,
+		}
+	}
+}
+
+// WithVersionTimestamp resolves the version of the document that includes all operations that were anchored
+// at or before the given point in time.
+func WithVersionTimestamp(t time.Time) ResolutionOption {
+	return func(opts *ResolutionOptions) {
+		opts.VersionTimestamp = &t
+	}
+}
+
+// WithOperationHistory returns the history of all operations that were considered during resolution.
+func WithOperationHistory() ResolutionOption {
+	return func(opts *ResolutionOptions) {
+		opts.OperationHistory = true
+	}
+}
+
+// GetResolutionOptions returns the resolution options for the given options.
+func GetResolutionOptions(opts ...ResolutionOption) ResolutionOptions {
+	options := ResolutionOptions{}
+
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	return options
+}
+
+// ParseResolutionOptions returns the resolution options for the given DID URL (or query) parameters.
+// The 'versionId' parameter is either a positive number of operations or a version ID as returned in the
+// document metadata. The 'versionTime' parameter is either
+// a transaction time or an RFC 3339 timestamp.
+// The 'operationHistory' parameter is a boolean.
+func ParseResolutionOptions(params url.Values) ([]ResolutionOption, error) {
+	var opts []ResolutionOption
+
+	if value := params.Get(VersionIDParam); value != "" {
+		opt, err := parseVersionID(value)
+		if err != nil {
+			return nil, err
+		}
+
+		opts = append(opts, opt)
+	}
+
+	if value := params.Get(VersionTimeParam); value != "" {
+		opt, err := parseVersionTime(value)
+		if err != nil {
+			return nil, err
+		}
+
+		opts = append(opts, opt)
+	}
+
+	if value := params.Get(OperationHistoryParam); value != "" {
+		include, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s[%s]: must be a boolean", OperationHistoryParam, value)
+		}
+
+		if include {
+			opts = append(opts, WithOperationHistory())
+		}
+	}
+
+	return opts, nil
+}
+
+func parseVersionID(value string) (ResolutionOption, error) {
+	versionID, err := strconv.ParseUint(value, 10, 64)
+	if err != nil {
+		return WithVersionHash(value), nil
+	}
+
+	if versionID == 0 {
+		return nil, fmt.Errorf("invalid %s[%s]: must be a positive number or a version hash", VersionIDParam, value)
+	}
+
+	return WithVersionID(versionID), nil
+}
+
+func parseVersionTime(value string) (ResolutionOption, error) {
+	if transactionTime, err := strconv.ParseUint(value, 10, 64); err == nil {
+		return WithVersionTime(transactionTime), nil
+	}
+
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s[%s]: must be a transaction time or an RFC 3339 timestamp", VersionTimeParam, value)
+	}
+
+	return WithVersionTimestamp(t), nil
+}
@@ -0,0 +1,95 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package document
+
+import (
+	"math"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetResolutionOptions(t *testing.T) {
+	opts := GetResolutionOptions()
+	require.Zero(t, opts.VersionID)
+	require.Nil(t, opts.VersionTime)
+	require.False(t, opts.VersionRequested())
+
+	opts = GetResolutionOptions(WithVersionID(3), WithVersionTime(100))
+	require.Equal(t, uint64(3), opts.VersionID)
+	require.Equal(t, &VersionTime{TransactionTime: 100, TransactionNumber: math.MaxUint64}, opts.VersionTime)
+
+	opts = GetResolutionOptions(WithVersionTransaction(100, 5))
+	require.Equal(t, &VersionTime{TransactionTime: 100, TransactionNumber: 5}, opts.VersionTime)
+	require.False(t, opts.OperationHistory)
+
+	opts = GetResolutionOptions(WithOperationHistory())
+	require.True(t, opts.OperationHistory)
+
+	opts = GetResolutionOptions(WithVersionHash("hash"))
+	require.Equal(t, "hash", opts.VersionHash)
+	require.True(t, opts.VersionRequested())
+}
+
+func TestParseResolutionOptions(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		opts, err := ParseResolutionOptions(url.Values{})
+		require.NoError(t, err)
+		require.Empty(t, opts)
+
+		opts, err = ParseResolutionOptions(url.Values{VersionIDParam: {"2"}, VersionTimeParam: {"150"}})
+		require.NoError(t, err)
+
+		options := GetResolutionOptions(opts...)
+		require.Equal(t, uint64(2), options.VersionID)
+		require.Equal(t, uint64(150), options.VersionTime.TransactionTime)
+
+		opts, err = ParseResolutionOptions(url.Values{VersionTimeParam: {"2021-02-01T10:00:00Z"}})
+		require.NoError(t, err)
+
+		options = GetResolutionOptions(opts...)
+		require.Zero(t, options.VersionID)
+		require.Nil(t, options.VersionTime)
+		require.Equal(t, time.Date(2021, 2, 1, 10, 0, 0, 0, time.UTC), *options.VersionTimestamp)
+		require.True(t, options.VersionRequested())
+
+		opts, err = ParseResolutionOptions(url.Values{VersionIDParam: {"EiAbc"}})
+		require.NoError(t, err)
+		require.Equal(t, "EiAbc", GetResolutionOptions(opts...).VersionHash)
+
+		opts, err = ParseResolutionOptions(url.Values{OperationHistoryParam: {"true"}})
+		require.NoError(t, err)
+		require.True(t, GetResolutionOptions(opts...).OperationHistory)
+
+		opts, err = ParseResolutionOptions(url.Values{OperationHistoryParam: {"false"}})
+		require.NoError(t, err)
+		require.Empty(t, opts)
+	})
+
+	t.Run("error - invalid operation history", func(t *testing.T) {
+		opts, err := ParseResolutionOptions(url.Values{OperationHistoryParam: {"yes"}})
+		require.Error(t, err)
+		require.Nil(t, opts)
+		require.Contains(t, err.Error(), "invalid operationHistory[yes]: must be a boolean")
+	})
+
+	t.Run("error - invalid version ID", func(t *testing.T) {
+		opts, err := ParseResolutionOptions(url.Values{VersionIDParam: {"0"}})
+		require.Error(t, err)
+		require.Nil(t, opts)
+		require.Contains(t, err.Error(), "invalid versionId[0]: must be a positive number or a version hash")
+	})
+
+	t.Run("error - invalid version time", func(t *testing.T) {
+		opts, err := ParseResolutionOptions(url.Values{VersionTimeParam: {"yesterday"}})
+		require.Error(t, err)
+		require.Nil(t, opts)
+		require.Contains(t, err.Error(), "invalid versionTime[yesterday]: must be a transaction time or an RFC 3339 timestamp")
+	})
+}
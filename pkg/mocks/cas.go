@@ -11,6 +11,7 @@ import (
 	"fmt"
 	"sync"
 
+	"github.com/trustbloc/sidetree-core-go/pkg/api/cas"
 	"github.com/trustbloc/sidetree-core-go/pkg/encoder"
 	"github.com/trustbloc/sidetree-core-go/pkg/hashing"
 )
@@ -64,7 +65,7 @@ func (m *MockCasClient) Read(address string) ([]byte, error) {
 
 	value, ok := m.m[address]
 	if !ok {
-		return nil, fmt.Errorf("not found")
+		return nil, cas.ErrContentNotFound
 	}
 
 	// decode address to verify hashes
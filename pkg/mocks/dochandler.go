@@ -8,7 +8,6 @@ package mocks
 
 import (
 	"encoding/json"
-	"errors"
 	"fmt"
 	"strings"
 
@@ -94,7 +93,7 @@ func (m *MockDocumentHandler) ProcessOperation(operationBuffer []byte, _ uint64)
 	var op Operation
 	err := json.Unmarshal(operationBuffer, &op)
 	if err != nil {
-		return nil, fmt.Errorf("bad request: %s", err.Error())
+		return nil, fmt.Errorf("%w: %s", operation.ErrBadRequest, err.Error())
 	}
 
 	var suffix string
@@ -107,7 +106,7 @@ func (m *MockDocumentHandler) ProcessOperation(operationBuffer []byte, _ uint64)
 	case operation.TypeUpdate, operation.TypeDeactivate, operation.TypeRecover:
 		suffix = op.DidSuffix
 	default:
-		return nil, fmt.Errorf("bad request: operation type [%s] not supported", op.Operation)
+		return nil, fmt.Errorf("%w: operation type [%s] not supported", operation.ErrBadRequest, op.Operation)
 	}
 
 	id := m.namespace + docutil.NamespaceDelimiter + suffix
@@ -141,15 +140,14 @@ func (m *MockDocumentHandler) ProcessOperation(operationBuffer []byte, _ uint64)
 	}, nil
 }
 
-// ResolveDocument mocks resolve document.
-func (m *MockDocumentHandler) ResolveDocument(didOrDocument string) (*document.ResolutionResult, error) {
+// ResolveDocument mocks resolve document. Resolution options are ignored.
+func (m *MockDocumentHandler) ResolveDocument(didOrDocument string, _ ...document.ResolutionOption) (*document.ResolutionResult, error) {
 	if m.err != nil {
 		return nil, m.err
 	}
 
-	const badRequest = "bad request"
 	if !strings.HasPrefix(didOrDocument, m.namespace) {
-		return nil, fmt.Errorf("%s: must start with supported namespace", badRequest)
+		return nil, fmt.Errorf("%w: must start with supported namespace", document.ErrInvalidDID)
 	}
 
 	pv, err := m.Protocol().Current()
@@ -159,7 +157,7 @@ func (m *MockDocumentHandler) ResolveDocument(didOrDocument string) (*document.R
 
 	did, initial, err := pv.OperationParser().ParseDID(m.namespace, didOrDocument)
 	if err != nil {
-		return nil, fmt.Errorf("%s: %s", badRequest, err.Error())
+		return nil, fmt.Errorf("%w: %s", document.ErrInvalidDID, err.Error())
 	}
 
 	if initial != nil {
@@ -167,7 +165,7 @@ func (m *MockDocumentHandler) ResolveDocument(didOrDocument string) (*document.R
 	}
 
 	if _, ok := m.store[didOrDocument]; !ok {
-		return nil, errors.New("not found")
+		return nil, document.ErrNotFound
 	}
 
 	doc := m.store[didOrDocument]
@@ -183,6 +181,23 @@ func (m *MockDocumentHandler) ResolveDocument(didOrDocument string) (*document.R
 	}, nil
 }
 
+// ResolveDocuments mocks batch resolution by resolving the given DIDs sequentially.
+func (m *MockDocumentHandler) ResolveDocuments(dids []string, opts ...document.ResolutionOption) []*document.BatchResolutionResult {
+	results := make([]*document.BatchResolutionResult, len(dids))
+
+	for i, did := range dids {
+		result, err := m.ResolveDocument(did, opts...)
+
+		results[i] = &document.BatchResolutionResult{
+			ID:     did,
+			Result: result,
+			Error:  err,
+		}
+	}
+
+	return results
+}
+
 func isDeactivated(doc document.Document) bool {
 	deactivated, ok := doc[deleted]
 	if !ok {
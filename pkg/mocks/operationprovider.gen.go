@@ -2,6 +2,7 @@
 package mocks
 
 import (
+	"context"
 	"sync"
 
 	"github.com/trustbloc/sidetree-core-go/pkg/api/operation"
@@ -10,10 +11,12 @@ import (
 )
 
 type OperationProvider struct {
-	GetTxnOperationsStub        func(*txn.SidetreeTxn) ([]*operation.AnchoredOperation, error)
+	GetTxnOperationsStub        func(context.Context, *txn.SidetreeTxn, ...string) ([]*operation.AnchoredOperation, error)
 	getTxnOperationsMutex       sync.RWMutex
 	getTxnOperationsArgsForCall []struct {
-		arg1 *txn.SidetreeTxn
+		arg1 context.Context
+		arg2 *txn.SidetreeTxn
+		arg3 []string
 	}
 	getTxnOperationsReturns struct {
 		result1 []*operation.AnchoredOperation
@@ -27,16 +30,18 @@ type OperationProvider struct {
 	invocationsMutex sync.RWMutex
 }
 
-func (fake *OperationProvider) GetTxnOperations(arg1 *txn.SidetreeTxn) ([]*operation.AnchoredOperation, error) {
+func (fake *OperationProvider) GetTxnOperations(arg1 context.Context, arg2 *txn.SidetreeTxn, arg3 ...string) ([]*operation.AnchoredOperation, error) {
 	fake.getTxnOperationsMutex.Lock()
 	ret, specificReturn := fake.getTxnOperationsReturnsOnCall[len(fake.getTxnOperationsArgsForCall)]
 	fake.getTxnOperationsArgsForCall = append(fake.getTxnOperationsArgsForCall, struct {
-		arg1 *txn.SidetreeTxn
-	}{arg1})
-	fake.recordInvocation("GetTxnOperations", []interface{}{arg1})
+		arg1 context.Context
+		arg2 *txn.SidetreeTxn
+		arg3 []string
+	}{arg1, arg2, arg3})
+	fake.recordInvocation("GetTxnOperations", []interface{}{arg1, arg2, arg3})
 	fake.getTxnOperationsMutex.Unlock()
 	if fake.GetTxnOperationsStub != nil {
-		return fake.GetTxnOperationsStub(arg1)
+		return fake.GetTxnOperationsStub(arg1, arg2, arg3...)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
@@ -51,17 +56,17 @@ func (fake *OperationProvider) GetTxnOperationsCallCount() int {
 	return len(fake.getTxnOperationsArgsForCall)
 }
 
-func (fake *OperationProvider) GetTxnOperationsCalls(stub func(*txn.SidetreeTxn) ([]*operation.AnchoredOperation, error)) {
+func (fake *OperationProvider) GetTxnOperationsCalls(stub func(context.Context, *txn.SidetreeTxn, ...string) ([]*operation.AnchoredOperation, error)) {
 	fake.getTxnOperationsMutex.Lock()
 	defer fake.getTxnOperationsMutex.Unlock()
 	fake.GetTxnOperationsStub = stub
 }
 
-func (fake *OperationProvider) GetTxnOperationsArgsForCall(i int) *txn.SidetreeTxn {
+func (fake *OperationProvider) GetTxnOperationsArgsForCall(i int) (context.Context, *txn.SidetreeTxn, []string) {
 	fake.getTxnOperationsMutex.RLock()
 	defer fake.getTxnOperationsMutex.RUnlock()
 	argsForCall := fake.getTxnOperationsArgsForCall[i]
-	return argsForCall.arg1
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
 }
 
 func (fake *OperationProvider) GetTxnOperationsReturns(result1 []*operation.AnchoredOperation, result2 error) {
@@ -22,10 +22,11 @@ type OperationQueue struct {
 		result1 uint
 		result2 error
 	}
-	RemoveStub        func(num uint) (ops operation.QueuedOperationsAtTime, ack func() uint, nack func(), err error)
+	RemoveStub        func(num uint, skip ...uint) (ops operation.QueuedOperationsAtTime, ack func() uint, nack func(), err error)
 	removeMutex       sync.RWMutex
 	removeArgsForCall []struct {
-		num uint
+		num  uint
+		skip []uint
 	}
 	removeReturns struct {
 		result1 operation.QueuedOperationsAtTime
@@ -117,16 +118,17 @@ func (fake *OperationQueue) AddReturnsOnCall(i int, result1 uint, result2 error)
 	}{result1, result2}
 }
 
-func (fake *OperationQueue) Remove(num uint) (ops operation.QueuedOperationsAtTime, ack func() uint, nack func(), err error) {
+func (fake *OperationQueue) Remove(num uint, skip ...uint) (ops operation.QueuedOperationsAtTime, ack func() uint, nack func(), err error) {
 	fake.removeMutex.Lock()
 	ret, specificReturn := fake.removeReturnsOnCall[len(fake.removeArgsForCall)]
 	fake.removeArgsForCall = append(fake.removeArgsForCall, struct {
-		num uint
-	}{num})
-	fake.recordInvocation("Remove", []interface{}{num})
+		num  uint
+		skip []uint
+	}{num, skip})
+	fake.recordInvocation("Remove", []interface{}{num, skip})
 	fake.removeMutex.Unlock()
 	if fake.RemoveStub != nil {
-		return fake.RemoveStub(num)
+		return fake.RemoveStub(num, skip...)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2, ret.result3, ret.result4
@@ -140,10 +142,10 @@ func (fake *OperationQueue) RemoveCallCount() int {
 	return len(fake.removeArgsForCall)
 }
 
-func (fake *OperationQueue) RemoveArgsForCall(i int) uint {
+func (fake *OperationQueue) RemoveArgsForCall(i int) (uint, []uint) {
 	fake.removeMutex.RLock()
 	defer fake.removeMutex.RUnlock()
-	return fake.removeArgsForCall[i].num
+	return fake.removeArgsForCall[i].num, fake.removeArgsForCall[i].skip
 }
 
 func (fake *OperationQueue) RemoveReturns(result1 operation.QueuedOperationsAtTime, result2 func() uint, result3 func(), result4 error) {
@@ -0,0 +1,161 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package observer
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// ErrCheckpointNotFound is returned when no checkpoint has been stored for a namespace.
+var ErrCheckpointNotFound = errors.New("checkpoint not found")
+
+// Checkpoint contains the time and number of the last fully processed transaction.
+type Checkpoint struct {
+	TransactionTime   uint64 `json:"transactionTime"`
+	TransactionNumber uint64 `json:"transactionNumber"`
+}
+
+// CheckpointStore persists the observer checkpoint per namespace.
+type CheckpointStore interface {
+	// Get returns the checkpoint for the given namespace or ErrCheckpointNotFound.
+	Get(namespace string) (*Checkpoint, error)
+	// Put stores the checkpoint for the given namespace.
+	Put(namespace string, checkpoint *Checkpoint) error
+}
+
+// MemCheckpointStore implements an in-memory checkpoint store.
+type MemCheckpointStore struct {
+	checkpoints map[string]Checkpoint
+	mutex       sync.RWMutex
+}
+
+// NewMemCheckpointStore returns a new in-memory checkpoint store.
+func NewMemCheckpointStore() *MemCheckpointStore {
+	return &MemCheckpointStore{
+		checkpoints: make(map[string]Checkpoint),
+	}
+}
+
+// Get returns the checkpoint for the given namespace.
+func (s *MemCheckpointStore) Get(namespace string) (*Checkpoint, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	cp, ok := s.checkpoints[namespace]
+	if !ok {
+		return nil, ErrCheckpointNotFound
+	}
+
+	return &cp, nil
+}
+
+// Put stores the checkpoint for the given namespace.
+func (s *MemCheckpointStore) Put(namespace string, checkpoint *Checkpoint) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.checkpoints[namespace] = *checkpoint
+
+	return nil
+}
+
+// FileCheckpointStore implements a checkpoint store that persists checkpoints of all namespaces in a JSON file.
+// The file is replaced atomically on each update.
+type FileCheckpointStore struct {
+	path        string
+	checkpoints map[string]Checkpoint
+	mutex       sync.RWMutex
+}
+
+// NewFileCheckpointStore returns a new checkpoint store backed by the file at the given path.
+// Existing checkpoints are loaded from the file.
+func NewFileCheckpointStore(path string) (*FileCheckpointStore, error) {
+	s := &FileCheckpointStore{
+		path:        path,
+		checkpoints: make(map[string]Checkpoint),
+	}
+
+	content, err := ioutil.ReadFile(filepath.Clean(path))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return s, nil
+		}
+
+		return nil, fmt.Errorf("read checkpoint file[%s]: %w", path, err)
+	}
+
+	if err := json.Unmarshal(content, &s.checkpoints); err != nil {
+		return nil, fmt.Errorf("unmarshal checkpoint file[%s]: %w", path, err)
+	}
+
+	return s, nil
+}
+
+// Get returns the checkpoint for the given namespace.
+func (s *FileCheckpointStore) Get(namespace string) (*Checkpoint, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	cp, ok := s.checkpoints[namespace]
+	if !ok {
+		return nil, ErrCheckpointNotFound
+	}
+
+	return &cp, nil
+}
+
+// Put stores the checkpoint for the given namespace.
+func (s *FileCheckpointStore) Put(namespace string, checkpoint *Checkpoint) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	checkpoints := make(map[string]Checkpoint, len(s.checkpoints)+1)
+	for ns, cp := range s.checkpoints {
+		checkpoints[ns] = cp
+	}
+
+	checkpoints[namespace] = *checkpoint
+
+	content, err := json.Marshal(checkpoints)
+	if err != nil {
+		return fmt.Errorf("marshal checkpoints: %w", err)
+	}
+
+	if err := replaceFile(s.path, content, "checkpoint"); err != nil {
+		return err
+	}
+
+	s.checkpoints = checkpoints
+
+	return nil
+}
+
+// replaceFile atomically replaces the content of the file at the given path (the kind of
+// file is used in error messages).
+func replaceFile(path string, content []byte, kind string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("create %s directory: %w", kind, err)
+	}
+
+	tmpPath := path + ".tmp"
+
+	if err := ioutil.WriteFile(tmpPath, content, 0o600); err != nil {
+		return fmt.Errorf("write %s file: %w", kind, err)
+	}
+
+	if err := os.Rename(tmpPath, path); err != nil {
+		return fmt.Errorf("replace %s file: %w", kind, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,100 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package observer
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemCheckpointStore(t *testing.T) {
+	s := NewMemCheckpointStore()
+
+	cp, err := s.Get("ns1")
+	require.True(t, errors.Is(err, ErrCheckpointNotFound))
+	require.Nil(t, cp)
+
+	require.NoError(t, s.Put("ns1", &Checkpoint{TransactionTime: 10, TransactionNumber: 2}))
+
+	cp, err = s.Get("ns1")
+	require.NoError(t, err)
+	require.Equal(t, uint64(10), cp.TransactionTime)
+	require.Equal(t, uint64(2), cp.TransactionNumber)
+
+	_, err = s.Get("ns2")
+	require.True(t, errors.Is(err, ErrCheckpointNotFound))
+}
+
+func TestFileCheckpointStore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "checkpoint")
+	require.NoError(t, err)
+
+	defer func() {
+		require.NoError(t, os.RemoveAll(dir))
+	}()
+
+	path := filepath.Join(dir, "observer", "checkpoints.json")
+
+	t.Run("success", func(t *testing.T) {
+		s, err := NewFileCheckpointStore(path)
+		require.NoError(t, err)
+
+		_, err = s.Get("ns1")
+		require.True(t, errors.Is(err, ErrCheckpointNotFound))
+
+		require.NoError(t, s.Put("ns1", &Checkpoint{TransactionTime: 10, TransactionNumber: 2}))
+		require.NoError(t, s.Put("ns2", &Checkpoint{TransactionTime: 20, TransactionNumber: 3}))
+		require.NoError(t, s.Put("ns1", &Checkpoint{TransactionTime: 11, TransactionNumber: 4}))
+
+		// checkpoints are loaded from file
+		s, err = NewFileCheckpointStore(path)
+		require.NoError(t, err)
+
+		cp, err := s.Get("ns1")
+		require.NoError(t, err)
+		require.Equal(t, uint64(11), cp.TransactionTime)
+		require.Equal(t, uint64(4), cp.TransactionNumber)
+
+		cp, err = s.Get("ns2")
+		require.NoError(t, err)
+		require.Equal(t, uint64(3), cp.TransactionNumber)
+	})
+
+	t.Run("error - invalid file", func(t *testing.T) {
+		invalidPath := filepath.Join(dir, "invalid.json")
+		require.NoError(t, ioutil.WriteFile(invalidPath, []byte("{"), 0o600))
+
+		s, err := NewFileCheckpointStore(invalidPath)
+		require.Error(t, err)
+		require.Nil(t, s)
+		require.Contains(t, err.Error(), "unmarshal checkpoint file")
+	})
+
+	t.Run("error - read file", func(t *testing.T) {
+		s, err := NewFileCheckpointStore(dir)
+		require.Error(t, err)
+		require.Nil(t, s)
+		require.Contains(t, err.Error(), "read checkpoint file")
+	})
+
+	t.Run("error - write file", func(t *testing.T) {
+		s, err := NewFileCheckpointStore(filepath.Join(dir, "file", "checkpoints.json"))
+		require.NoError(t, err)
+
+		// parent directory can't be created since a file with the same name exists
+		require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "file"), []byte("{}"), 0o600))
+
+		err = s.Put("ns1", &Checkpoint{})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "create checkpoint directory")
+	})
+}
@@ -7,6 +7,11 @@ SPDX-License-Identifier: Apache-2.0
 package observer
 
 import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
 	"github.com/trustbloc/edge-core/pkg/log"
 
 	"github.com/trustbloc/sidetree-core-go/pkg/api/operation"
@@ -21,40 +26,138 @@ type Ledger interface {
 	RegisterForSidetreeTxn() <-chan []txn.SidetreeTxn
 }
 
+// TxnReader reads Sidetree transactions from the anchoring system (e.g. batch.AnchorWriter). Read returns the
+// transaction that follows the given transaction number (or nil if there is none) and whether more
+// transactions are available.
+type TxnReader interface {
+	Read(sinceTransactionNumber int) (bool, *txn.SidetreeTxn)
+}
+
 // OperationStore interface to access operation store.
 type OperationStore interface {
 	Put(ops []*operation.AnchoredOperation) error
 }
 
-// OperationFilter filters out operations before they are persisted.
-type OperationFilter interface {
-	Filter(uniqueSuffix string, ops []*operation.AnchoredOperation) ([]*operation.AnchoredOperation, error)
+// OperationStatusTracker records that the operations in a Sidetree transaction were processed.
+type OperationStatusTracker interface {
+	Processed(sidetreeTxn txn.SidetreeTxn) error
 }
 
 // Providers contains all of the providers required by the TxnProcessor.
 type Providers struct {
 	Ledger                 Ledger
 	ProtocolClientProvider protocol.ClientProvider
+
+	// StatusTracker is optional. If set, operations are marked as processed once their transaction is processed.
+	StatusTracker OperationStatusTracker
+
+	// CheckpointStore is optional. If set, the last processed transaction is recorded per namespace and
+	// transactions at or before the checkpoint are not processed again.
+	CheckpointStore CheckpointStore
+
+	// TxnReaders are optional readers per namespace. On start, transactions since the namespace checkpoint
+	// are read and processed before live notifications from the ledger are processed.
+	TxnReaders map[string]TxnReader
+
+	// DeadLetterStore is optional (in-memory store is used by default). Transactions that fail processing
+	// after the maximum number of attempts are stored in the dead-letter store. If a CheckpointStore is set
+	// then a durable dead-letter store (e.g. FileDeadLetterStore) should be set as well, otherwise failed
+	// transactions are never moved to the (in-memory) dead-letter store and are retried until they succeed.
+	DeadLetterStore DeadLetterStore
+}
+
+// Option is an observer option.
+type Option func(opts *Observer)
+
+// WithMaxAttempts sets the maximum number of processing attempts for a transaction before it is
+// moved to the dead-letter store.
+func WithMaxAttempts(maxAttempts int) Option {
+	return func(opts *Observer) {
+		opts.maxAttempts = maxAttempts
+	}
+}
+
+// WithRetryBackoff sets the backoff before the first retry of a failed transaction and the maximum backoff.
+// The backoff is doubled after each failed attempt.
+func WithRetryBackoff(initial, maxBackoff time.Duration) Option {
+	return func(opts *Observer) {
+		opts.initialBackoff = initial
+		opts.maxBackoff = maxBackoff
+	}
 }
 
 // Observer receives transactions over a channel and processes them by storing them to an operation store.
+//
+// Transactions within a namespace are processed in transaction order. Transactions that fail processing are
+// retried with exponential backoff and later transactions of the same namespace are held back until the failed
+// transaction has been processed (or moved to the dead-letter store). The namespace checkpoint is not advanced
+// past a transaction that is waiting to be retried, so that pending retries are replayed after a restart.
+//
+// Transactions that fail after the maximum number of attempts are moved to the dead-letter store from which
+// they may be inspected and replayed (note that a replayed transaction is processed after the transactions
+// that followed it). The checkpoint advances past dead-lettered transactions, so transactions are only moved
+// to the dead-letter store if either the checkpoint isn't persisted or a dead-letter store was provided.
 type Observer struct {
 	*Providers
 
 	stopCh chan struct{}
+
+	maxAttempts    int
+	initialBackoff time.Duration
+	maxBackoff     time.Duration
+
+	retries   *retryQueue
+	completed map[string][]txn.SidetreeTxn
+	mutex     sync.Mutex
+
+	// retryIndefinitely is set if dead-lettered transactions would be lost on restart, i.e. if the
+	// checkpoint is persisted but the dead-letter store is not
+	retryIndefinitely bool
 }
 
 // New returns a new observer.
-func New(providers *Providers) *Observer {
-	return &Observer{
-		Providers: providers,
-		stopCh:    make(chan struct{}, 1),
+func New(providers *Providers, opts ...Option) *Observer {
+	o := &Observer{
+		Providers:      providers,
+		stopCh:         make(chan struct{}, 1),
+		maxAttempts:    defaultMaxAttempts,
+		initialBackoff: defaultInitialBackoff,
+		maxBackoff:     defaultMaxBackoff,
+		retries:        newRetryQueue(),
+		completed:      make(map[string][]txn.SidetreeTxn),
+	}
+
+	if o.DeadLetterStore == nil {
+		o.DeadLetterStore = NewMemDeadLetterStore()
+
+		if o.CheckpointStore != nil {
+			logger.Warnf("No dead-letter store was provided for the persisted checkpoint. " +
+				"Failed transactions will be retried until they succeed.")
+
+			o.retryIndefinitely = true
+		}
 	}
+
+	// apply options
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	return o
 }
 
-// Start starts observer routines.
+// Start starts observer routines. Missed transactions are replayed from the configured transaction
+// readers before live notifications are processed.
 func (o *Observer) Start() {
-	go o.listen(o.Ledger.RegisterForSidetreeTxn())
+	txnsCh := o.Ledger.RegisterForSidetreeTxn()
+
+	go func() {
+		if !o.catchUp() {
+			return
+		}
+
+		o.listen(txnsCh)
+	}()
 }
 
 // Stop stops the observer.
@@ -62,6 +165,49 @@ func (o *Observer) Stop() {
 	o.stopCh <- struct{}{}
 }
 
+// DeadLetters returns the transactions that failed processing after the maximum number of attempts.
+func (o *Observer) DeadLetters() ([]*FailedTxn, error) {
+	return o.DeadLetterStore.GetAll()
+}
+
+// ReplayDeadLetters processes all transactions in the dead-letter store. Transactions that are processed
+// successfully are deleted from the dead-letter store. The number of successfully processed transactions is returned.
+func (o *Observer) ReplayDeadLetters() (int, error) {
+	failedTxns, err := o.DeadLetterStore.GetAll()
+	if err != nil {
+		return 0, fmt.Errorf("get dead letters: %w", err)
+	}
+
+	replayed := 0
+
+	for _, failed := range failedTxns {
+		if e := o.processTxn(failed.Txn); e != nil {
+			logger.Warnf("[%s] Failed to replay transaction number [%d]: %s",
+				failed.Txn.Namespace, failed.Txn.TransactionNumber, e.Error())
+
+			failed.Attempts++
+			failed.LastError = e.Error()
+			failed.Updated = time.Now()
+
+			if err := o.DeadLetterStore.Put(failed); err != nil {
+				return replayed, fmt.Errorf("update dead letter: %w", err)
+			}
+
+			continue
+		}
+
+		if err := o.DeadLetterStore.Delete(failed.Txn.Namespace, failed.Txn.TransactionNumber); err != nil {
+			return replayed, fmt.Errorf("delete dead letter: %w", err)
+		}
+
+		o.processed(failed.Txn)
+
+		replayed++
+	}
+
+	return replayed, nil
+}
+
 func (o *Observer) listen(txnsCh <-chan []txn.SidetreeTxn) {
 	for {
 		select {
@@ -78,33 +224,320 @@ func (o *Observer) listen(txnsCh <-chan []txn.SidetreeTxn) {
 			}
 
 			o.process(txns)
+
+		case <-o.retryTimer():
+			o.processRetries()
 		}
 	}
 }
 
+// catchUp processes all transactions since the checkpoint of each namespace that has a transaction reader.
+// False is returned if the observer was stopped while catching up.
+func (o *Observer) catchUp() bool {
+	for namespace, reader := range o.TxnReaders {
+		since := -1
+
+		cp, err := o.getCheckpoint(namespace)
+		if err != nil {
+			logger.Warnf("[%s] Failed to get checkpoint: %s. Skipping catch-up.", namespace, err.Error())
+
+			continue
+		}
+
+		if cp != nil {
+			since = int(cp.TransactionNumber)
+		}
+
+		logger.Infof("[%s] Catching up on transactions since transaction number [%d]", namespace, since)
+
+		for {
+			select {
+			case <-o.stopCh:
+				logger.Infof("The observer has been stopped while catching up. Exiting.")
+
+				return false
+			default:
+			}
+
+			more, t := reader.Read(since)
+			if t == nil {
+				break
+			}
+
+			o.process([]txn.SidetreeTxn{*t})
+
+			since = int(t.TransactionNumber)
+
+			if !more {
+				break
+			}
+		}
+	}
+
+	return true
+}
+
 func (o *Observer) process(txns []txn.SidetreeTxn) {
 	for _, txn := range txns {
-		pc, err := o.ProtocolClientProvider.ForNamespace(txn.Namespace)
-		if err != nil {
-			logger.Warnf("Failed to get protocol client for namespace [%s]: %s", txn.Namespace, err.Error())
+		if o.isProcessed(txn) {
+			logger.Debugf("[%s] Skipping transaction number [%d] since it has already been processed",
+				txn.Namespace, txn.TransactionNumber)
 
 			continue
 		}
 
-		v, err := pc.Get(txn.ProtocolGenesisTime)
-		if err != nil {
-			logger.Warnf("Failed to get processor for transaction time [%d]: %s", txn.ProtocolGenesisTime, err.Error())
+		if o.hasPendingRetries(txn.Namespace) {
+			logger.Debugf("[%s] Holding back transaction number [%d] until the failed transactions of the namespace "+
+				"have been processed", txn.Namespace, txn.TransactionNumber)
+
+			o.addRetry(&retryEntry{FailedTxn: &FailedTxn{Txn: txn}, due: time.Now()})
 
 			continue
 		}
 
-		err = v.TransactionProcessor().Process(txn)
+		err := o.processTxn(txn)
 		if err != nil {
 			logger.Warnf("Failed to process anchor[%s]: %s", txn.AnchorString, err.Error())
 
+			entry := &retryEntry{FailedTxn: &FailedTxn{Txn: txn}}
+
+			o.addRetry(entry)
+			o.retryFailed(entry, err)
+
 			continue
 		}
 
 		logger.Debugf("Successfully processed anchor[%s]", txn.AnchorString)
+
+		o.processed(txn)
+	}
+}
+
+// processTxn processes the given transaction. An error is returned only if processing may be retried.
+func (o *Observer) processTxn(sidetreeTxn txn.SidetreeTxn) error {
+	pc, err := o.ProtocolClientProvider.ForNamespace(sidetreeTxn.Namespace)
+	if err != nil {
+		logger.Warnf("Failed to get protocol client for namespace [%s]: %s", sidetreeTxn.Namespace, err.Error())
+
+		return nil
+	}
+
+	v, err := pc.Get(sidetreeTxn.ProtocolGenesisTime)
+	if err != nil {
+		logger.Warnf("Failed to get processor for transaction time [%d]: %s", sidetreeTxn.ProtocolGenesisTime, err.Error())
+
+		return nil
+	}
+
+	return v.TransactionProcessor().Process(sidetreeTxn)
+}
+
+// processed is invoked when processing of the given transaction is complete.
+func (o *Observer) processed(sidetreeTxn txn.SidetreeTxn) {
+	o.complete(sidetreeTxn)
+
+	if o.StatusTracker != nil {
+		if err := o.StatusTracker.Processed(sidetreeTxn); err != nil {
+			logger.Warnf("Failed to record processed status for anchor[%s]: %s", sidetreeTxn.AnchorString, err.Error())
+		}
+	}
+}
+
+func (o *Observer) hasPendingRetries(namespace string) bool {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	_, ok := o.retries.minPending(namespace)
+
+	return ok
+}
+
+func (o *Observer) addRetry(entry *retryEntry) {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	o.retries.add(entry)
+}
+
+// backoff returns the backoff after the given number of failed attempts.
+func (o *Observer) backoff(attempts int) time.Duration {
+	backoff := o.initialBackoff
+
+	for i := 1; i < attempts; i++ {
+		backoff *= defaultBackoffFactor
+
+		if backoff >= o.maxBackoff {
+			return o.maxBackoff
+		}
+	}
+
+	return backoff
+}
+
+func (o *Observer) retryTimer() <-chan time.Time {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	next, ok := o.retries.next()
+	if !ok {
+		// nil channel blocks forever
+		return nil
+	}
+
+	return time.After(time.Until(next))
+}
+
+func (o *Observer) processRetries() {
+	o.mutex.Lock()
+	due := o.retries.due(time.Now())
+	o.mutex.Unlock()
+
+	for _, entry := range due {
+		err := o.processTxn(entry.Txn)
+		if err == nil {
+			logger.Infof("[%s] Successfully processed transaction number [%d] after %d attempts",
+				entry.Txn.Namespace, entry.Txn.TransactionNumber, entry.Attempts+1)
+
+			o.removeRetry(entry)
+			o.processed(entry.Txn)
+
+			continue
+		}
+
+		o.retryFailed(entry, err)
+	}
+}
+
+func (o *Observer) retryFailed(entry *retryEntry, err error) {
+	entry.Attempts++
+	entry.LastError = err.Error()
+	entry.Updated = time.Now()
+
+	if entry.Attempts < o.maxAttempts || o.retryIndefinitely {
+		o.mutex.Lock()
+		entry.due = time.Now().Add(o.backoff(entry.Attempts))
+		o.mutex.Unlock()
+
+		logger.Warnf("[%s] Failed to process transaction number [%d] (attempt %d of %d): %s. Retrying in %s.",
+			entry.Txn.Namespace, entry.Txn.TransactionNumber, entry.Attempts, o.maxAttempts, err.Error(),
+			o.backoff(entry.Attempts))
+
+		return
+	}
+
+	logger.Errorf("[%s] Failed to process transaction number [%d] after %d attempts. Moving to dead-letter store: %s",
+		entry.Txn.Namespace, entry.Txn.TransactionNumber, entry.Attempts, err.Error())
+
+	if e := o.DeadLetterStore.Put(entry.FailedTxn); e != nil {
+		// keep the transaction in the retry queue so that it isn't lost
+		logger.Errorf("[%s] Failed to store transaction number [%d] in dead-letter store: %s",
+			entry.Txn.Namespace, entry.Txn.TransactionNumber, e.Error())
+
+		o.mutex.Lock()
+		entry.due = time.Now().Add(o.maxBackoff)
+		o.mutex.Unlock()
+
+		return
+	}
+
+	o.removeRetry(entry)
+
+	// the transaction is in the dead-letter store so the checkpoint may advance past it
+	o.complete(entry.Txn)
+}
+
+func (o *Observer) removeRetry(entry *retryEntry) {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	o.retries.remove(entry)
+}
+
+func (o *Observer) getCheckpoint(namespace string) (*Checkpoint, error) {
+	if o.CheckpointStore == nil {
+		return nil, nil
+	}
+
+	cp, err := o.CheckpointStore.Get(namespace)
+	if err != nil {
+		if errors.Is(err, ErrCheckpointNotFound) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return cp, nil
+}
+
+func (o *Observer) isProcessed(sidetreeTxn txn.SidetreeTxn) bool {
+	cp, err := o.getCheckpoint(sidetreeTxn.Namespace)
+	if err != nil {
+		logger.Warnf("[%s] Failed to get checkpoint: %s", sidetreeTxn.Namespace, err.Error())
+
+		return false
+	}
+
+	return cp != nil && sidetreeTxn.TransactionNumber <= cp.TransactionNumber
+}
+
+// complete records that processing of the given transaction is complete and advances the namespace checkpoint
+// to the latest completed transaction that precedes all transactions that are waiting to be retried.
+func (o *Observer) complete(sidetreeTxn txn.SidetreeTxn) {
+	if o.CheckpointStore == nil {
+		return
+	}
+
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	namespace := sidetreeTxn.Namespace
+
+	minPending, hasPending := o.retries.minPending(namespace)
+
+	var latest *txn.SidetreeTxn
+
+	var remaining []txn.SidetreeTxn
+
+	for _, t := range append(o.completed[namespace], sidetreeTxn) {
+		if hasPending && t.TransactionNumber > minPending {
+			remaining = append(remaining, t)
+
+			continue
+		}
+
+		if latest == nil || t.TransactionNumber > latest.TransactionNumber {
+			completed := t
+			latest = &completed
+		}
+	}
+
+	o.completed[namespace] = remaining
+
+	if latest != nil {
+		o.updateCheckpoint(*latest)
+	}
+}
+
+func (o *Observer) updateCheckpoint(sidetreeTxn txn.SidetreeTxn) {
+	cp, err := o.getCheckpoint(sidetreeTxn.Namespace)
+	if err != nil {
+		logger.Warnf("[%s] Failed to get checkpoint: %s", sidetreeTxn.Namespace, err.Error())
+
+		return
+	}
+
+	if cp != nil && cp.TransactionNumber >= sidetreeTxn.TransactionNumber {
+		// never move the checkpoint backwards (e.g. when a dead letter is replayed)
+		return
+	}
+
+	err = o.CheckpointStore.Put(sidetreeTxn.Namespace, &Checkpoint{
+		TransactionTime:   sidetreeTxn.TransactionTime,
+		TransactionNumber: sidetreeTxn.TransactionNumber,
+	})
+	if err != nil {
+		logger.Warnf("[%s] Failed to store checkpoint for transaction number [%d]: %s",
+			sidetreeTxn.Namespace, sidetreeTxn.TransactionNumber, err.Error())
 	}
 }
@@ -7,13 +7,18 @@ SPDX-License-Identifier: Apache-2.0
 package observer
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/require"
 
 	"github.com/trustbloc/sidetree-core-go/pkg/api/operation"
+	"github.com/trustbloc/sidetree-core-go/pkg/api/protocol"
 	"github.com/trustbloc/sidetree-core-go/pkg/api/txn"
 	"github.com/trustbloc/sidetree-core-go/pkg/mocks"
 	"github.com/trustbloc/sidetree-core-go/pkg/versions/1_0/txnprocessor"
@@ -72,6 +77,408 @@ func TestStartObserver(t *testing.T) {
 
 		require.Equal(t, 1, tp.ProcessCallCount())
 	})
+
+	t.Run("test status tracker", func(t *testing.T) {
+		sidetreeTxnCh := make(chan []txn.SidetreeTxn, 100)
+
+		tp := &mocks.TxnProcessor{}
+
+		pc := mocks.NewMockProtocolClient()
+		pc.Versions[0].TransactionProcessorReturns(tp)
+
+		tracker := &mockStatusTracker{err: fmt.Errorf("tracker error")}
+
+		providers := &Providers{
+			Ledger:                 mockLedger{registerForSidetreeTxnValue: sidetreeTxnCh},
+			ProtocolClientProvider: mocks.NewMockProtocolClientProvider().WithProtocolClient(namespace1, pc),
+			StatusTracker:          tracker,
+		}
+
+		o := New(providers)
+		require.NotNil(t, o)
+
+		o.Start()
+		defer o.Stop()
+
+		sidetreeTxnCh <- []txn.SidetreeTxn{
+			{Namespace: namespace1, TransactionTime: 10, TransactionNumber: 0, AnchorString: "1.address"},
+			{Namespace: namespace1, TransactionTime: 11, TransactionNumber: 1, AnchorString: "2.address"},
+		}
+		time.Sleep(200 * time.Millisecond)
+
+		require.Equal(t, 2, tp.ProcessCallCount())
+		require.Equal(t, []string{"1.address", "2.address"}, tracker.getAnchors())
+	})
+}
+
+func TestObserver_Checkpoint(t *testing.T) {
+	const namespace = "ns1"
+
+	newProtocolClientProvider := func(tp *mocks.TxnProcessor) protocol.ClientProvider {
+		pc := mocks.NewMockProtocolClient()
+		pc.Versions[0].TransactionProcessorReturns(tp)
+
+		return mocks.NewMockProtocolClientProvider().WithProtocolClient(namespace, pc)
+	}
+
+	t.Run("catch up from checkpoint before live notifications", func(t *testing.T) {
+		sidetreeTxnCh := make(chan []txn.SidetreeTxn, 100)
+
+		reader := newMockTxnReader(namespace, 5)
+
+		store := NewMemCheckpointStore()
+		require.NoError(t, store.Put(namespace, &Checkpoint{TransactionTime: 1, TransactionNumber: 1}))
+
+		tp := &mocks.TxnProcessor{}
+
+		o := New(&Providers{
+			Ledger:                 mockLedger{registerForSidetreeTxnValue: sidetreeTxnCh},
+			ProtocolClientProvider: newProtocolClientProvider(tp),
+			CheckpointStore:        store,
+			TxnReaders:             map[string]TxnReader{namespace: reader},
+		})
+
+		// live notifications contain transactions that were already replayed
+		sidetreeTxnCh <- []txn.SidetreeTxn{
+			{Namespace: namespace, TransactionTime: 4, TransactionNumber: 4, AnchorString: "4.anchor"},
+			{Namespace: namespace, TransactionTime: 5, TransactionNumber: 5, AnchorString: "5.anchor"},
+		}
+
+		o.Start()
+		defer o.Stop()
+
+		time.Sleep(200 * time.Millisecond)
+
+		// transactions 2, 3 and 4 are replayed and transaction 5 is processed from live notifications
+		require.Equal(t, 4, tp.ProcessCallCount())
+
+		for i, expected := range []uint64{2, 3, 4, 5} {
+			sidetreeTxn, _ := tp.ProcessArgsForCall(i)
+			require.Equal(t, expected, sidetreeTxn.TransactionNumber)
+		}
+
+		cp, err := store.Get(namespace)
+		require.NoError(t, err)
+		require.Equal(t, uint64(5), cp.TransactionNumber)
+		require.Equal(t, uint64(5), cp.TransactionTime)
+	})
+
+	t.Run("catch up without checkpoint", func(t *testing.T) {
+		tp := &mocks.TxnProcessor{}
+
+		store := NewMemCheckpointStore()
+
+		o := New(&Providers{
+			Ledger:                 mockLedger{registerForSidetreeTxnValue: make(chan []txn.SidetreeTxn, 100)},
+			ProtocolClientProvider: newProtocolClientProvider(tp),
+			CheckpointStore:        store,
+			TxnReaders:             map[string]TxnReader{namespace: newMockTxnReader(namespace, 3)},
+		})
+
+		o.Start()
+		defer o.Stop()
+
+		time.Sleep(200 * time.Millisecond)
+
+		require.Equal(t, 3, tp.ProcessCallCount())
+
+		cp, err := store.Get(namespace)
+		require.NoError(t, err)
+		require.Equal(t, uint64(2), cp.TransactionNumber)
+	})
+
+	t.Run("checkpoint not updated on processing error", func(t *testing.T) {
+		tp := &mocks.TxnProcessor{}
+		tp.ProcessReturns(fmt.Errorf("processing error"))
+
+		store := NewMemCheckpointStore()
+
+		o := New(&Providers{
+			Ledger:                 mockLedger{registerForSidetreeTxnValue: make(chan []txn.SidetreeTxn, 100)},
+			ProtocolClientProvider: newProtocolClientProvider(tp),
+			CheckpointStore:        store,
+			TxnReaders:             map[string]TxnReader{namespace: newMockTxnReader(namespace, 2)},
+		})
+
+		o.Start()
+		defer o.Stop()
+
+		time.Sleep(200 * time.Millisecond)
+
+		// the second transaction is held back until the first one has been processed
+		require.Equal(t, 1, tp.ProcessCallCount())
+
+		o.mutex.Lock()
+		require.Equal(t, 2, o.retries.len())
+		o.mutex.Unlock()
+
+		_, err := store.Get(namespace)
+		require.True(t, errors.Is(err, ErrCheckpointNotFound))
+	})
+
+	t.Run("checkpoint store error", func(t *testing.T) {
+		tp := &mocks.TxnProcessor{}
+
+		sidetreeTxnCh := make(chan []txn.SidetreeTxn, 100)
+
+		o := New(&Providers{
+			Ledger:                 mockLedger{registerForSidetreeTxnValue: sidetreeTxnCh},
+			ProtocolClientProvider: newProtocolClientProvider(tp),
+			CheckpointStore:        &mockCheckpointStore{err: fmt.Errorf("checkpoint store error")},
+			TxnReaders:             map[string]TxnReader{namespace: newMockTxnReader(namespace, 2)},
+		})
+
+		o.Start()
+		defer o.Stop()
+
+		sidetreeTxnCh <- []txn.SidetreeTxn{
+			{Namespace: namespace, TransactionTime: 4, TransactionNumber: 4, AnchorString: "4.anchor"},
+		}
+
+		time.Sleep(200 * time.Millisecond)
+
+		// catch-up is skipped but live notifications are processed
+		require.Equal(t, 1, tp.ProcessCallCount())
+	})
+
+	t.Run("stopped while catching up", func(t *testing.T) {
+		tp := &mocks.TxnProcessor{}
+
+		o := New(&Providers{
+			Ledger:                 mockLedger{registerForSidetreeTxnValue: make(chan []txn.SidetreeTxn, 100)},
+			ProtocolClientProvider: newProtocolClientProvider(tp),
+			TxnReaders:             map[string]TxnReader{namespace: newMockTxnReader(namespace, 2)},
+		})
+
+		o.Stop()
+		o.Start()
+
+		time.Sleep(200 * time.Millisecond)
+
+		require.Zero(t, tp.ProcessCallCount())
+	})
+}
+
+func TestObserver_Retry(t *testing.T) {
+	const namespace = "ns1"
+
+	newProtocolClientProvider := func(tp *mocks.TxnProcessor) protocol.ClientProvider {
+		pc := mocks.NewMockProtocolClient()
+		pc.Versions[0].TransactionProcessorReturns(tp)
+
+		return mocks.NewMockProtocolClientProvider().WithProtocolClient(namespace, pc)
+	}
+
+	txns := []txn.SidetreeTxn{
+		{Namespace: namespace, TransactionTime: 1, TransactionNumber: 1, AnchorString: "1.anchor"},
+		{Namespace: namespace, TransactionTime: 2, TransactionNumber: 2, AnchorString: "2.anchor"},
+	}
+
+	t.Run("success after retry", func(t *testing.T) {
+		sidetreeTxnCh := make(chan []txn.SidetreeTxn, 100)
+
+		tp := &mocks.TxnProcessor{}
+		tp.ProcessReturnsOnCall(0, fmt.Errorf("CAS unavailable"))
+		tp.ProcessReturnsOnCall(1, fmt.Errorf("CAS unavailable"))
+
+		store := NewMemCheckpointStore()
+
+		o := New(&Providers{
+			Ledger:                 mockLedger{registerForSidetreeTxnValue: sidetreeTxnCh},
+			ProtocolClientProvider: newProtocolClientProvider(tp),
+			CheckpointStore:        store,
+		}, WithRetryBackoff(100*time.Millisecond, time.Second))
+
+		o.Start()
+		defer o.Stop()
+
+		sidetreeTxnCh <- txns
+
+		time.Sleep(50 * time.Millisecond)
+
+		// second transaction is held back until the failed transaction has been processed
+		require.Equal(t, 1, tp.ProcessCallCount())
+
+		_, err := store.Get(namespace)
+		require.True(t, errors.Is(err, ErrCheckpointNotFound))
+
+		// first retry (after 100ms) fails and second retry (after 200ms) succeeds
+		time.Sleep(500 * time.Millisecond)
+
+		require.Equal(t, 4, tp.ProcessCallCount())
+
+		sidetreeTxn, _ := tp.ProcessArgsForCall(2)
+		require.Equal(t, uint64(1), sidetreeTxn.TransactionNumber)
+
+		sidetreeTxn, _ = tp.ProcessArgsForCall(3)
+		require.Equal(t, uint64(2), sidetreeTxn.TransactionNumber)
+
+		cp, err := store.Get(namespace)
+		require.NoError(t, err)
+		require.Equal(t, uint64(2), cp.TransactionNumber)
+
+		deadLetters, err := o.DeadLetters()
+		require.NoError(t, err)
+		require.Empty(t, deadLetters)
+	})
+
+	t.Run("dead letter after max attempts", func(t *testing.T) {
+		sidetreeTxnCh := make(chan []txn.SidetreeTxn, 100)
+
+		tp := &mocks.TxnProcessor{}
+		tp.ProcessReturns(fmt.Errorf("CAS unavailable"))
+
+		store := NewMemCheckpointStore()
+
+		o := New(&Providers{
+			Ledger:                 mockLedger{registerForSidetreeTxnValue: sidetreeTxnCh},
+			ProtocolClientProvider: newProtocolClientProvider(tp),
+			CheckpointStore:        store,
+			DeadLetterStore:        NewMemDeadLetterStore(),
+		}, WithMaxAttempts(3), WithRetryBackoff(20*time.Millisecond, 40*time.Millisecond))
+
+		o.Start()
+		defer o.Stop()
+
+		sidetreeTxnCh <- txns
+
+		time.Sleep(300 * time.Millisecond)
+
+		require.Equal(t, 6, tp.ProcessCallCount())
+
+		// the second transaction is processed after the first one was moved to the dead-letter store
+		for i := 0; i < 6; i++ {
+			sidetreeTxn, _ := tp.ProcessArgsForCall(i)
+			require.Equal(t, uint64(i/3+1), sidetreeTxn.TransactionNumber)
+		}
+
+		deadLetters, err := o.DeadLetters()
+		require.NoError(t, err)
+		require.Len(t, deadLetters, 2)
+		require.Equal(t, uint64(1), deadLetters[0].Txn.TransactionNumber)
+		require.Equal(t, 3, deadLetters[0].Attempts)
+		require.Equal(t, "CAS unavailable", deadLetters[0].LastError)
+		require.Equal(t, uint64(2), deadLetters[1].Txn.TransactionNumber)
+
+		// checkpoint advances past dead letters
+		cp, err := store.Get(namespace)
+		require.NoError(t, err)
+		require.Equal(t, uint64(2), cp.TransactionNumber)
+
+		// replay fails again
+		replayed, err := o.ReplayDeadLetters()
+		require.NoError(t, err)
+		require.Zero(t, replayed)
+
+		deadLetters, err = o.DeadLetters()
+		require.NoError(t, err)
+		require.Len(t, deadLetters, 2)
+		require.Equal(t, 4, deadLetters[0].Attempts)
+
+		// replay succeeds
+		tp.ProcessReturns(nil)
+
+		replayed, err = o.ReplayDeadLetters()
+		require.NoError(t, err)
+		require.Equal(t, 2, replayed)
+
+		deadLetters, err = o.DeadLetters()
+		require.NoError(t, err)
+		require.Empty(t, deadLetters)
+
+		cp, err = store.Get(namespace)
+		require.NoError(t, err)
+		require.Equal(t, uint64(2), cp.TransactionNumber)
+	})
+
+	t.Run("retry indefinitely without dead-letter store for persisted checkpoint", func(t *testing.T) {
+		sidetreeTxnCh := make(chan []txn.SidetreeTxn, 100)
+
+		var available int32
+
+		tp := &mocks.TxnProcessor{}
+		tp.ProcessStub = func(txn.SidetreeTxn, ...string) error {
+			if atomic.LoadInt32(&available) == 0 {
+				return fmt.Errorf("CAS unavailable")
+			}
+
+			return nil
+		}
+
+		store := NewMemCheckpointStore()
+
+		o := New(&Providers{
+			Ledger:                 mockLedger{registerForSidetreeTxnValue: sidetreeTxnCh},
+			ProtocolClientProvider: newProtocolClientProvider(tp),
+			CheckpointStore:        store,
+		}, WithMaxAttempts(2), WithRetryBackoff(10*time.Millisecond, 20*time.Millisecond))
+
+		o.Start()
+		defer o.Stop()
+
+		sidetreeTxnCh <- txns
+
+		time.Sleep(200 * time.Millisecond)
+
+		// only the first transaction is retried
+		require.True(t, tp.ProcessCallCount() > 2)
+
+		for i := 0; i < tp.ProcessCallCount(); i++ {
+			sidetreeTxn, _ := tp.ProcessArgsForCall(i)
+			require.Equal(t, uint64(1), sidetreeTxn.TransactionNumber)
+		}
+
+		deadLetters, err := o.DeadLetters()
+		require.NoError(t, err)
+		require.Empty(t, deadLetters)
+
+		_, err = store.Get(namespace)
+		require.True(t, errors.Is(err, ErrCheckpointNotFound))
+
+		// the first transaction succeeds and the second one is processed
+		atomic.StoreInt32(&available, 1)
+
+		time.Sleep(100 * time.Millisecond)
+
+		cp, err := store.Get(namespace)
+		require.NoError(t, err)
+		require.Equal(t, uint64(2), cp.TransactionNumber)
+	})
+
+	t.Run("dead letter store error", func(t *testing.T) {
+		errExpected := fmt.Errorf("dead letter store error")
+
+		tp := &mocks.TxnProcessor{}
+		tp.ProcessReturns(fmt.Errorf("CAS unavailable"))
+
+		o := New(&Providers{
+			Ledger:                 mockLedger{registerForSidetreeTxnValue: make(chan []txn.SidetreeTxn, 100)},
+			ProtocolClientProvider: newProtocolClientProvider(tp),
+			DeadLetterStore:        &mockDeadLetterStore{err: errExpected},
+		}, WithMaxAttempts(1))
+
+		o.process(txns[:1])
+
+		// transaction is kept in the retry queue
+		require.Equal(t, 1, o.retries.len())
+
+		_, err := o.DeadLetters()
+		require.EqualError(t, err, errExpected.Error())
+
+		_, err = o.ReplayDeadLetters()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), errExpected.Error())
+	})
+
+	t.Run("backoff", func(t *testing.T) {
+		o := New(&Providers{}, WithRetryBackoff(time.Second, 5*time.Second))
+
+		require.Equal(t, time.Second, o.backoff(1))
+		require.Equal(t, 2*time.Second, o.backoff(2))
+		require.Equal(t, 4*time.Second, o.backoff(3))
+		require.Equal(t, 5*time.Second, o.backoff(4))
+		require.Equal(t, 5*time.Second, o.backoff(10))
+	})
 }
 
 func TestTxnProcessor_Process(t *testing.T) {
@@ -94,6 +501,85 @@ func TestTxnProcessor_Process(t *testing.T) {
 	})
 }
 
+type mockTxnReader struct {
+	txns []*txn.SidetreeTxn
+}
+
+// newMockTxnReader returns a reader with the given number of transactions (numbered from zero).
+func newMockTxnReader(namespace string, n int) *mockTxnReader {
+	r := &mockTxnReader{}
+
+	for i := 0; i < n; i++ {
+		r.txns = append(r.txns, &txn.SidetreeTxn{
+			Namespace:         namespace,
+			TransactionTime:   uint64(i),
+			TransactionNumber: uint64(i),
+			AnchorString:      fmt.Sprintf("%d.anchor", i),
+		})
+	}
+
+	return r
+}
+
+func (m *mockTxnReader) Read(sinceTransactionNumber int) (bool, *txn.SidetreeTxn) {
+	next := sinceTransactionNumber + 1
+	if next >= len(m.txns) {
+		return false, nil
+	}
+
+	return next < len(m.txns)-1, m.txns[next]
+}
+
+type mockDeadLetterStore struct {
+	err error
+}
+
+func (m *mockDeadLetterStore) Put(*FailedTxn) error {
+	return m.err
+}
+
+func (m *mockDeadLetterStore) GetAll() ([]*FailedTxn, error) {
+	return nil, m.err
+}
+
+func (m *mockDeadLetterStore) Delete(string, uint64) error {
+	return m.err
+}
+
+type mockCheckpointStore struct {
+	err error
+}
+
+func (m *mockCheckpointStore) Get(string) (*Checkpoint, error) {
+	return nil, m.err
+}
+
+func (m *mockCheckpointStore) Put(string, *Checkpoint) error {
+	return m.err
+}
+
+type mockStatusTracker struct {
+	err     error
+	mutex   sync.Mutex
+	anchors []string
+}
+
+func (m *mockStatusTracker) Processed(sidetreeTxn txn.SidetreeTxn) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.anchors = append(m.anchors, sidetreeTxn.AnchorString)
+
+	return m.err
+}
+
+func (m *mockStatusTracker) getAnchors() []string {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	return m.anchors
+}
+
 type mockLedger struct {
 	registerForSidetreeTxnValue chan []txn.SidetreeTxn
 }
@@ -127,7 +613,7 @@ type mockTxnOpsProvider struct {
 	err error
 }
 
-func (m *mockTxnOpsProvider) GetTxnOperations(txn *txn.SidetreeTxn) ([]*operation.AnchoredOperation, error) {
+func (m *mockTxnOpsProvider) GetTxnOperations(_ context.Context, txn *txn.SidetreeTxn, suffixes ...string) ([]*operation.AnchoredOperation, error) {
 	if m.err != nil {
 		return nil, m.err
 	}
@@ -0,0 +1,328 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package observer
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/trustbloc/sidetree-core-go/pkg/api/txn"
+)
+
+const (
+	defaultMaxAttempts    = 5
+	defaultInitialBackoff = time.Second
+	defaultMaxBackoff     = 5 * time.Minute
+	defaultBackoffFactor  = 2
+)
+
+// FailedTxn contains a transaction that failed processing.
+type FailedTxn struct {
+	// Txn is the transaction that failed processing.
+	Txn txn.SidetreeTxn `json:"txn"`
+
+	// Attempts is the number of processing attempts.
+	Attempts int `json:"attempts"`
+
+	// LastError is the error returned by the last processing attempt.
+	LastError string `json:"lastError"`
+
+	// Updated is the time of the last processing attempt.
+	Updated time.Time `json:"updated"`
+}
+
+// DeadLetterStore stores transactions that failed processing after the maximum number of attempts.
+type DeadLetterStore interface {
+	// Put stores the given failed transaction (replacing an existing entry for the same transaction).
+	Put(failed *FailedTxn) error
+	// GetAll returns all failed transactions ordered by namespace and transaction number.
+	GetAll() ([]*FailedTxn, error)
+	// Delete deletes the failed transaction with the given namespace and transaction number.
+	Delete(namespace string, transactionNumber uint64) error
+}
+
+// retryEntry is a transaction that is waiting to be retried.
+type retryEntry struct {
+	*FailedTxn
+
+	due time.Time
+}
+
+// retryQueue holds failed transactions per namespace in transaction order. Only the head entry of
+// a namespace is retried so that retries within a namespace happen in order.
+type retryQueue struct {
+	entries map[string][]*retryEntry
+}
+
+func newRetryQueue() *retryQueue {
+	return &retryQueue{entries: make(map[string][]*retryEntry)}
+}
+
+func (q *retryQueue) add(entry *retryEntry) {
+	entries := append(q.entries[entry.Txn.Namespace], entry)
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].Txn.TransactionNumber < entries[j].Txn.TransactionNumber
+	})
+
+	q.entries[entry.Txn.Namespace] = entries
+}
+
+// due returns the head entries of all namespaces that are due at the given time.
+func (q *retryQueue) due(now time.Time) []*retryEntry {
+	var due []*retryEntry
+
+	for _, entries := range q.entries {
+		if !entries[0].due.After(now) {
+			due = append(due, entries[0])
+		}
+	}
+
+	sort.Slice(due, func(i, j int) bool {
+		return due[i].Txn.Namespace < due[j].Txn.Namespace
+	})
+
+	return due
+}
+
+// next returns the earliest due time of all head entries.
+func (q *retryQueue) next() (time.Time, bool) {
+	var next time.Time
+
+	for _, entries := range q.entries {
+		if next.IsZero() || entries[0].due.Before(next) {
+			next = entries[0].due
+		}
+	}
+
+	return next, !next.IsZero()
+}
+
+func (q *retryQueue) remove(entry *retryEntry) {
+	entries := q.entries[entry.Txn.Namespace]
+
+	for i, e := range entries {
+		if e == entry {
+			entries = append(entries[:i], entries[i+1:]...)
+
+			break
+		}
+	}
+
+	if len(entries) == 0 {
+		delete(q.entries, entry.Txn.Namespace)
+
+		return
+	}
+
+	q.entries[entry.Txn.Namespace] = entries
+}
+
+// minPending returns the lowest transaction number that is pending retry for the given namespace.
+func (q *retryQueue) minPending(namespace string) (uint64, bool) {
+	entries := q.entries[namespace]
+	if len(entries) == 0 {
+		return 0, false
+	}
+
+	return entries[0].Txn.TransactionNumber, true
+}
+
+func (q *retryQueue) len() int {
+	n := 0
+
+	for _, entries := range q.entries {
+		n += len(entries)
+	}
+
+	return n
+}
+
+// MemDeadLetterStore implements an in-memory dead-letter store.
+type MemDeadLetterStore struct {
+	failed map[string]map[uint64]FailedTxn
+	mutex  sync.RWMutex
+}
+
+// NewMemDeadLetterStore returns a new in-memory dead-letter store.
+func NewMemDeadLetterStore() *MemDeadLetterStore {
+	return &MemDeadLetterStore{
+		failed: make(map[string]map[uint64]FailedTxn),
+	}
+}
+
+// Put stores the given failed transaction.
+func (s *MemDeadLetterStore) Put(failed *FailedTxn) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	txns, ok := s.failed[failed.Txn.Namespace]
+	if !ok {
+		txns = make(map[uint64]FailedTxn)
+		s.failed[failed.Txn.Namespace] = txns
+	}
+
+	txns[failed.Txn.TransactionNumber] = *failed
+
+	return nil
+}
+
+// GetAll returns all failed transactions ordered by namespace and transaction number.
+func (s *MemDeadLetterStore) GetAll() ([]*FailedTxn, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	var all []*FailedTxn
+
+	for _, txns := range s.failed {
+		for _, failed := range txns {
+			f := failed
+			all = append(all, &f)
+		}
+	}
+
+	sortFailedTxns(all)
+
+	return all, nil
+}
+
+// Delete deletes the failed transaction with the given namespace and transaction number.
+func (s *MemDeadLetterStore) Delete(namespace string, transactionNumber uint64) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	delete(s.failed[namespace], transactionNumber)
+
+	if len(s.failed[namespace]) == 0 {
+		delete(s.failed, namespace)
+	}
+
+	return nil
+}
+
+// FileDeadLetterStore implements a dead-letter store that persists the failed transactions of all namespaces
+// in a JSON file. The file is replaced atomically on each update. A durable dead-letter store is required if
+// the observer checkpoint is persisted since the checkpoint advances past dead-lettered transactions.
+type FileDeadLetterStore struct {
+	path   string
+	failed []*FailedTxn
+	mutex  sync.RWMutex
+}
+
+// NewFileDeadLetterStore returns a new dead-letter store backed by the file at the given path.
+// Existing failed transactions are loaded from the file.
+func NewFileDeadLetterStore(path string) (*FileDeadLetterStore, error) {
+	s := &FileDeadLetterStore{path: path}
+
+	content, err := ioutil.ReadFile(filepath.Clean(path))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return s, nil
+		}
+
+		return nil, fmt.Errorf("read dead-letter file[%s]: %w", path, err)
+	}
+
+	if err := json.Unmarshal(content, &s.failed); err != nil {
+		return nil, fmt.Errorf("unmarshal dead-letter file[%s]: %w", path, err)
+	}
+
+	sortFailedTxns(s.failed)
+
+	return s, nil
+}
+
+// Put stores the given failed transaction.
+func (s *FileDeadLetterStore) Put(failed *FailedTxn) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	f := *failed
+
+	updated := []*FailedTxn{&f}
+
+	for _, existing := range s.failed {
+		if !isSameTxn(existing, failed.Txn.Namespace, failed.Txn.TransactionNumber) {
+			updated = append(updated, existing)
+		}
+	}
+
+	sortFailedTxns(updated)
+
+	return s.save(updated)
+}
+
+// GetAll returns all failed transactions ordered by namespace and transaction number.
+func (s *FileDeadLetterStore) GetAll() ([]*FailedTxn, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	all := make([]*FailedTxn, len(s.failed))
+
+	for i, failed := range s.failed {
+		f := *failed
+		all[i] = &f
+	}
+
+	return all, nil
+}
+
+// Delete deletes the failed transaction with the given namespace and transaction number.
+func (s *FileDeadLetterStore) Delete(namespace string, transactionNumber uint64) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	var updated []*FailedTxn
+
+	for _, existing := range s.failed {
+		if !isSameTxn(existing, namespace, transactionNumber) {
+			updated = append(updated, existing)
+		}
+	}
+
+	if len(updated) == len(s.failed) {
+		return nil
+	}
+
+	return s.save(updated)
+}
+
+func (s *FileDeadLetterStore) save(failed []*FailedTxn) error {
+	content, err := json.Marshal(failed)
+	if err != nil {
+		return fmt.Errorf("marshal dead letters: %w", err)
+	}
+
+	if err := replaceFile(s.path, content, "dead-letter"); err != nil {
+		return err
+	}
+
+	s.failed = failed
+
+	return nil
+}
+
+func isSameTxn(failed *FailedTxn, namespace string, transactionNumber uint64) bool {
+	return failed.Txn.Namespace == namespace && failed.Txn.TransactionNumber == transactionNumber
+}
+
+// sortFailedTxns sorts the given failed transactions by namespace and transaction number.
+func sortFailedTxns(failed []*FailedTxn) {
+	sort.Slice(failed, func(i, j int) bool {
+		if failed[i].Txn.Namespace != failed[j].Txn.Namespace {
+			return failed[i].Txn.Namespace < failed[j].Txn.Namespace
+		}
+
+		return failed[i].Txn.TransactionNumber < failed[j].Txn.TransactionNumber
+	})
+}
@@ -0,0 +1,209 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package observer
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/sidetree-core-go/pkg/api/txn"
+)
+
+func TestRetryQueue(t *testing.T) {
+	q := newRetryQueue()
+
+	_, ok := q.next()
+	require.False(t, ok)
+
+	_, ok = q.minPending("ns1")
+	require.False(t, ok)
+
+	now := time.Now()
+
+	e1 := newRetryEntry("ns1", 5, now.Add(time.Minute))
+	e2 := newRetryEntry("ns1", 3, now.Add(time.Hour))
+	e3 := newRetryEntry("ns2", 7, now.Add(-time.Second))
+
+	q.add(e1)
+	q.add(e2)
+	q.add(e3)
+
+	require.Equal(t, 3, q.len())
+
+	// entries are ordered by transaction number within a namespace
+	n, ok := q.minPending("ns1")
+	require.True(t, ok)
+	require.Equal(t, uint64(3), n)
+
+	next, ok := q.next()
+	require.True(t, ok)
+	require.Equal(t, e3.due, next)
+
+	due := q.due(now)
+	require.Len(t, due, 1)
+	require.Equal(t, e3, due[0])
+
+	// e1 is due but it's not the head of namespace ns1
+	due = q.due(now.Add(2 * time.Minute))
+	require.Len(t, due, 1)
+	require.Equal(t, e3, due[0])
+
+	due = q.due(now.Add(2 * time.Hour))
+	require.Len(t, due, 2)
+	require.Equal(t, e2, due[0])
+	require.Equal(t, e3, due[1])
+
+	q.remove(e2)
+	q.remove(e3)
+
+	require.Equal(t, 1, q.len())
+
+	n, ok = q.minPending("ns1")
+	require.True(t, ok)
+	require.Equal(t, uint64(5), n)
+
+	_, ok = q.minPending("ns2")
+	require.False(t, ok)
+
+	q.remove(e1)
+	require.Zero(t, q.len())
+}
+
+func TestMemDeadLetterStore(t *testing.T) {
+	s := NewMemDeadLetterStore()
+
+	all, err := s.GetAll()
+	require.NoError(t, err)
+	require.Empty(t, all)
+
+	require.NoError(t, s.Put(&FailedTxn{Txn: txn.SidetreeTxn{Namespace: "ns2", TransactionNumber: 1}}))
+	require.NoError(t, s.Put(&FailedTxn{Txn: txn.SidetreeTxn{Namespace: "ns1", TransactionNumber: 5}}))
+	require.NoError(t, s.Put(&FailedTxn{Txn: txn.SidetreeTxn{Namespace: "ns1", TransactionNumber: 2}, Attempts: 1}))
+	require.NoError(t, s.Put(&FailedTxn{Txn: txn.SidetreeTxn{Namespace: "ns1", TransactionNumber: 2}, Attempts: 2}))
+
+	all, err = s.GetAll()
+	require.NoError(t, err)
+	require.Len(t, all, 3)
+	require.Equal(t, "ns1", all[0].Txn.Namespace)
+	require.Equal(t, uint64(2), all[0].Txn.TransactionNumber)
+	require.Equal(t, 2, all[0].Attempts)
+	require.Equal(t, uint64(5), all[1].Txn.TransactionNumber)
+	require.Equal(t, "ns2", all[2].Txn.Namespace)
+
+	require.NoError(t, s.Delete("ns1", 2))
+	require.NoError(t, s.Delete("ns2", 1))
+	require.NoError(t, s.Delete("ns3", 1))
+
+	all, err = s.GetAll()
+	require.NoError(t, err)
+	require.Len(t, all, 1)
+	require.Equal(t, uint64(5), all[0].Txn.TransactionNumber)
+}
+
+func TestFileDeadLetterStore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "deadletter")
+	require.NoError(t, err)
+
+	defer func() {
+		require.NoError(t, os.RemoveAll(dir))
+	}()
+
+	path := filepath.Join(dir, "observer", "deadletters.json")
+
+	t.Run("success", func(t *testing.T) {
+		s, err := NewFileDeadLetterStore(path)
+		require.NoError(t, err)
+
+		all, err := s.GetAll()
+		require.NoError(t, err)
+		require.Empty(t, all)
+
+		require.NoError(t, s.Put(&FailedTxn{Txn: txn.SidetreeTxn{Namespace: "ns2", TransactionNumber: 1}}))
+		require.NoError(t, s.Put(&FailedTxn{Txn: txn.SidetreeTxn{Namespace: "ns1", TransactionNumber: 5}}))
+		require.NoError(t, s.Put(&FailedTxn{Txn: txn.SidetreeTxn{Namespace: "ns1", TransactionNumber: 2}, Attempts: 1}))
+		require.NoError(t, s.Put(&FailedTxn{
+			Txn:       txn.SidetreeTxn{Namespace: "ns1", TransactionNumber: 2, AnchorString: "2.anchor"},
+			Attempts:  2,
+			LastError: "CAS unavailable",
+		}))
+
+		// failed transactions are loaded from file
+		s, err = NewFileDeadLetterStore(path)
+		require.NoError(t, err)
+
+		all, err = s.GetAll()
+		require.NoError(t, err)
+		require.Len(t, all, 3)
+		require.Equal(t, "ns1", all[0].Txn.Namespace)
+		require.Equal(t, uint64(2), all[0].Txn.TransactionNumber)
+		require.Equal(t, "2.anchor", all[0].Txn.AnchorString)
+		require.Equal(t, 2, all[0].Attempts)
+		require.Equal(t, "CAS unavailable", all[0].LastError)
+		require.Equal(t, uint64(5), all[1].Txn.TransactionNumber)
+		require.Equal(t, "ns2", all[2].Txn.Namespace)
+
+		// returned transactions are copies
+		all[0].Attempts = 10
+
+		require.NoError(t, s.Delete("ns1", 2))
+		require.NoError(t, s.Delete("ns2", 1))
+		require.NoError(t, s.Delete("ns3", 1))
+
+		s, err = NewFileDeadLetterStore(path)
+		require.NoError(t, err)
+
+		all, err = s.GetAll()
+		require.NoError(t, err)
+		require.Len(t, all, 1)
+		require.Equal(t, uint64(5), all[0].Txn.TransactionNumber)
+	})
+
+	t.Run("error - invalid file", func(t *testing.T) {
+		invalidPath := filepath.Join(dir, "invalid.json")
+		require.NoError(t, ioutil.WriteFile(invalidPath, []byte("{"), 0o600))
+
+		s, err := NewFileDeadLetterStore(invalidPath)
+		require.Error(t, err)
+		require.Nil(t, s)
+		require.Contains(t, err.Error(), "unmarshal dead-letter file")
+	})
+
+	t.Run("error - read file", func(t *testing.T) {
+		s, err := NewFileDeadLetterStore(dir)
+		require.Error(t, err)
+		require.Nil(t, s)
+		require.Contains(t, err.Error(), "read dead-letter file")
+	})
+
+	t.Run("error - write file", func(t *testing.T) {
+		s, err := NewFileDeadLetterStore(filepath.Join(dir, "file", "deadletters.json"))
+		require.NoError(t, err)
+
+		// parent directory can't be created since a file with the same name exists
+		require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "file"), []byte("{}"), 0o600))
+
+		err = s.Put(&FailedTxn{Txn: txn.SidetreeTxn{Namespace: "ns1", TransactionNumber: 1}})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "create dead-letter directory")
+
+		all, err := s.GetAll()
+		require.NoError(t, err)
+		require.Empty(t, all)
+	})
+}
+
+func newRetryEntry(namespace string, txnNumber uint64, due time.Time) *retryEntry {
+	return &retryEntry{
+		FailedTxn: &FailedTxn{Txn: txn.SidetreeTxn{Namespace: namespace, TransactionNumber: txnNumber}},
+		due:       due,
+	}
+}
@@ -0,0 +1,136 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package opfilter contains operation filters that may be applied by the transaction processor to the
+// operations of each unique suffix before they are persisted to the operation store.
+package opfilter
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+
+	"github.com/trustbloc/edge-core/pkg/log"
+
+	"github.com/trustbloc/sidetree-core-go/pkg/api/operation"
+	"github.com/trustbloc/sidetree-core-go/pkg/api/protocol"
+	"github.com/trustbloc/sidetree-core-go/pkg/document"
+)
+
+var logger = log.New("sidetree-core-opfilter")
+
+// DocumentResolver resolves the current state of a document.
+type DocumentResolver interface {
+	Resolve(uniqueSuffix string, opts ...document.ResolutionOption) (*protocol.ResolutionModel, error)
+}
+
+// OperationStore retrieves the operations that were persisted for a document.
+type OperationStore interface {
+	Get(uniqueSuffix string) ([]*operation.AnchoredOperation, error)
+}
+
+// DeactivatedFilter drops all operations for a suffix whose document has already been deactivated.
+type DeactivatedFilter struct {
+	resolver DocumentResolver
+}
+
+// NewDeactivatedFilter returns a new filter that drops operations for deactivated documents.
+func NewDeactivatedFilter(resolver DocumentResolver) *DeactivatedFilter {
+	return &DeactivatedFilter{resolver: resolver}
+}
+
+// Filter returns no operations if the document with the given suffix is deactivated. If the document doesn't
+// exist yet then all operations are returned. Any other resolution error is returned so that the transaction
+// is processed again later.
+func (f *DeactivatedFilter) Filter(uniqueSuffix string, ops []*operation.AnchoredOperation) ([]*operation.AnchoredOperation, error) {
+	rm, err := f.resolver.Resolve(uniqueSuffix)
+	if err != nil {
+		if errors.Is(err, operation.ErrNotFound) || errors.Is(err, document.ErrNotFound) {
+			return ops, nil
+		}
+
+		return nil, fmt.Errorf("resolve suffix[%s]: %w", uniqueSuffix, err)
+	}
+
+	if rm.Deactivated {
+		logger.Infof("Dropping %d operation(s) for deactivated suffix[%s]", len(ops), uniqueSuffix)
+
+		return nil, nil
+	}
+
+	return ops, nil
+}
+
+// DuplicateFilter drops operations that were already persisted for a suffix. Operations are compared
+// by the hash of the operation request.
+type DuplicateFilter struct {
+	store OperationStore
+}
+
+// NewDuplicateFilter returns a new filter that drops operations that already exist in the given store.
+func NewDuplicateFilter(store OperationStore) *DuplicateFilter {
+	return &DuplicateFilter{store: store}
+}
+
+// Filter returns the operations that haven't been persisted for the given suffix.
+func (f *DuplicateFilter) Filter(uniqueSuffix string, ops []*operation.AnchoredOperation) ([]*operation.AnchoredOperation, error) {
+	storedOps, err := f.store.Get(uniqueSuffix)
+	if err != nil {
+		if errors.Is(err, operation.ErrNotFound) {
+			return ops, nil
+		}
+
+		return nil, fmt.Errorf("get operations for suffix[%s]: %w", uniqueSuffix, err)
+	}
+
+	stored := make(map[[sha256.Size]byte]struct{}, len(storedOps))
+
+	for _, op := range storedOps {
+		stored[sha256.Sum256(op.OperationBuffer)] = struct{}{}
+	}
+
+	var filtered []*operation.AnchoredOperation
+
+	for _, op := range ops {
+		if _, ok := stored[sha256.Sum256(op.OperationBuffer)]; ok {
+			logger.Infof("Dropping duplicate %s operation for suffix[%s]", op.Type, uniqueSuffix)
+
+			continue
+		}
+
+		filtered = append(filtered, op)
+	}
+
+	return filtered, nil
+}
+
+// AllowListFilter drops operations for suffixes that are not in the allow-list. This filter may be used
+// by partial replicas that only store operations for a subset of documents.
+type AllowListFilter struct {
+	suffixes map[string]struct{}
+}
+
+// NewAllowListFilter returns a new filter that only allows operations for the given suffixes.
+func NewAllowListFilter(suffixes ...string) *AllowListFilter {
+	allowed := make(map[string]struct{}, len(suffixes))
+
+	for _, suffix := range suffixes {
+		allowed[suffix] = struct{}{}
+	}
+
+	return &AllowListFilter{suffixes: allowed}
+}
+
+// Filter returns the given operations if the suffix is in the allow-list; otherwise no operations are returned.
+func (f *AllowListFilter) Filter(uniqueSuffix string, ops []*operation.AnchoredOperation) ([]*operation.AnchoredOperation, error) {
+	if _, ok := f.suffixes[uniqueSuffix]; !ok {
+		logger.Debugf("Dropping %d operation(s) for suffix[%s] which is not in the allow-list", len(ops), uniqueSuffix)
+
+		return nil, nil
+	}
+
+	return ops, nil
+}
@@ -0,0 +1,142 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package opfilter
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/sidetree-core-go/pkg/api/operation"
+	"github.com/trustbloc/sidetree-core-go/pkg/api/protocol"
+	"github.com/trustbloc/sidetree-core-go/pkg/document"
+)
+
+const suffix = "suffix"
+
+func TestDeactivatedFilter(t *testing.T) {
+	ops := []*operation.AnchoredOperation{{UniqueSuffix: suffix, Type: operation.TypeUpdate}}
+
+	t.Run("deactivated", func(t *testing.T) {
+		f := NewDeactivatedFilter(&mockResolver{rm: &protocol.ResolutionModel{Deactivated: true}})
+
+		filtered, err := f.Filter(suffix, ops)
+		require.NoError(t, err)
+		require.Empty(t, filtered)
+	})
+
+	t.Run("not deactivated", func(t *testing.T) {
+		f := NewDeactivatedFilter(&mockResolver{rm: &protocol.ResolutionModel{}})
+
+		filtered, err := f.Filter(suffix, ops)
+		require.NoError(t, err)
+		require.Equal(t, ops, filtered)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		f := NewDeactivatedFilter(&mockResolver{err: fmt.Errorf("get operations: %w", operation.ErrNotFound)})
+
+		filtered, err := f.Filter(suffix, ops)
+		require.NoError(t, err)
+		require.Equal(t, ops, filtered)
+
+		f = NewDeactivatedFilter(&mockResolver{err: document.ErrNotFound})
+
+		filtered, err = f.Filter(suffix, ops)
+		require.NoError(t, err)
+		require.Equal(t, ops, filtered)
+	})
+
+	t.Run("resolve error", func(t *testing.T) {
+		f := NewDeactivatedFilter(&mockResolver{err: errors.New("store error")})
+
+		filtered, err := f.Filter(suffix, ops)
+		require.Error(t, err)
+		require.Nil(t, filtered)
+		require.Contains(t, err.Error(), "resolve suffix[suffix]: store error")
+	})
+}
+
+func TestDuplicateFilter(t *testing.T) {
+	op1 := &operation.AnchoredOperation{UniqueSuffix: suffix, Type: operation.TypeCreate, OperationBuffer: []byte("op1")}
+	op2 := &operation.AnchoredOperation{UniqueSuffix: suffix, Type: operation.TypeUpdate, OperationBuffer: []byte("op2")}
+	op3 := &operation.AnchoredOperation{UniqueSuffix: suffix, Type: operation.TypeUpdate, OperationBuffer: []byte("op3")}
+
+	t.Run("duplicates are dropped", func(t *testing.T) {
+		f := NewDuplicateFilter(&mockStore{ops: []*operation.AnchoredOperation{
+			{UniqueSuffix: suffix, Type: operation.TypeCreate, OperationBuffer: []byte("op1"), TransactionNumber: 1},
+			{UniqueSuffix: suffix, Type: operation.TypeUpdate, OperationBuffer: []byte("op3"), TransactionNumber: 2},
+		}})
+
+		filtered, err := f.Filter(suffix, []*operation.AnchoredOperation{op1, op2, op3})
+		require.NoError(t, err)
+		require.Equal(t, []*operation.AnchoredOperation{op2}, filtered)
+	})
+
+	t.Run("no stored operations", func(t *testing.T) {
+		f := NewDuplicateFilter(&mockStore{})
+
+		filtered, err := f.Filter(suffix, []*operation.AnchoredOperation{op1, op2})
+		require.NoError(t, err)
+		require.Equal(t, []*operation.AnchoredOperation{op1, op2}, filtered)
+	})
+
+	t.Run("suffix not found", func(t *testing.T) {
+		f := NewDuplicateFilter(&mockStore{err: operation.ErrNotFound})
+
+		filtered, err := f.Filter(suffix, []*operation.AnchoredOperation{op1})
+		require.NoError(t, err)
+		require.Equal(t, []*operation.AnchoredOperation{op1}, filtered)
+	})
+
+	t.Run("store error", func(t *testing.T) {
+		f := NewDuplicateFilter(&mockStore{err: errors.New("injected store error")})
+
+		filtered, err := f.Filter(suffix, []*operation.AnchoredOperation{op1})
+		require.Error(t, err)
+		require.Nil(t, filtered)
+		require.Contains(t, err.Error(), "get operations for suffix[suffix]: injected store error")
+	})
+}
+
+func TestAllowListFilter(t *testing.T) {
+	ops := []*operation.AnchoredOperation{{UniqueSuffix: suffix, Type: operation.TypeCreate}}
+
+	f := NewAllowListFilter(suffix, "other")
+
+	filtered, err := f.Filter(suffix, ops)
+	require.NoError(t, err)
+	require.Equal(t, ops, filtered)
+
+	filtered, err = f.Filter("unknown", ops)
+	require.NoError(t, err)
+	require.Empty(t, filtered)
+
+	filtered, err = NewAllowListFilter().Filter(suffix, ops)
+	require.NoError(t, err)
+	require.Empty(t, filtered)
+}
+
+type mockResolver struct {
+	rm  *protocol.ResolutionModel
+	err error
+}
+
+func (m *mockResolver) Resolve(string, ...document.ResolutionOption) (*protocol.ResolutionModel, error) {
+	return m.rm, m.err
+}
+
+type mockStore struct {
+	ops []*operation.AnchoredOperation
+	err error
+}
+
+func (m *mockStore) Get(string) ([]*operation.AnchoredOperation, error) {
+	return m.ops, m.err
+}
@@ -0,0 +1,127 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package opstatus
+
+import (
+	"sync"
+)
+
+const defaultMaxEntries = 10000
+
+// MemStoreOption is an in-memory status store option.
+type MemStoreOption func(opts *MemStore)
+
+// WithMaxEntries sets the maximum number of operation statuses held by the store. When the maximum
+// is reached, the oldest statuses are evicted.
+func WithMaxEntries(maxEntries int) MemStoreOption {
+	return func(opts *MemStore) {
+		opts.maxEntries = maxEntries
+	}
+}
+
+// MemStore implements an in-memory operation status store.
+type MemStore struct {
+	statuses   map[string]OperationStatus
+	anchors    map[string]map[string]struct{}
+	order      []string
+	maxEntries int
+	mutex      sync.RWMutex
+}
+
+// NewMemStore returns a new in-memory operation status store.
+func NewMemStore(opts ...MemStoreOption) *MemStore {
+	s := &MemStore{
+		statuses:   make(map[string]OperationStatus),
+		anchors:    make(map[string]map[string]struct{}),
+		maxEntries: defaultMaxEntries,
+	}
+
+	// apply options
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
+}
+
+// Put stores the given operation status.
+func (s *MemStore) Put(status *OperationStatus) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	existing, ok := s.statuses[status.ID]
+	if ok {
+		s.removeFromAnchor(existing.AnchorString, existing.ID)
+	} else {
+		s.order = append(s.order, status.ID)
+	}
+
+	s.statuses[status.ID] = *status
+
+	if status.AnchorString != "" {
+		ids, ok := s.anchors[status.AnchorString]
+		if !ok {
+			ids = make(map[string]struct{})
+			s.anchors[status.AnchorString] = ids
+		}
+
+		ids[status.ID] = struct{}{}
+	}
+
+	for len(s.order) > s.maxEntries {
+		evicted := s.statuses[s.order[0]]
+
+		s.removeFromAnchor(evicted.AnchorString, evicted.ID)
+		delete(s.statuses, evicted.ID)
+
+		s.order = s.order[1:]
+	}
+
+	return nil
+}
+
+// Get returns the status of the operation with the given ID.
+func (s *MemStore) Get(id string) (*OperationStatus, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	status, ok := s.statuses[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+
+	return &status, nil
+}
+
+// GetByAnchor returns the status of all operations that were anchored with the given anchor string.
+func (s *MemStore) GetByAnchor(anchorString string) ([]*OperationStatus, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	var statuses []*OperationStatus
+
+	for id := range s.anchors[anchorString] {
+		status := s.statuses[id]
+
+		statuses = append(statuses, &status)
+	}
+
+	return statuses, nil
+}
+
+func (s *MemStore) removeFromAnchor(anchorString, id string) {
+	ids, ok := s.anchors[anchorString]
+	if !ok {
+		return
+	}
+
+	delete(ids, id)
+
+	if len(ids) == 0 {
+		delete(s.anchors, anchorString)
+	}
+}
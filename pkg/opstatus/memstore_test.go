@@ -0,0 +1,81 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package opstatus
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemStore(t *testing.T) {
+	s := NewMemStore()
+
+	status, err := s.Get("id1")
+	require.True(t, errors.Is(err, ErrNotFound))
+	require.Nil(t, status)
+
+	require.NoError(t, s.Put(&OperationStatus{ID: "id1", Status: StatusQueued}))
+	require.NoError(t, s.Put(&OperationStatus{ID: "id2", Status: StatusAnchored, AnchorString: "anchor1"}))
+	require.NoError(t, s.Put(&OperationStatus{ID: "id3", Status: StatusAnchored, AnchorString: "anchor1"}))
+
+	status, err = s.Get("id1")
+	require.NoError(t, err)
+	require.Equal(t, StatusQueued, status.Status)
+
+	// returned status is a copy
+	status.Status = StatusProcessed
+
+	status, err = s.Get("id1")
+	require.NoError(t, err)
+	require.Equal(t, StatusQueued, status.Status)
+
+	statuses, err := s.GetByAnchor("anchor1")
+	require.NoError(t, err)
+	require.Len(t, statuses, 2)
+
+	statuses, err = s.GetByAnchor("anchor2")
+	require.NoError(t, err)
+	require.Empty(t, statuses)
+
+	// operation is placed back in the queue
+	require.NoError(t, s.Put(&OperationStatus{ID: "id2", Status: StatusQueued}))
+
+	statuses, err = s.GetByAnchor("anchor1")
+	require.NoError(t, err)
+	require.Len(t, statuses, 1)
+	require.Equal(t, "id3", statuses[0].ID)
+}
+
+func TestMemStore_MaxEntries(t *testing.T) {
+	s := NewMemStore(WithMaxEntries(2))
+
+	require.NoError(t, s.Put(&OperationStatus{ID: "id1", AnchorString: "anchor1"}))
+	require.NoError(t, s.Put(&OperationStatus{ID: "id2"}))
+
+	// updating an existing status doesn't evict anything
+	require.NoError(t, s.Put(&OperationStatus{ID: "id1", AnchorString: "anchor1", Status: StatusProcessed}))
+
+	_, err := s.Get("id1")
+	require.NoError(t, err)
+
+	require.NoError(t, s.Put(&OperationStatus{ID: "id3"}))
+
+	_, err = s.Get("id1")
+	require.True(t, errors.Is(err, ErrNotFound))
+
+	statuses, err := s.GetByAnchor("anchor1")
+	require.NoError(t, err)
+	require.Empty(t, statuses)
+
+	_, err = s.Get("id2")
+	require.NoError(t, err)
+
+	_, err = s.Get("id3")
+	require.NoError(t, err)
+}
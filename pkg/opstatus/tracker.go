@@ -0,0 +1,224 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package opstatus tracks the status of operations from the time they are accepted by the node until
+// they are processed by the observer.
+//
+// Each accepted operation is identified by the operation hash (see GetOperationID). The status of the
+// operation transitions as follows:
+//
+// 1) queued - the operation was added to the batch writer queue
+// 2) batched - the operation was cut from the queue into a batch
+// 3) anchored - the batch files were stored in CAS and the anchor string was written to the anchoring system
+// 4) processed - the observer processed the Sidetree transaction which contains the operation
+//
+// If anchoring of a batch fails then the operations are placed back in the queue and their status is set to queued.
+package opstatus
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/trustbloc/edge-core/pkg/log"
+
+	"github.com/trustbloc/sidetree-core-go/pkg/api/operation"
+	"github.com/trustbloc/sidetree-core-go/pkg/api/protocol"
+	"github.com/trustbloc/sidetree-core-go/pkg/api/txn"
+	"github.com/trustbloc/sidetree-core-go/pkg/encoder"
+	"github.com/trustbloc/sidetree-core-go/pkg/hashing"
+)
+
+var logger = log.New("sidetree-core-opstatus")
+
+// sha2_256 is the multihash algorithm used for computing operation IDs.
+const sha2_256 = 18
+
+// ErrNotFound is returned when the status for the given operation ID is not found.
+var ErrNotFound = errors.New("operation status not found")
+
+// Status defines valid values for operation status.
+type Status string
+
+const (
+	// StatusQueued indicates that the operation was added to the batch writer queue.
+	StatusQueued Status = "queued"
+
+	// StatusBatched indicates that the operation was cut into a batch.
+	StatusBatched Status = "batched"
+
+	// StatusAnchored indicates that the batch containing the operation was anchored.
+	StatusAnchored Status = "anchored"
+
+	// StatusProcessed indicates that the observer processed the transaction containing the operation.
+	StatusProcessed Status = "processed"
+)
+
+// AnchorDocument contains the URI and type of a batch file that contains the operation.
+type AnchorDocument struct {
+	ID   string                      `json:"id"`
+	Type protocol.AnchorDocumentType `json:"type"`
+}
+
+// OperationStatus contains the status of an operation.
+type OperationStatus struct {
+	// ID is the operation ID (operation hash).
+	ID string `json:"id"`
+
+	// Namespace is the namespace of the operation.
+	Namespace string `json:"namespace"`
+
+	// UniqueSuffix is the unique suffix of the document that the operation applies to.
+	UniqueSuffix string `json:"didSuffix"`
+
+	// Status is the current status of the operation.
+	Status Status `json:"status"`
+
+	// AnchorString is the anchor string of the transaction containing the operation (set once anchored).
+	AnchorString string `json:"anchorString,omitempty"`
+
+	// AnchorDocuments are the batch files created for the batch containing the operation (set once anchored).
+	AnchorDocuments []*AnchorDocument `json:"anchorDocuments,omitempty"`
+
+	// TransactionTime is the time of the transaction containing the operation (set once processed).
+	TransactionTime uint64 `json:"transactionTime,omitempty"`
+
+	// TransactionNumber is the number of the transaction containing the operation (set once processed).
+	TransactionNumber uint64 `json:"transactionNumber,omitempty"`
+
+	// Updated is the time of the last status transition.
+	Updated time.Time `json:"updated"`
+}
+
+// Store persists operation status.
+type Store interface {
+	// Put stores the given operation status (replacing existing status for the same ID).
+	Put(status *OperationStatus) error
+	// Get returns the status of the operation with the given ID or ErrNotFound.
+	Get(id string) (*OperationStatus, error)
+	// GetByAnchor returns the status of all operations that were anchored with the given anchor string.
+	GetByAnchor(anchorString string) ([]*OperationStatus, error)
+}
+
+// GetOperationID returns the ID of the operation in the given operation request. The ID is the
+// base64url encoded multihash (sha2-256) of the operation request.
+func GetOperationID(operationBuffer []byte) (string, error) {
+	mh, err := hashing.ComputeMultihash(sha2_256, operationBuffer)
+	if err != nil {
+		return "", err
+	}
+
+	return encoder.EncodeToString(mh), nil
+}
+
+// Tracker records operation status transitions in the status store.
+type Tracker struct {
+	store Store
+	now   func() time.Time
+}
+
+// New returns a new operation status tracker.
+func New(store Store) *Tracker {
+	return &Tracker{
+		store: store,
+		now:   time.Now,
+	}
+}
+
+// Get returns the status of the operation with the given ID.
+func (t *Tracker) Get(id string) (*OperationStatus, error) {
+	return t.store.Get(id)
+}
+
+// Queued records that the given operations were added to the batch writer queue.
+func (t *Tracker) Queued(ops []*operation.QueuedOperation) error {
+	return t.update(ops, func(status *OperationStatus) {
+		status.Status = StatusQueued
+		status.AnchorString = ""
+		status.AnchorDocuments = nil
+	})
+}
+
+// Batched records that the given operations were cut into a batch.
+func (t *Tracker) Batched(ops []*operation.QueuedOperation) error {
+	return t.update(ops, func(status *OperationStatus) {
+		status.Status = StatusBatched
+	})
+}
+
+// Anchored records that the given operations were anchored with the given anchor string. The anchor documents
+// are the batch files that were created for the batch.
+func (t *Tracker) Anchored(ops []*operation.QueuedOperation, anchorString string, artifacts []*protocol.AnchorDocument) error {
+	anchorDocs := make([]*AnchorDocument, len(artifacts))
+
+	for i, artifact := range artifacts {
+		anchorDocs[i] = &AnchorDocument{ID: artifact.ID, Type: artifact.Type}
+	}
+
+	return t.update(ops, func(status *OperationStatus) {
+		status.Status = StatusAnchored
+		status.AnchorString = anchorString
+		status.AnchorDocuments = anchorDocs
+	})
+}
+
+// Processed records that the observer processed the given transaction. The status of all operations that
+// were anchored with the transaction's anchor string is set to processed.
+func (t *Tracker) Processed(sidetreeTxn txn.SidetreeTxn) error {
+	statuses, err := t.store.GetByAnchor(sidetreeTxn.AnchorString)
+	if err != nil {
+		return fmt.Errorf("get operation status for anchor[%s]: %w", sidetreeTxn.AnchorString, err)
+	}
+
+	for _, status := range statuses {
+		status.Status = StatusProcessed
+		status.TransactionTime = sidetreeTxn.TransactionTime
+		status.TransactionNumber = sidetreeTxn.TransactionNumber
+		status.Updated = t.now()
+
+		if err := t.store.Put(status); err != nil {
+			return fmt.Errorf("store operation status[%s]: %w", status.ID, err)
+		}
+	}
+
+	logger.Debugf("Marked %d operations for anchor[%s] as processed", len(statuses), sidetreeTxn.AnchorString)
+
+	return nil
+}
+
+func (t *Tracker) update(ops []*operation.QueuedOperation, apply func(status *OperationStatus)) error {
+	for _, op := range ops {
+		id, err := GetOperationID(op.OperationBuffer)
+		if err != nil {
+			return fmt.Errorf("get operation ID for suffix[%s]: %w", op.UniqueSuffix, err)
+		}
+
+		status := &OperationStatus{
+			ID:           id,
+			Namespace:    op.Namespace,
+			UniqueSuffix: op.UniqueSuffix,
+		}
+
+		existing, err := t.store.Get(id)
+		if err == nil {
+			status = existing
+		} else if !errors.Is(err, ErrNotFound) {
+			return fmt.Errorf("get operation status[%s]: %w", id, err)
+		}
+
+		apply(status)
+
+		status.Updated = t.now()
+
+		if err := t.store.Put(status); err != nil {
+			return fmt.Errorf("store operation status[%s]: %w", id, err)
+		}
+
+		logger.Debugf("Operation[%s] for suffix[%s] is %s", id, op.UniqueSuffix, status.Status)
+	}
+
+	return nil
+}
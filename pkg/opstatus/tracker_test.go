@@ -0,0 +1,185 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package opstatus
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/sidetree-core-go/pkg/api/operation"
+	"github.com/trustbloc/sidetree-core-go/pkg/api/protocol"
+	"github.com/trustbloc/sidetree-core-go/pkg/api/txn"
+)
+
+const (
+	namespace    = "did:sidetree"
+	anchorString = "1.coreIndexURI"
+)
+
+var (
+	op1 = &operation.QueuedOperation{Namespace: namespace, UniqueSuffix: "suffix1", OperationBuffer: []byte("op1")}
+	op2 = &operation.QueuedOperation{Namespace: namespace, UniqueSuffix: "suffix2", OperationBuffer: []byte("op2")}
+)
+
+func TestGetOperationID(t *testing.T) {
+	id1, err := GetOperationID(op1.OperationBuffer)
+	require.NoError(t, err)
+	require.NotEmpty(t, id1)
+
+	id2, err := GetOperationID(op2.OperationBuffer)
+	require.NoError(t, err)
+	require.NotEqual(t, id1, id2)
+
+	id, err := GetOperationID([]byte("op1"))
+	require.NoError(t, err)
+	require.Equal(t, id1, id)
+}
+
+func TestTracker(t *testing.T) {
+	tracker := New(NewMemStore())
+
+	id1, err := GetOperationID(op1.OperationBuffer)
+	require.NoError(t, err)
+
+	id2, err := GetOperationID(op2.OperationBuffer)
+	require.NoError(t, err)
+
+	status, err := tracker.Get(id1)
+	require.True(t, errors.Is(err, ErrNotFound))
+	require.Nil(t, status)
+
+	ops := []*operation.QueuedOperation{op1, op2}
+
+	require.NoError(t, tracker.Queued(ops))
+
+	status, err = tracker.Get(id1)
+	require.NoError(t, err)
+	require.Equal(t, StatusQueued, status.Status)
+	require.Equal(t, namespace, status.Namespace)
+	require.Equal(t, op1.UniqueSuffix, status.UniqueSuffix)
+	require.False(t, status.Updated.IsZero())
+
+	require.NoError(t, tracker.Batched(ops))
+
+	status, err = tracker.Get(id2)
+	require.NoError(t, err)
+	require.Equal(t, StatusBatched, status.Status)
+
+	artifacts := []*protocol.AnchorDocument{
+		{ID: "coreIndexURI", Desc: "core index", Type: protocol.TypePermanent},
+		{ID: "chunkURI", Desc: "chunk", Type: protocol.TypeProvisional},
+	}
+
+	require.NoError(t, tracker.Anchored(ops, anchorString, artifacts))
+
+	status, err = tracker.Get(id1)
+	require.NoError(t, err)
+	require.Equal(t, StatusAnchored, status.Status)
+	require.Equal(t, anchorString, status.AnchorString)
+	require.Len(t, status.AnchorDocuments, 2)
+	require.Equal(t, "coreIndexURI", status.AnchorDocuments[0].ID)
+	require.Equal(t, protocol.TypePermanent, status.AnchorDocuments[0].Type)
+	require.Equal(t, "chunkURI", status.AnchorDocuments[1].ID)
+
+	// transaction for some other anchor
+	require.NoError(t, tracker.Processed(txn.SidetreeTxn{AnchorString: "other"}))
+
+	status, err = tracker.Get(id1)
+	require.NoError(t, err)
+	require.Equal(t, StatusAnchored, status.Status)
+
+	require.NoError(t, tracker.Processed(txn.SidetreeTxn{
+		AnchorString:      anchorString,
+		TransactionTime:   100,
+		TransactionNumber: 5,
+	}))
+
+	for _, id := range []string{id1, id2} {
+		status, err = tracker.Get(id)
+		require.NoError(t, err)
+		require.Equal(t, StatusProcessed, status.Status)
+		require.Equal(t, anchorString, status.AnchorString)
+		require.Equal(t, uint64(100), status.TransactionTime)
+		require.Equal(t, uint64(5), status.TransactionNumber)
+	}
+}
+
+func TestTracker_Requeued(t *testing.T) {
+	tracker := New(NewMemStore())
+
+	ops := []*operation.QueuedOperation{op1}
+
+	require.NoError(t, tracker.Anchored(ops, anchorString, nil))
+	require.NoError(t, tracker.Queued(ops))
+
+	id, err := GetOperationID(op1.OperationBuffer)
+	require.NoError(t, err)
+
+	status, err := tracker.Get(id)
+	require.NoError(t, err)
+	require.Equal(t, StatusQueued, status.Status)
+	require.Empty(t, status.AnchorString)
+	require.Empty(t, status.AnchorDocuments)
+}
+
+func TestTracker_Error(t *testing.T) {
+	errExpected := errors.New("injected store error")
+
+	ops := []*operation.QueuedOperation{op1}
+
+	t.Run("get error", func(t *testing.T) {
+		tracker := New(&mockStore{getErr: errExpected})
+
+		err := tracker.Queued(ops)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), errExpected.Error())
+	})
+
+	t.Run("put error", func(t *testing.T) {
+		tracker := New(&mockStore{putErr: errExpected, getErr: ErrNotFound})
+
+		err := tracker.Queued(ops)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), errExpected.Error())
+
+		err = tracker.Processed(txn.SidetreeTxn{AnchorString: anchorString})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), errExpected.Error())
+	})
+
+	t.Run("get by anchor error", func(t *testing.T) {
+		tracker := New(&mockStore{getByAnchorErr: errExpected})
+
+		err := tracker.Processed(txn.SidetreeTxn{AnchorString: anchorString})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), errExpected.Error())
+	})
+}
+
+type mockStore struct {
+	putErr         error
+	getErr         error
+	getByAnchorErr error
+}
+
+func (m *mockStore) Put(*OperationStatus) error {
+	return m.putErr
+}
+
+func (m *mockStore) Get(string) (*OperationStatus, error) {
+	return nil, m.getErr
+}
+
+func (m *mockStore) GetByAnchor(string) ([]*OperationStatus, error) {
+	if m.getByAnchorErr != nil {
+		return nil, m.getByAnchorErr
+	}
+
+	return []*OperationStatus{{ID: "id"}}, nil
+}
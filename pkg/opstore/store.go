@@ -0,0 +1,239 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package opstore implements a persistent operation store which is embedded in the node.
+//
+// Operations are stored in an embedded key/value database (bbolt). The 'operations' bucket holds the
+// operations keyed by unique suffix followed by the anchoring position (transaction time and number) and the
+// operation hash, so the operations of a suffix are read with a single prefix scan in anchoring order.
+// The 'hashes' bucket maps each operation hash to its key in the 'operations' bucket and is used to detect
+// duplicate operations. The operations of each Put are written in a single database transaction.
+package opstore
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/trustbloc/edge-core/pkg/log"
+	"go.etcd.io/bbolt"
+
+	"github.com/trustbloc/sidetree-core-go/pkg/api/operation"
+)
+
+var logger = log.New("sidetree-core-opstore")
+
+const (
+	// openTimeout is the time to wait for the file lock of the database (held by another process).
+	openTimeout = 5 * time.Second
+
+	// keySeparator separates the unique suffix from the anchoring position in an operation key.
+	keySeparator = 0
+
+	// positionSize is the size of the anchoring position (transaction time and number) in an operation key.
+	positionSize = 16
+)
+
+var (
+	operationsBucket = []byte("operations")
+	hashesBucket     = []byte("hashes")
+)
+
+// Store implements a persistent operation store backed by an embedded key/value database.
+type Store struct {
+	path  string
+	db    *bbolt.DB
+	mutex sync.RWMutex
+}
+
+// New opens (or creates) the operation store at the given path.
+func New(path string) (*Store, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return nil, fmt.Errorf("create operation store directory: %w", err)
+	}
+
+	db, err := bbolt.Open(filepath.Clean(path), 0o600, &bbolt.Options{Timeout: openTimeout})
+	if err != nil {
+		return nil, fmt.Errorf("open operation store[%s]: %w", path, err)
+	}
+
+	err = db.Update(func(tx *bbolt.Tx) error {
+		for _, name := range [][]byte{operationsBucket, hashesBucket} {
+			if _, e := tx.CreateBucketIfNotExists(name); e != nil {
+				return fmt.Errorf("create bucket[%s]: %w", name, e)
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		_ = db.Close() //nolint:errcheck
+
+		return nil, fmt.Errorf("initialize operation store[%s]: %w", path, err)
+	}
+
+	logger.Infof("Opened operation store [%s]", path)
+
+	return &Store{path: path, db: db}, nil
+}
+
+// Put stores the given operations. All operations are stored atomically, i.e. if an error is returned then
+// none of the operations were stored. If an operation (same operation hash) is already stored then the
+// operation with the earliest anchoring position (transaction time, transaction number) is kept.
+func (s *Store) Put(ops []*operation.AnchoredOperation) error {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	if s.db == nil {
+		return errors.New("operation store is closed")
+	}
+
+	if len(ops) == 0 {
+		return nil
+	}
+
+	err := s.db.Update(func(tx *bbolt.Tx) error {
+		for _, op := range ops {
+			if err := put(tx, op); err != nil {
+				return fmt.Errorf("store %s operation for suffix[%s]: %w", op.Type, op.UniqueSuffix, err)
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("write operations to store: %w", err)
+	}
+
+	return nil
+}
+
+// Get returns all operations for the given unique suffix ordered by anchoring position (transaction time,
+// transaction number). operation.ErrNotFound is returned if no operations exist for the suffix.
+func (s *Store) Get(uniqueSuffix string) ([]*operation.AnchoredOperation, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	if s.db == nil {
+		return nil, errors.New("operation store is closed")
+	}
+
+	var ops []*operation.AnchoredOperation
+
+	err := s.db.View(func(tx *bbolt.Tx) error {
+		prefix := suffixPrefix(uniqueSuffix)
+
+		c := tx.Bucket(operationsBucket).Cursor()
+
+		for k, v := c.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, v = c.Next() {
+			op := &operation.AnchoredOperation{}
+
+			if err := json.Unmarshal(v, op); err != nil {
+				return fmt.Errorf("unmarshal operation for suffix[%s]: %w", uniqueSuffix, err)
+			}
+
+			ops = append(ops, op)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(ops) == 0 {
+		return nil, operation.ErrNotFound
+	}
+
+	return ops, nil
+}
+
+// Close closes the operation store.
+func (s *Store) Close() error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.db == nil {
+		return nil
+	}
+
+	err := s.db.Close()
+	s.db = nil
+
+	return err
+}
+
+// put stores the given operation unless the same operation is already stored with an earlier (or the same)
+// anchoring position, in which case the operation is ignored.
+func put(tx *bbolt.Tx, op *operation.AnchoredOperation) error {
+	if op.UniqueSuffix == "" {
+		return errors.New("missing unique suffix")
+	}
+
+	ops := tx.Bucket(operationsBucket)
+	hashes := tx.Bucket(hashesBucket)
+
+	hash := sha256.Sum256(op.OperationBuffer)
+	key := operationKey(op, hash)
+
+	if existingKey := hashes.Get(hash[:]); existingKey != nil {
+		if bytes.Compare(position(key), position(existingKey)) >= 0 {
+			logger.Debugf("Ignoring %s operation for suffix[%s] since it's already stored", op.Type, op.UniqueSuffix)
+
+			return nil
+		}
+
+		logger.Debugf("Replacing %s operation for suffix[%s] with an earlier anchored copy", op.Type, op.UniqueSuffix)
+
+		if err := ops.Delete(existingKey); err != nil {
+			return err
+		}
+	}
+
+	value, err := json.Marshal(op)
+	if err != nil {
+		return err
+	}
+
+	if err := ops.Put(key, value); err != nil {
+		return err
+	}
+
+	return hashes.Put(hash[:], key)
+}
+
+// operationKey returns the key of the operation: unique suffix, separator, transaction time, transaction
+// number and operation hash. Numbers are encoded as big endian so that keys sort by anchoring position.
+func operationKey(op *operation.AnchoredOperation, hash [sha256.Size]byte) []byte {
+	prefix := suffixPrefix(op.UniqueSuffix)
+
+	key := make([]byte, len(prefix)+positionSize+len(hash))
+
+	copy(key, prefix)
+	binary.BigEndian.PutUint64(key[len(prefix):], op.TransactionTime)
+	binary.BigEndian.PutUint64(key[len(prefix)+8:], op.TransactionNumber)
+	copy(key[len(prefix)+positionSize:], hash[:])
+
+	return key
+}
+
+func suffixPrefix(uniqueSuffix string) []byte {
+	return append([]byte(uniqueSuffix), keySeparator)
+}
+
+// position returns the anchoring position (transaction time and number) of the given operation key.
+func position(key []byte) []byte {
+	end := len(key) - sha256.Size
+
+	return key[end-positionSize : end]
+}
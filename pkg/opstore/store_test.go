@@ -0,0 +1,263 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package opstore
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.etcd.io/bbolt"
+
+	"github.com/trustbloc/sidetree-core-go/pkg/api/operation"
+)
+
+const (
+	suffix1 = "suffix1"
+	suffix2 = "suffix2"
+)
+
+func TestStore(t *testing.T) {
+	path := newStorePath(t)
+
+	s, err := New(path)
+	require.NoError(t, err)
+
+	ops, err := s.Get(suffix1)
+	require.True(t, errors.Is(err, operation.ErrNotFound))
+	require.Nil(t, ops)
+
+	create1 := newOperation(suffix1, operation.TypeCreate, "create1", 1)
+	create2 := newOperation(suffix2, operation.TypeCreate, "create2", 1)
+	update1 := newOperation(suffix1, operation.TypeUpdate, "update1", 2)
+
+	require.NoError(t, s.Put([]*operation.AnchoredOperation{create1, create2}))
+	require.NoError(t, s.Put([]*operation.AnchoredOperation{update1}))
+	require.NoError(t, s.Put(nil))
+
+	ops, err = s.Get(suffix1)
+	require.NoError(t, err)
+	require.Equal(t, []*operation.AnchoredOperation{create1, update1}, ops)
+
+	ops, err = s.Get(suffix2)
+	require.NoError(t, err)
+	require.Equal(t, []*operation.AnchoredOperation{create2}, ops)
+
+	require.NoError(t, s.Close())
+	require.NoError(t, s.Close())
+
+	_, err = s.Get(suffix1)
+	require.EqualError(t, err, "operation store is closed")
+
+	err = s.Put([]*operation.AnchoredOperation{create1})
+	require.EqualError(t, err, "operation store is closed")
+
+	// operations are loaded when the store is reopened
+	s, err = New(path)
+	require.NoError(t, err)
+
+	defer func() {
+		require.NoError(t, s.Close())
+	}()
+
+	ops, err = s.Get(suffix1)
+	require.NoError(t, err)
+	require.Equal(t, []*operation.AnchoredOperation{create1, update1}, ops)
+
+	ops, err = s.Get(suffix2)
+	require.NoError(t, err)
+	require.Equal(t, []*operation.AnchoredOperation{create2}, ops)
+}
+
+func TestStore_Idempotent(t *testing.T) {
+	path := newStorePath(t)
+
+	s, err := New(path)
+	require.NoError(t, err)
+
+	create := newOperation(suffix1, operation.TypeCreate, "create", 2)
+	update := newOperation(suffix1, operation.TypeUpdate, "update", 3)
+
+	require.NoError(t, s.Put([]*operation.AnchoredOperation{create, create}))
+
+	// same operation anchored in a later transaction is ignored
+	require.NoError(t, s.Put([]*operation.AnchoredOperation{newOperation(suffix1, operation.TypeCreate, "create", 5)}))
+
+	require.NoError(t, s.Put([]*operation.AnchoredOperation{create, update}))
+
+	ops, err := s.Get(suffix1)
+	require.NoError(t, err)
+	require.Equal(t, []*operation.AnchoredOperation{create, update}, ops)
+
+	require.NoError(t, s.Close())
+
+	// operation hashes are loaded when the store is reopened
+	s, err = New(path)
+	require.NoError(t, err)
+
+	defer func() {
+		require.NoError(t, s.Close())
+	}()
+
+	require.NoError(t, s.Put([]*operation.AnchoredOperation{update}))
+
+	ops, err = s.Get(suffix1)
+	require.NoError(t, err)
+	require.Equal(t, []*operation.AnchoredOperation{create, update}, ops)
+
+	// same operation anchored in an earlier transaction replaces the stored operation
+	earlierCreate := newOperation(suffix1, operation.TypeCreate, "create", 1)
+
+	require.NoError(t, s.Put([]*operation.AnchoredOperation{earlierCreate}))
+
+	ops, err = s.Get(suffix1)
+	require.NoError(t, err)
+	require.Equal(t, []*operation.AnchoredOperation{earlierCreate, update}, ops)
+
+	// same transaction time but an earlier transaction number
+	earlierUpdate := newOperation(suffix1, operation.TypeUpdate, "update", 3)
+	earlierUpdate.TransactionNumber = 2
+
+	require.NoError(t, s.Put([]*operation.AnchoredOperation{update, earlierUpdate}))
+
+	ops, err = s.Get(suffix1)
+	require.NoError(t, err)
+	require.Equal(t, []*operation.AnchoredOperation{earlierCreate, earlierUpdate}, ops)
+}
+
+func TestStore_Order(t *testing.T) {
+	s, err := New(newStorePath(t))
+	require.NoError(t, err)
+
+	defer func() {
+		require.NoError(t, s.Close())
+	}()
+
+	create := newOperation(suffix1, operation.TypeCreate, "create", 1)
+	update1 := newOperation(suffix1, operation.TypeUpdate, "update1", 2)
+	update2 := newOperation(suffix1, operation.TypeUpdate, "update2", 300)
+
+	// suffix which has suffix1 as a prefix
+	other := newOperation(suffix1+"0", operation.TypeCreate, "other", 1)
+
+	require.NoError(t, s.Put([]*operation.AnchoredOperation{update2, other}))
+	require.NoError(t, s.Put([]*operation.AnchoredOperation{update1, create}))
+
+	ops, err := s.Get(suffix1)
+	require.NoError(t, err)
+	require.Equal(t, []*operation.AnchoredOperation{create, update1, update2}, ops)
+
+	ops, err = s.Get(suffix1 + "0")
+	require.NoError(t, err)
+	require.Equal(t, []*operation.AnchoredOperation{other}, ops)
+}
+
+func TestStore_Atomic(t *testing.T) {
+	s, err := New(newStorePath(t))
+	require.NoError(t, err)
+
+	defer func() {
+		require.NoError(t, s.Close())
+	}()
+
+	create1 := newOperation(suffix1, operation.TypeCreate, "create1", 1)
+	invalid := newOperation("", operation.TypeCreate, "create2", 1)
+
+	err = s.Put([]*operation.AnchoredOperation{create1, invalid})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "missing unique suffix")
+
+	_, err = s.Get(suffix1)
+	require.True(t, errors.Is(err, operation.ErrNotFound))
+
+	// the hash of the discarded operation isn't stored either
+	require.NoError(t, s.Put([]*operation.AnchoredOperation{create1}))
+
+	ops, err := s.Get(suffix1)
+	require.NoError(t, err)
+	require.Equal(t, []*operation.AnchoredOperation{create1}, ops)
+}
+
+func TestStore_Error(t *testing.T) {
+	t.Run("directory error", func(t *testing.T) {
+		path := newStorePath(t)
+
+		appendToFile(t, path, "")
+
+		s, err := New(filepath.Join(path, "ops", "operations.db"))
+		require.Error(t, err)
+		require.Nil(t, s)
+		require.Contains(t, err.Error(), "create operation store directory")
+	})
+
+	t.Run("open error", func(t *testing.T) {
+		path := newStorePath(t)
+
+		appendToFile(t, path, "not a database")
+
+		s, err := New(path)
+		require.Error(t, err)
+		require.Nil(t, s)
+		require.Contains(t, err.Error(), "open operation store")
+	})
+
+	t.Run("unmarshal error", func(t *testing.T) {
+		s, err := New(newStorePath(t))
+		require.NoError(t, err)
+
+		defer func() {
+			require.NoError(t, s.Close())
+		}()
+
+		require.NoError(t, s.db.Update(func(tx *bbolt.Tx) error {
+			return tx.Bucket(operationsBucket).Put(suffixPrefix(suffix1), []byte("{"))
+		}))
+
+		_, err = s.Get(suffix1)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "unmarshal operation for suffix[suffix1]")
+	})
+}
+
+func newOperation(suffix string, opType operation.Type, request string, txnNumber uint64) *operation.AnchoredOperation {
+	return &operation.AnchoredOperation{
+		Type:              opType,
+		UniqueSuffix:      suffix,
+		OperationBuffer:   []byte(request),
+		TransactionTime:   txnNumber,
+		TransactionNumber: txnNumber,
+	}
+}
+
+func newStorePath(t *testing.T) string {
+	t.Helper()
+
+	dir, err := ioutil.TempDir("", "opstore")
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		require.NoError(t, os.RemoveAll(dir))
+	})
+
+	return filepath.Join(dir, "store", "operations.db")
+}
+
+func appendToFile(t *testing.T, path, content string) {
+	t.Helper()
+
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o700))
+
+	f, err := os.OpenFile(filepath.Clean(path), os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o600)
+	require.NoError(t, err)
+
+	_, err = f.WriteString(content)
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+}
@@ -0,0 +1,91 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package processor
+
+import (
+	"github.com/trustbloc/sidetree-core-go/pkg/api/operation"
+	"github.com/trustbloc/sidetree-core-go/pkg/api/protocol"
+)
+
+// operationHistory records how operations were processed during resolution. All methods may be invoked
+// on a nil history, in which case nothing is recorded.
+type operationHistory struct {
+	entries map[*operation.AnchoredOperation]*protocol.OperationHistoryEntry
+}
+
+func newOperationHistory() *operationHistory {
+	return &operationHistory{
+		entries: make(map[*operation.AnchoredOperation]*protocol.OperationHistoryEntry),
+	}
+}
+
+// applied records that the given operation was applied and resulted in the given state.
+func (h *operationHistory) applied(op *operation.AnchoredOperation, state *protocol.ResolutionModel) {
+	if h == nil {
+		return
+	}
+
+	entry := newHistoryEntry(op)
+	entry.Applied = true
+	entry.UpdateCommitment = state.UpdateCommitment
+	entry.RecoveryCommitment = state.RecoveryCommitment
+
+	h.entries[op] = entry
+}
+
+// rejected records that the given operation was not applied for the given reason.
+func (h *operationHistory) rejected(op *operation.AnchoredOperation, reason string) {
+	if h == nil {
+		return
+	}
+
+	entry := newHistoryEntry(op)
+	entry.Reason = reason
+
+	h.entries[op] = entry
+}
+
+// get returns the history for the given (sorted) operations. Operations that were not processed are
+// explained using the final state of the resolution.
+func (h *operationHistory) get(ops []*operation.AnchoredOperation, r *resolution) []*protocol.OperationHistoryEntry {
+	history := make([]*protocol.OperationHistoryEntry, len(ops))
+
+	for i, op := range ops {
+		entry, ok := h.entries[op]
+		if !ok {
+			entry = newHistoryEntry(op)
+			entry.Reason = notProcessedReason(op, r)
+		}
+
+		history[i] = entry
+	}
+
+	return history
+}
+
+func notProcessedReason(op *operation.AnchoredOperation, r *resolution) string {
+	switch {
+	case op.Type == operation.TypeCreate:
+		return "document was created by another create operation"
+	case op.Type == operation.TypeUpdate && r.state.Deactivated:
+		return "document is deactivated"
+	case op.Type == operation.TypeUpdate &&
+		!isAfter(op, r.fullState.LastOperationTransactionTime, r.fullState.LastOperationTransactionNumber):
+		return "operation was anchored before the last recover operation"
+	default:
+		return "operation doesn't reveal a commitment of the document's commitment chain"
+	}
+}
+
+func newHistoryEntry(op *operation.AnchoredOperation) *protocol.OperationHistoryEntry {
+	return &protocol.OperationHistoryEntry{
+		Type:               op.Type,
+		TransactionTime:    op.TransactionTime,
+		TransactionNumber:  op.TransactionNumber,
+		CanonicalReference: op.CanonicalReference,
+	}
+}
@@ -0,0 +1,54 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package processor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/sidetree-core-go/pkg/api/operation"
+	"github.com/trustbloc/sidetree-core-go/pkg/api/protocol"
+)
+
+func TestOperationHistory(t *testing.T) {
+	createOp := &operation.AnchoredOperation{Type: operation.TypeCreate, TransactionTime: 1, CanonicalReference: "ref1"}
+	otherCreateOp := &operation.AnchoredOperation{Type: operation.TypeCreate, TransactionTime: 2}
+	updateOp := &operation.AnchoredOperation{Type: operation.TypeUpdate, TransactionTime: 3, TransactionNumber: 1}
+	deactivateOp := &operation.AnchoredOperation{Type: operation.TypeDeactivate, TransactionTime: 4}
+
+	t.Run("nil history", func(t *testing.T) {
+		var h *operationHistory
+
+		require.NotPanics(t, func() {
+			h.applied(createOp, &protocol.ResolutionModel{})
+			h.rejected(updateOp, "reason")
+		})
+	})
+
+	t.Run("success", func(t *testing.T) {
+		h := newOperationHistory()
+
+		h.applied(createOp, &protocol.ResolutionModel{UpdateCommitment: "uc", RecoveryCommitment: "rc"})
+		h.applied(deactivateOp, &protocol.ResolutionModel{Deactivated: true})
+
+		state := &protocol.ResolutionModel{Deactivated: true}
+
+		history := h.get([]*operation.AnchoredOperation{createOp, otherCreateOp, updateOp, deactivateOp},
+			&resolution{state: state, fullState: state})
+
+		require.Equal(t, []*protocol.OperationHistoryEntry{
+			{
+				Type: operation.TypeCreate, TransactionTime: 1, CanonicalReference: "ref1",
+				Applied: true, UpdateCommitment: "uc", RecoveryCommitment: "rc",
+			},
+			{Type: operation.TypeCreate, TransactionTime: 2, Reason: "document was created by another create operation"},
+			{Type: operation.TypeUpdate, TransactionTime: 3, TransactionNumber: 1, Reason: "document is deactivated"},
+			{Type: operation.TypeDeactivate, TransactionTime: 4, Applied: true},
+		}, history)
+	})
+}
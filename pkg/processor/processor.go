@@ -10,22 +10,30 @@ import (
 	"errors"
 	"fmt"
 	"sort"
+	"time"
 
 	"github.com/trustbloc/edge-core/pkg/log"
 
 	"github.com/trustbloc/sidetree-core-go/pkg/api/operation"
 	"github.com/trustbloc/sidetree-core-go/pkg/api/protocol"
 	"github.com/trustbloc/sidetree-core-go/pkg/commitment"
+	"github.com/trustbloc/sidetree-core-go/pkg/document"
 )
 
 var logger = log.New("sidetree-core-processor")
 
+const defaultSnapshotMinOperations = 10
+
 // OperationProcessor will process document operations in chronological order and create final document during resolution.
 // It uses operation store client to retrieve all operations that are related to requested document.
 type OperationProcessor struct {
 	name  string
 	store OperationStoreClient
 	pc    protocol.Client
+
+	snapshots             SnapshotStore
+	snapshotMinOperations int
+	txnTimeProvider       TransactionTimeProvider
 }
 
 // OperationStoreClient defines interface for retrieving all operations related to document.
@@ -34,15 +42,61 @@ type OperationStoreClient interface {
 	Get(uniqueSuffix string) ([]*operation.AnchoredOperation, error)
 }
 
+// TransactionTimeProvider converts a timestamp into a transaction time (e.g. a block number). It is the
+// reverse of the timestamp provider that is used for the document metadata.
+type TransactionTimeProvider interface {
+	TransactionTime(t time.Time) (uint64, error)
+}
+
+// Option is an operation processor option.
+type Option func(opts *OperationProcessor)
+
+// WithSnapshotStore sets the store for resolution snapshots. When set, the state of a document is persisted
+// after resolution and subsequent resolutions start from the snapshot instead of replaying all operations.
+func WithSnapshotStore(store SnapshotStore) Option {
+	return func(opts *OperationProcessor) {
+		opts.snapshots = store
+	}
+}
+
+// WithSnapshotMinOperations sets the minimum number of operations that a document must have for a snapshot
+// to be persisted.
+func WithSnapshotMinOperations(minOperations int) Option {
+	return func(opts *OperationProcessor) {
+		opts.snapshotMinOperations = minOperations
+	}
+}
+
+// WithTransactionTimeProvider sets the provider that converts the timestamp of the 'versionTime' resolution
+// option into a transaction time. If not set then 'versionTime' timestamps are rejected.
+func WithTransactionTimeProvider(p TransactionTimeProvider) Option {
+	return func(opts *OperationProcessor) {
+		opts.txnTimeProvider = p
+	}
+}
+
 // New returns new operation processor with the given name. (Note that name is only used for logging.)
-func New(name string, store OperationStoreClient, pc protocol.Client) *OperationProcessor {
-	return &OperationProcessor{name: name, store: store, pc: pc}
+func New(name string, store OperationStoreClient, pc protocol.Client, opts ...Option) *OperationProcessor {
+	s := &OperationProcessor{
+		name:                  name,
+		store:                 store,
+		pc:                    pc,
+		snapshotMinOperations: defaultSnapshotMinOperations,
+	}
+
+	// apply options
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
 }
 
 // Resolve document based on the given unique suffix.
 // Parameters:
 // uniqueSuffix - unique portion of ID to resolve. for example "abc123" in "did:sidetree:abc123".
-func (s *OperationProcessor) Resolve(uniqueSuffix string) (*protocol.ResolutionModel, error) {
+// opts - resolution options, e.g. for resolving a specific version of the document.
+func (s *OperationProcessor) Resolve(uniqueSuffix string, opts ...document.ResolutionOption) (*protocol.ResolutionModel, error) {
 	ops, err := s.store.Get(uniqueSuffix)
 	if err != nil {
 		return nil, err
@@ -52,42 +106,206 @@ func (s *OperationProcessor) Resolve(uniqueSuffix string) (*protocol.ResolutionM
 
 	logger.Debugf("[%s] Found %d operations for unique suffix [%s]: %+v", s.name, len(ops), uniqueSuffix, ops)
 
-	rm := &protocol.ResolutionModel{}
+	resolutionOpts := document.GetResolutionOptions(opts...)
+	if resolutionOpts.VersionRequested() || resolutionOpts.OperationHistory {
+		return s.resolveWithOptions(uniqueSuffix, ops, resolutionOpts)
+	}
+
+	snapshot := s.getSnapshot(uniqueSuffix, ops)
+
+	r, err := s.resolve(ops, snapshot, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	s.putSnapshot(uniqueSuffix, ops, snapshot, r)
+
+	return r.state, nil
+}
 
+// resolution holds the state of a resolution.
+type resolution struct {
+	state               *protocol.ResolutionModel
+	fullState           *protocol.ResolutionModel
+	recoveryCommitments map[string]bool
+	updateCommitments   map[string]bool
+}
+
+// resolve applies the given operations. If a snapshot is provided then resolution starts from the state of
+// the snapshot and only operations that continue the commitment chains of the snapshot are applied.
+// If a history is provided then the processing of each operation is recorded.
+func (s *OperationProcessor) resolve(ops []*operation.AnchoredOperation, snapshot *Snapshot, history *operationHistory) (*resolution, error) {
 	// split operations into 'create', 'update' and 'full' operations
 	createOps, updateOps, fullOps := splitOperations(ops)
-	if len(createOps) == 0 {
-		return nil, errors.New("missing create operation")
-	}
 
-	// apply 'create' operations first
-	rm = s.applyFirstValidCreateOperation(createOps, rm)
-	if rm == nil {
-		return nil, errors.New("valid create operation not found")
+	r := newResolution(snapshot)
+
+	if r.fullState == nil {
+		if len(createOps) == 0 {
+			return nil, document.NewResolutionError(document.NotFoundErrorCode, "missing create operation")
+		}
+
+		// apply 'create' operations first
+		r.fullState = s.applyFirstValidCreateOperation(createOps, &protocol.ResolutionModel{}, history)
+		if r.fullState == nil {
+			return nil, document.NewResolutionError(document.NotFoundErrorCode, "valid create operation not found")
+		}
 	}
 
-	// apply 'full' operations first
+	fullState := r.fullState
+
+	// apply 'full' operations next
 	if len(fullOps) > 0 {
-		logger.Debugf("[%s] Applying %d full operations for unique suffix [%s]", s.name, len(fullOps), uniqueSuffix)
+		logger.Debugf("[%s] Applying %d full operations for unique suffix [%s]", s.name, len(fullOps), fullOps[0].UniqueSuffix)
 
-		rm = s.applyOperations(fullOps, rm, getRecoveryCommitment)
-		if rm.Deactivated {
-			// document was deactivated, stop processing
-			return rm, nil
-		}
+		r.fullState = s.applyOperations(fullOps, r.fullState, getRecoveryCommitment, r.recoveryCommitments, history)
+	}
+
+	// update operations are applied on top of the latest full state
+	if r.state == nil || r.fullState != fullState {
+		r.state = r.fullState
+		r.updateCommitments = make(map[string]bool)
+	}
+
+	if r.state.Deactivated {
+		// document was deactivated, stop processing
+		return r, nil
 	}
 
 	// next apply update ops since last 'full' transaction
-	filteredUpdateOps := getOpsWithTxnGreaterThan(updateOps, rm.LastOperationTransactionTime, rm.LastOperationTransactionNumber)
+	filteredUpdateOps := getOpsWithTxnGreaterThan(updateOps, r.fullState.LastOperationTransactionTime, r.fullState.LastOperationTransactionNumber)
 	if len(filteredUpdateOps) > 0 {
-		logger.Debugf("[%s] Applying %d update operations after last full operation for unique suffix [%s]", s.name, len(filteredUpdateOps), uniqueSuffix)
-		rm = s.applyOperations(filteredUpdateOps, rm, getUpdateCommitment)
+		logger.Debugf("[%s] Applying %d update operations after last full operation for unique suffix [%s]", s.name, len(filteredUpdateOps), filteredUpdateOps[0].UniqueSuffix)
+
+		r.state = s.applyOperations(filteredUpdateOps, r.state, getUpdateCommitment, r.updateCommitments, history)
+	}
+
+	return r, nil
+}
+
+func newResolution(snapshot *Snapshot) *resolution {
+	r := &resolution{
+		recoveryCommitments: make(map[string]bool),
+		updateCommitments:   make(map[string]bool),
+	}
+
+	if snapshot == nil {
+		return r
 	}
 
-	return rm, nil
+	r.state = snapshot.State
+	r.fullState = snapshot.FullState
+
+	for _, c := range snapshot.RecoveryCommitments {
+		r.recoveryCommitments[c] = true
+	}
+
+	for _, c := range snapshot.UpdateCommitments {
+		r.updateCommitments[c] = true
+	}
+
+	return r
 }
 
-func (s *OperationProcessor) createOperationHashMap(ops []*operation.AnchoredOperation) map[string][]*operation.AnchoredOperation {
+// getSnapshot returns the snapshot for the given suffix. Nil is returned if no snapshot exists or if the
+// snapshot is no longer valid, i.e. an operation that is ordered before the latest operation of the snapshot
+// was added after the snapshot was taken.
+func (s *OperationProcessor) getSnapshot(uniqueSuffix string, ops []*operation.AnchoredOperation) *Snapshot {
+	if s.snapshots == nil {
+		return nil
+	}
+
+	snapshot, err := s.snapshots.Get(uniqueSuffix)
+	if err != nil {
+		if !errors.Is(err, ErrSnapshotNotFound) {
+			logger.Warnf("[%s] Error retrieving snapshot for unique suffix [%s]: %s", s.name, uniqueSuffix, err)
+		}
+
+		return nil
+	}
+
+	if countOpsUpTo(ops, snapshot.TransactionTime, snapshot.TransactionNumber) != snapshot.OperationCount {
+		logger.Infof("[%s] Invalidating snapshot for unique suffix [%s] since operations were added before transaction time [%d], number [%d]",
+			s.name, uniqueSuffix, snapshot.TransactionTime, snapshot.TransactionNumber)
+
+		if err := s.snapshots.Delete(uniqueSuffix); err != nil {
+			logger.Warnf("[%s] Error deleting snapshot for unique suffix [%s]: %s", s.name, uniqueSuffix, err)
+		}
+
+		return nil
+	}
+
+	logger.Debugf("[%s] Resolving unique suffix [%s] from snapshot at transaction time [%d], number [%d]",
+		s.name, uniqueSuffix, snapshot.TransactionTime, snapshot.TransactionNumber)
+
+	return snapshot
+}
+
+// putSnapshot persists the resolution state if the operations have changed since the given snapshot was taken.
+func (s *OperationProcessor) putSnapshot(uniqueSuffix string, ops []*operation.AnchoredOperation, snapshot *Snapshot, r *resolution) {
+	if s.snapshots == nil || len(ops) < s.snapshotMinOperations {
+		return
+	}
+
+	if snapshot != nil && snapshot.OperationCount == len(ops) {
+		// nothing changed since the snapshot was taken
+		return
+	}
+
+	latest := ops[0]
+
+	for _, op := range ops[1:] {
+		if isAfter(op, latest.TransactionTime, latest.TransactionNumber) {
+			latest = op
+		}
+	}
+
+	err := s.snapshots.Put(uniqueSuffix, &Snapshot{
+		State:               r.state,
+		FullState:           r.fullState,
+		RecoveryCommitments: keys(r.recoveryCommitments),
+		UpdateCommitments:   keys(r.updateCommitments),
+		TransactionTime:     latest.TransactionTime,
+		TransactionNumber:   latest.TransactionNumber,
+		OperationCount:      len(ops),
+	})
+	if err != nil {
+		logger.Warnf("[%s] Error storing snapshot for unique suffix [%s]: %s", s.name, uniqueSuffix, err)
+	}
+}
+
+// countOpsUpTo returns the number of operations that are ordered at or before the given transaction.
+func countOpsUpTo(ops []*operation.AnchoredOperation, txnTime, txnNumber uint64) int {
+	count := 0
+
+	for _, op := range ops {
+		if !isAfter(op, txnTime, txnNumber) {
+			count++
+		}
+	}
+
+	return count
+}
+
+func isAfter(op *operation.AnchoredOperation, txnTime, txnNumber uint64) bool {
+	if op.TransactionTime != txnTime {
+		return op.TransactionTime > txnTime
+	}
+
+	return op.TransactionNumber > txnNumber
+}
+
+func keys(m map[string]bool) []string {
+	var result []string
+
+	for k := range m {
+		result = append(result, k)
+	}
+
+	return result
+}
+
+func (s *OperationProcessor) createOperationHashMap(ops []*operation.AnchoredOperation, history *operationHistory) map[string][]*operation.AnchoredOperation {
 	opMap := make(map[string][]*operation.AnchoredOperation)
 
 	for _, op := range ops {
@@ -95,6 +313,8 @@ func (s *OperationProcessor) createOperationHashMap(ops []*operation.AnchoredOpe
 		if err != nil {
 			logger.Infof("[%s] Skipped bad operation while creating operation hash map {UniqueSuffix: %s, Type: %s, TransactionTime: %d, TransactionNumber: %d}. Reason: %s", s.name, op.UniqueSuffix, op.Type, op.TransactionTime, op.TransactionNumber, err)
 
+			history.rejected(op, err.Error())
+
 			continue
 		}
 
@@ -102,6 +322,8 @@ func (s *OperationProcessor) createOperationHashMap(ops []*operation.AnchoredOpe
 		if err != nil {
 			logger.Infof("[%s] Skipped calculating commitment while creating operation hash map {UniqueSuffix: %s, Type: %s, TransactionTime: %d, TransactionNumber: %d}. Reason: %s", s.name, op.UniqueSuffix, op.Type, op.TransactionTime, op.TransactionNumber, err)
 
+			history.rejected(op, err.Error())
+
 			continue
 		}
 
@@ -147,16 +369,16 @@ func getOpsWithTxnGreaterThan(ops []*operation.AnchoredOperation, txnTime, txnNu
 	return nil
 }
 
-func (s *OperationProcessor) applyOperations(ops []*operation.AnchoredOperation, rm *protocol.ResolutionModel, commitmentFnc fnc) *protocol.ResolutionModel {
+// applyOperations applies the chain of operations that starts with the commitment of the given resolution model.
+// Commitments that are processed are added to the given commitment map.
+func (s *OperationProcessor) applyOperations(ops []*operation.AnchoredOperation, rm *protocol.ResolutionModel, commitmentFnc fnc,
+	commitmentMap map[string]bool, history *operationHistory) *protocol.ResolutionModel {
 	// suffix for logging
 	uniqueSuffix := ops[0].UniqueSuffix
 
 	state := rm
 
-	opMap := s.createOperationHashMap(ops)
-
-	// holds applied commitments
-	commitmentMap := make(map[string]bool)
+	opMap := s.createOperationHashMap(ops, history)
 
 	c := commitmentFnc(state)
 	logger.Debugf("[%s] Processing commitment '%s' {UniqueSuffix: %s}", s.name, c, uniqueSuffix)
@@ -165,7 +387,7 @@ func (s *OperationProcessor) applyOperations(ops []*operation.AnchoredOperation,
 	for ok {
 		logger.Debugf("[%s] Found %d operation(s) for commitment '%s' {UniqueSuffix: %s}", s.name, len(commitmentOps), c, uniqueSuffix)
 
-		newState := s.applyFirstValidOperation(commitmentOps, state, c, commitmentMap)
+		newState := s.applyFirstValidOperation(commitmentOps, state, c, commitmentMap, history)
 
 		// can't find a valid operation to apply
 		if newState == nil {
@@ -210,7 +432,8 @@ func getRecoveryCommitment(rm *protocol.ResolutionModel) string {
 	return rm.RecoveryCommitment
 }
 
-func (s *OperationProcessor) applyFirstValidCreateOperation(createOps []*operation.AnchoredOperation, rm *protocol.ResolutionModel) *protocol.ResolutionModel {
+func (s *OperationProcessor) applyFirstValidCreateOperation(createOps []*operation.AnchoredOperation, rm *protocol.ResolutionModel,
+	history *operationHistory) *protocol.ResolutionModel {
 	for _, op := range createOps {
 		var state *protocol.ResolutionModel
 		var err error
@@ -218,11 +441,15 @@ func (s *OperationProcessor) applyFirstValidCreateOperation(createOps []*operati
 		if state, err = s.applyOperation(op, rm); err != nil {
 			logger.Infof("[%s] Skipped bad operation {UniqueSuffix: %s, Type: %s, TransactionTime: %d, TransactionNumber: %d}. Reason: %s", s.name, op.UniqueSuffix, op.Type, op.TransactionTime, op.TransactionNumber, err)
 
+			history.rejected(op, err.Error())
+
 			continue
 		}
 
 		logger.Debugf("[%s] After applying create op %+v, recover commitment[%s], update commitment[%s], New doc: %s", s.name, op, state.RecoveryCommitment, state.UpdateCommitment, state.Doc)
 
+		history.applied(op, state)
+
 		return state
 	}
 
@@ -230,55 +457,84 @@ func (s *OperationProcessor) applyFirstValidCreateOperation(createOps []*operati
 }
 
 // this function should be used for update, recover and deactivate operations (create is handled differently).
-func (s *OperationProcessor) applyFirstValidOperation(ops []*operation.AnchoredOperation, rm *protocol.ResolutionModel, currCommitment string, processedCommitments map[string]bool) *protocol.ResolutionModel {
-	for _, op := range ops {
-		var state *protocol.ResolutionModel
-		var err error
-
-		nextCommitment, err := s.getCommitment(op)
+func (s *OperationProcessor) applyFirstValidOperation(ops []*operation.AnchoredOperation, rm *protocol.ResolutionModel, currCommitment string,
+	processedCommitments map[string]bool, history *operationHistory) *protocol.ResolutionModel {
+	for i, op := range ops {
+		state, err := s.applyNextOperation(op, rm, currCommitment, processedCommitments)
 		if err != nil {
 			logger.Infof("[%s] Skipped bad operation {UniqueSuffix: %s, Type: %s, TransactionTime: %d, TransactionNumber: %d}. Reason: %s", s.name, op.UniqueSuffix, op.Type, op.TransactionTime, op.TransactionNumber, err)
 
-			continue
-		}
-
-		if currCommitment == nextCommitment {
-			logger.Infof("[%s] Skipped bad operation {UniqueSuffix: %s, Type: %s, TransactionTime: %d, TransactionNumber: %d}. Reason: operation commitment(key) equals next operation commitment(key)", s.name, op.UniqueSuffix, op.Type, op.TransactionTime, op.TransactionNumber)
+			history.rejected(op, err.Error())
 
 			continue
 		}
 
-		if nextCommitment != "" {
-			// for recovery and update operations check if next commitment has been used already; if so skip to next operation
-			_, processed := processedCommitments[nextCommitment]
-			if processed {
-				logger.Infof("[%s] Skipped bad operation {UniqueSuffix: %s, Type: %s, TransactionTime: %d, TransactionNumber: %d}. Reason: next operation commitment(key) has already been used", s.name, op.UniqueSuffix, op.Type, op.TransactionTime, op.TransactionNumber)
-
-				continue
-			}
-		}
+		logger.Debugf("[%s] After applying op %+v, recover commitment[%s], update commitment[%s], New doc: %s", s.name, op, state.RecoveryCommitment, state.UpdateCommitment, state.Doc)
 
-		if state, err = s.applyOperation(op, rm); err != nil {
-			logger.Infof("[%s] Skipped bad operation {UniqueSuffix: %s, Type: %s, TransactionTime: %d, TransactionNumber: %d}. Reason: %s", s.name, op.UniqueSuffix, op.Type, op.TransactionTime, op.TransactionNumber, err)
+		history.applied(op, state)
 
-			continue
+		for _, other := range ops[i+1:] {
+			history.rejected(other, "another operation for the same commitment(key) was applied")
 		}
 
-		logger.Debugf("[%s] After applying op %+v, recover commitment[%s], update commitment[%s], New doc: %s", s.name, op, state.RecoveryCommitment, state.UpdateCommitment, state.Doc)
-
 		return state
 	}
 
 	return nil
 }
 
+// applyNextOperation applies the given operation after checking that the operation's next commitment
+// is neither the current commitment nor a commitment that was already used.
+func (s *OperationProcessor) applyNextOperation(op *operation.AnchoredOperation, rm *protocol.ResolutionModel, currCommitment string,
+	processedCommitments map[string]bool) (*protocol.ResolutionModel, error) {
+	nextCommitment, err := s.getCommitment(op)
+	if err != nil {
+		return nil, err
+	}
+
+	if currCommitment == nextCommitment {
+		return nil, errors.New("operation commitment(key) equals next operation commitment(key)")
+	}
+
+	if nextCommitment != "" {
+		// for recovery and update operations check if next commitment has been used already; if so skip to next operation
+		if _, processed := processedCommitments[nextCommitment]; processed {
+			return nil, errors.New("next operation commitment(key) has already been used")
+		}
+	}
+
+	return s.applyOperation(op, rm)
+}
+
 func (s *OperationProcessor) applyOperation(op *operation.AnchoredOperation, rm *protocol.ResolutionModel) (*protocol.ResolutionModel, error) {
 	p, err := s.pc.Get(op.ProtocolGenesisTime)
 	if err != nil {
 		return nil, fmt.Errorf("apply '%s' operation: %s", op.Type, err.Error())
 	}
 
-	return p.OperationApplier().Apply(op, rm)
+	state, err := p.OperationApplier().Apply(op, rm)
+	if err != nil {
+		return nil, err
+	}
+
+	versionID, err := getVersionID(op, p.Protocol())
+	if err != nil {
+		return nil, fmt.Errorf("apply '%s' operation: %s", op.Type, err.Error())
+	}
+
+	// version information is tracked by the processor (independent of the protocol version)
+	result := *state
+	result.VersionID = versionID
+
+	if op.Type == operation.TypeCreate {
+		result.CreatedTime = op.TransactionTime
+		result.UpdatedTime = 0
+	} else {
+		result.CreatedTime = rm.CreatedTime
+		result.UpdatedTime = op.TransactionTime
+	}
+
+	return &result, nil
 }
 
 func sortOperations(ops []*operation.AnchoredOperation) {
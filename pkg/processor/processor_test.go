@@ -15,6 +15,7 @@ import (
 	"fmt"
 	"strconv"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
@@ -71,6 +72,7 @@ func TestResolve(t *testing.T) {
 		require.Nil(t, doc)
 		require.Error(t, err)
 		require.Equal(t, "uniqueSuffix not found in the store", err.Error())
+		require.True(t, errors.Is(err, operation.ErrNotFound))
 	})
 
 	t.Run("store error", func(t *testing.T) {
@@ -116,6 +118,515 @@ func TestResolve(t *testing.T) {
 		require.Error(t, err)
 		require.Nil(t, doc)
 		require.Contains(t, err.Error(), "valid create operation not found")
+		require.True(t, errors.Is(err, document.ErrNotFound))
+	})
+}
+
+func TestResolve_Snapshot(t *testing.T) {
+	recoveryKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	updateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	t.Run("success - only new operations are applied", func(t *testing.T) {
+		pc, applied := newCountingProtocolClient()
+
+		store, uniqueSuffix := getDefaultStore(recoveryKey, updateKey)
+		nextUpdateKey := addUpdateOperations(t, store, updateKey, uniqueSuffix, 1, 3)
+
+		snapshots := NewMemSnapshotStore()
+		p := New("test", store, pc, WithSnapshotStore(snapshots), WithSnapshotMinOperations(2))
+
+		result, err := p.Resolve(uniqueSuffix)
+		require.NoError(t, err)
+		require.Equal(t, "special3", result.Doc["test"])
+		require.Equal(t, int32(4), *applied)
+
+		snapshot, err := snapshots.Get(uniqueSuffix)
+		require.NoError(t, err)
+		require.Equal(t, 4, snapshot.OperationCount)
+		require.Equal(t, uint64(3), snapshot.TransactionTime)
+		require.Equal(t, result, snapshot.State)
+		require.Len(t, snapshot.UpdateCommitments, 3)
+
+		// resolving again doesn't apply any operations
+		*applied = 0
+
+		result, err = p.Resolve(uniqueSuffix)
+		require.NoError(t, err)
+		require.Equal(t, "special3", result.Doc["test"])
+		require.Zero(t, *applied)
+
+		// only the new operations are applied
+		addUpdateOperations(t, store, nextUpdateKey, uniqueSuffix, 4, 5)
+
+		result, err = p.Resolve(uniqueSuffix)
+		require.NoError(t, err)
+		require.Equal(t, "special5", result.Doc["test"])
+		require.Equal(t, int32(2), *applied)
+
+		snapshot, err = snapshots.Get(uniqueSuffix)
+		require.NoError(t, err)
+		require.Equal(t, 6, snapshot.OperationCount)
+		require.Equal(t, uint64(5), snapshot.TransactionTime)
+
+		// the result matches the result of a resolution without snapshots
+		expected, err := New("test", store, pc).Resolve(uniqueSuffix)
+		require.NoError(t, err)
+		require.Equal(t, expected, result)
+	})
+
+	t.Run("success - recover after snapshot", func(t *testing.T) {
+		pc := newMockProtocolClient()
+
+		store, uniqueSuffix := getDefaultStore(recoveryKey, updateKey)
+		addUpdateOperations(t, store, updateKey, uniqueSuffix, 1, 2)
+
+		snapshots := NewMemSnapshotStore()
+		p := New("test", store, pc, WithSnapshotStore(snapshots), WithSnapshotMinOperations(1))
+
+		_, err := p.Resolve(uniqueSuffix)
+		require.NoError(t, err)
+
+		recoverOp, nextRecoveryKey, err := getAnchoredRecoverOperation(recoveryKey, updateKey, uniqueSuffix, 3)
+		require.NoError(t, err)
+		require.NoError(t, store.Put(recoverOp))
+
+		result, err := p.Resolve(uniqueSuffix)
+		require.NoError(t, err)
+
+		docBytes, err := result.Doc.Bytes()
+		require.NoError(t, err)
+		require.Contains(t, string(docBytes), "recovered3")
+
+		expected, err := New("test", store, pc).Resolve(uniqueSuffix)
+		require.NoError(t, err)
+		require.Equal(t, expected, result)
+
+		// updates after the recovery are applied on top of the recovered document
+		addUpdateOperations(t, store, updateKey, uniqueSuffix, 4, 4)
+
+		result, err = p.Resolve(uniqueSuffix)
+		require.NoError(t, err)
+		require.Equal(t, "special4", result.Doc["test"])
+
+		expected, err = New("test", store, pc).Resolve(uniqueSuffix)
+		require.NoError(t, err)
+		require.Equal(t, expected, result)
+
+		deactivateOp, err := getDeactivateOperation(nextRecoveryKey, uniqueSuffix)
+		require.NoError(t, err)
+		require.NoError(t, store.Put(getAnchoredOperation(deactivateOp, 5)))
+
+		result, err = p.Resolve(uniqueSuffix)
+		require.NoError(t, err)
+		require.True(t, result.Deactivated)
+
+		result, err = p.Resolve(uniqueSuffix)
+		require.NoError(t, err)
+		require.True(t, result.Deactivated)
+	})
+
+	t.Run("snapshot is invalidated if an earlier operation is added", func(t *testing.T) {
+		pc, applied := newCountingProtocolClient()
+
+		store, uniqueSuffix := getDefaultStore(recoveryKey, updateKey)
+
+		updateOp, nextUpdateKey, err := getAnchoredUpdateOperation(updateKey, uniqueSuffix, 1)
+		require.NoError(t, err)
+
+		lateOp, _, err := getAnchoredUpdateOperation(nextUpdateKey, uniqueSuffix, 2)
+		require.NoError(t, err)
+
+		require.NoError(t, store.Put(updateOp))
+
+		snapshots := NewMemSnapshotStore()
+		p := New("test", store, pc, WithSnapshotStore(snapshots), WithSnapshotMinOperations(1))
+
+		_, err = p.Resolve(uniqueSuffix)
+		require.NoError(t, err)
+
+		// replace the state of the snapshot in order to detect whether the snapshot is used
+		snapshot, err := snapshots.Get(uniqueSuffix)
+		require.NoError(t, err)
+
+		snapshot.State = &protocol.ResolutionModel{
+			Doc:              map[string]interface{}{"test": "from-snapshot"},
+			UpdateCommitment: snapshot.State.UpdateCommitment,
+		}
+
+		require.NoError(t, snapshots.Put(uniqueSuffix, snapshot))
+
+		result, err := p.Resolve(uniqueSuffix)
+		require.NoError(t, err)
+		require.Equal(t, "from-snapshot", result.Doc["test"])
+
+		// an operation that is ordered before the latest operation of the snapshot invalidates the snapshot
+		lateOp.TransactionTime = 1
+		lateOp.TransactionNumber = 0
+		require.NoError(t, store.Put(lateOp))
+
+		*applied = 0
+
+		result, err = p.Resolve(uniqueSuffix)
+		require.NoError(t, err)
+		require.Equal(t, "special2", result.Doc["test"])
+		require.Equal(t, int32(3), *applied)
+
+		snapshot, err = snapshots.Get(uniqueSuffix)
+		require.NoError(t, err)
+		require.Equal(t, 3, snapshot.OperationCount)
+	})
+
+	t.Run("snapshot is not stored for fewer than minimum operations", func(t *testing.T) {
+		store, uniqueSuffix := getDefaultStore(recoveryKey, updateKey)
+		addUpdateOperations(t, store, updateKey, uniqueSuffix, 1, 2)
+
+		snapshots := NewMemSnapshotStore()
+		p := New("test", store, newMockProtocolClient(), WithSnapshotStore(snapshots))
+
+		_, err := p.Resolve(uniqueSuffix)
+		require.NoError(t, err)
+
+		_, err = snapshots.Get(uniqueSuffix)
+		require.True(t, errors.Is(err, ErrSnapshotNotFound))
+	})
+
+	t.Run("snapshot store errors", func(t *testing.T) {
+		store, uniqueSuffix := getDefaultStore(recoveryKey, updateKey)
+		addUpdateOperations(t, store, updateKey, uniqueSuffix, 1, 2)
+
+		snapshots := &mockSnapshotStore{
+			MemSnapshotStore: NewMemSnapshotStore(),
+			getErr:           errors.New("get error"),
+			putErr:           errors.New("put error"),
+		}
+
+		p := New("test", store, newMockProtocolClient(), WithSnapshotStore(snapshots), WithSnapshotMinOperations(1))
+
+		result, err := p.Resolve(uniqueSuffix)
+		require.NoError(t, err)
+		require.Equal(t, "special2", result.Doc["test"])
+
+		// snapshot that is invalid and can't be deleted
+		snapshots.getErr = nil
+		snapshots.deleteErr = errors.New("delete error")
+
+		require.NoError(t, snapshots.MemSnapshotStore.Put(uniqueSuffix, &Snapshot{OperationCount: 1}))
+
+		result, err = p.Resolve(uniqueSuffix)
+		require.NoError(t, err)
+		require.Equal(t, "special2", result.Doc["test"])
+	})
+}
+
+func TestResolve_Version(t *testing.T) {
+	recoveryKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	updateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	store, uniqueSuffix := getDefaultStore(recoveryKey, updateKey)
+	addUpdateOperations(t, store, updateKey, uniqueSuffix, 1, 3)
+
+	snapshots := NewMemSnapshotStore()
+	p := New("test", store, newMockProtocolClient(), WithSnapshotStore(snapshots), WithSnapshotMinOperations(1))
+
+	latest, err := p.Resolve(uniqueSuffix)
+	require.NoError(t, err)
+	require.Equal(t, "special3", latest.Doc["test"])
+
+	t.Run("success - version ID", func(t *testing.T) {
+		result, err := p.Resolve(uniqueSuffix, document.WithVersionID(1))
+		require.NoError(t, err)
+		require.NotContains(t, result.Doc, "test")
+
+		result, err = p.Resolve(uniqueSuffix, document.WithVersionID(2))
+		require.NoError(t, err)
+		require.Equal(t, "special1", result.Doc["test"])
+
+		result, err = p.Resolve(uniqueSuffix, document.WithVersionID(4))
+		require.NoError(t, err)
+		require.Equal(t, latest, result)
+	})
+
+	t.Run("success - version time", func(t *testing.T) {
+		result, err := p.Resolve(uniqueSuffix, document.WithVersionTime(2))
+		require.NoError(t, err)
+		require.Equal(t, "special2", result.Doc["test"])
+
+		result, err = p.Resolve(uniqueSuffix, document.WithVersionTransaction(1, 0))
+		require.NoError(t, err)
+		require.Equal(t, "special1", result.Doc["test"])
+
+		result, err = p.Resolve(uniqueSuffix, document.WithVersionTime(100))
+		require.NoError(t, err)
+		require.Equal(t, latest, result)
+	})
+
+	t.Run("success - version ID and version time", func(t *testing.T) {
+		result, err := p.Resolve(uniqueSuffix, document.WithVersionTime(3), document.WithVersionID(2))
+		require.NoError(t, err)
+		require.Equal(t, "special1", result.Doc["test"])
+	})
+
+	t.Run("success - version timestamp", func(t *testing.T) {
+		p := New("test", store, newMockProtocolClient(), WithTransactionTimeProvider(
+			txnTimeProviderFunc(func(t time.Time) (uint64, error) { return uint64(t.Unix()), nil }),
+		))
+
+		result, err := p.Resolve(uniqueSuffix, document.WithVersionTimestamp(time.Unix(2, 0)))
+		require.NoError(t, err)
+		require.Equal(t, "special2", result.Doc["test"])
+	})
+
+	t.Run("error - version timestamp without transaction time provider", func(t *testing.T) {
+		result, err := p.Resolve(uniqueSuffix, document.WithVersionTimestamp(time.Unix(2, 0)))
+		require.Error(t, err)
+		require.Nil(t, result)
+		require.True(t, errors.Is(err, document.ErrInvalidOptions))
+		require.Contains(t, err.Error(), "versionTime timestamps are not supported")
+	})
+
+	t.Run("error - transaction time provider error", func(t *testing.T) {
+		p := New("test", store, newMockProtocolClient(), WithTransactionTimeProvider(
+			txnTimeProviderFunc(func(time.Time) (uint64, error) { return 0, errors.New("out of range") }),
+		))
+
+		result, err := p.Resolve(uniqueSuffix, document.WithVersionTimestamp(time.Unix(2, 0)))
+		require.Error(t, err)
+		require.Nil(t, result)
+		require.True(t, errors.Is(err, document.ErrInvalidOptions))
+		require.Contains(t, err.Error(), "out of range")
+	})
+
+	t.Run("snapshot is not affected", func(t *testing.T) {
+		snapshot, err := snapshots.Get(uniqueSuffix)
+		require.NoError(t, err)
+		require.Equal(t, latest, snapshot.State)
+		require.Equal(t, 4, snapshot.OperationCount)
+	})
+
+	t.Run("error - version not found", func(t *testing.T) {
+		result, err := p.Resolve(uniqueSuffix, document.WithVersionID(5))
+		require.Error(t, err)
+		require.Nil(t, result)
+		require.True(t, errors.Is(err, operation.ErrNotFound))
+
+		result, err = p.Resolve(uniqueSuffix, document.WithVersionTime(3), document.WithVersionID(5))
+		require.Error(t, err)
+		require.Nil(t, result)
+		require.True(t, errors.Is(err, operation.ErrNotFound))
+	})
+}
+
+func TestResolve_VersionWithRejectedOperation(t *testing.T) {
+	recoveryKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	updateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	otherKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	store, uniqueSuffix := getDefaultStore(recoveryKey, updateKey)
+	updateKey = addUpdateOperations(t, store, updateKey, uniqueSuffix, 1, 1)
+
+	// rejected since it doesn't reveal the update commitment
+	rejectedOp, _, err := getAnchoredUpdateOperation(otherKey, uniqueSuffix, 2)
+	require.NoError(t, err)
+	require.NoError(t, store.Put(rejectedOp))
+
+	addUpdateOperations(t, store, updateKey, uniqueSuffix, 3, 3)
+
+	p := New("test", store, newMockProtocolClient())
+
+	result, err := p.Resolve(uniqueSuffix, document.WithVersionID(2))
+	require.NoError(t, err)
+	require.Equal(t, "special1", result.Doc["test"])
+
+	// the rejected operation is not counted
+	result, err = p.Resolve(uniqueSuffix, document.WithVersionID(3))
+	require.NoError(t, err)
+	require.Equal(t, "special3", result.Doc["test"])
+
+	result, err = p.Resolve(uniqueSuffix, document.WithVersionID(4))
+	require.Error(t, err)
+	require.Nil(t, result)
+	require.True(t, errors.Is(err, operation.ErrNotFound))
+}
+
+func TestResolve_VersionMetadata(t *testing.T) {
+	recoveryKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	updateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	store, uniqueSuffix := getDefaultStore(recoveryKey, updateKey)
+
+	p := New("test", store, newMockProtocolClient())
+
+	created, err := p.Resolve(uniqueSuffix)
+	require.NoError(t, err)
+	require.NotEmpty(t, created.VersionID)
+	require.Equal(t, uint64(0), created.UpdatedTime)
+	require.Empty(t, created.NextVersionID)
+
+	addUpdateOperations(t, store, updateKey, uniqueSuffix, 1, 2)
+
+	latest, err := p.Resolve(uniqueSuffix)
+	require.NoError(t, err)
+	require.NotEmpty(t, latest.VersionID)
+	require.NotEqual(t, created.VersionID, latest.VersionID)
+	require.Equal(t, created.CreatedTime, latest.CreatedTime)
+	require.Equal(t, uint64(2), latest.UpdatedTime)
+	require.Empty(t, latest.NextVersionID)
+	require.Equal(t, uint64(0), latest.NextUpdateTime)
+
+	t.Run("success - version hash", func(t *testing.T) {
+		result, err := p.Resolve(uniqueSuffix, document.WithVersionHash(created.VersionID))
+		require.NoError(t, err)
+		require.NotContains(t, result.Doc, "test")
+		require.Equal(t, created.VersionID, result.VersionID)
+
+		result, err = p.Resolve(uniqueSuffix, document.WithVersionHash(latest.VersionID))
+		require.NoError(t, err)
+		require.Equal(t, latest, result)
+	})
+
+	t.Run("success - next version", func(t *testing.T) {
+		second, err := p.Resolve(uniqueSuffix, document.WithVersionID(2))
+		require.NoError(t, err)
+		require.Equal(t, "special1", second.Doc["test"])
+		require.Equal(t, uint64(1), second.UpdatedTime)
+		require.Equal(t, latest.VersionID, second.NextVersionID)
+		require.Equal(t, uint64(2), second.NextUpdateTime)
+
+		first, err := p.Resolve(uniqueSuffix, document.WithVersionID(1))
+		require.NoError(t, err)
+		require.Equal(t, created.VersionID, first.VersionID)
+		require.Equal(t, second.VersionID, first.NextVersionID)
+		require.Equal(t, uint64(1), first.NextUpdateTime)
+	})
+
+	t.Run("error - version hash not found", func(t *testing.T) {
+		result, err := p.Resolve(uniqueSuffix, document.WithVersionHash("unknown"))
+		require.Error(t, err)
+		require.Nil(t, result)
+		require.True(t, errors.Is(err, operation.ErrNotFound))
+	})
+
+	t.Run("error - protocol without multihash algorithm", func(t *testing.T) {
+		_, err := getVersionID(&operation.AnchoredOperation{}, protocol.Protocol{})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "protocol doesn't define a multihash algorithm")
+
+		_, err = getVersionID(&operation.AnchoredOperation{}, protocol.Protocol{MultihashAlgorithms: []uint{55}})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "compute version ID")
+	})
+}
+
+func TestResolve_OperationHistory(t *testing.T) {
+	recoveryKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	updateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	pc := newMockProtocolClient()
+
+	t.Run("success - updates", func(t *testing.T) {
+		store, uniqueSuffix := getDefaultStore(recoveryKey, updateKey)
+		addUpdateOperations(t, store, updateKey, uniqueSuffix, 1, 1)
+
+		// update that uses the same commitment as the first update
+		duplicateOp, _, err := getAnchoredUpdateOperation(updateKey, uniqueSuffix, 2)
+		require.NoError(t, err)
+		require.NoError(t, store.Put(duplicateOp))
+
+		// update with an unknown key
+		otherKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		require.NoError(t, err)
+
+		unknownOp, _, err := getAnchoredUpdateOperation(otherKey, uniqueSuffix, 3)
+		require.NoError(t, err)
+		require.NoError(t, store.Put(unknownOp))
+
+		p := New("test", store, pc)
+
+		expected, err := p.Resolve(uniqueSuffix)
+		require.NoError(t, err)
+		require.Nil(t, expected.OperationHistory)
+
+		result, err := p.Resolve(uniqueSuffix, document.WithOperationHistory())
+		require.NoError(t, err)
+		require.Equal(t, expected.Doc, result.Doc)
+		require.Equal(t, expected.UpdateCommitment, result.UpdateCommitment)
+
+		history := result.OperationHistory
+		require.Len(t, history, 4)
+
+		require.Equal(t, operation.TypeCreate, history[0].Type)
+		require.True(t, history[0].Applied)
+		require.Empty(t, history[0].Reason)
+		require.Equal(t, expected.RecoveryCommitment, history[0].RecoveryCommitment)
+
+		require.Equal(t, operation.TypeUpdate, history[1].Type)
+		require.True(t, history[1].Applied)
+		require.Equal(t, uint64(1), history[1].TransactionTime)
+		require.Equal(t, expected.UpdateCommitment, history[1].UpdateCommitment)
+
+		require.False(t, history[2].Applied)
+		require.Equal(t, uint64(2), history[2].TransactionTime)
+		require.Equal(t, "another operation for the same commitment(key) was applied", history[2].Reason)
+		require.Empty(t, history[2].UpdateCommitment)
+
+		require.False(t, history[3].Applied)
+		require.Equal(t, "operation doesn't reveal a commitment of the document's commitment chain", history[3].Reason)
+	})
+
+	t.Run("success - update before recover", func(t *testing.T) {
+		store, uniqueSuffix := getDefaultStore(recoveryKey, updateKey)
+		addUpdateOperations(t, store, updateKey, uniqueSuffix, 1, 1)
+
+		recoverOp, _, err := getAnchoredRecoverOperation(recoveryKey, updateKey, uniqueSuffix, 2)
+		require.NoError(t, err)
+		require.NoError(t, store.Put(recoverOp))
+
+		result, err := New("test", store, pc).Resolve(uniqueSuffix, document.WithOperationHistory())
+		require.NoError(t, err)
+
+		history := result.OperationHistory
+		require.Len(t, history, 3)
+		require.True(t, history[0].Applied)
+		require.False(t, history[1].Applied)
+		require.Equal(t, "operation was anchored before the last recover operation", history[1].Reason)
+		require.True(t, history[2].Applied)
+		require.Equal(t, operation.TypeRecover, history[2].Type)
+		require.Equal(t, result.RecoveryCommitment, history[2].RecoveryCommitment)
+	})
+
+	t.Run("success - bad operation", func(t *testing.T) {
+		store, uniqueSuffix := getDefaultStore(recoveryKey, updateKey)
+
+		updateOp, _, err := getAnchoredUpdateOperation(updateKey, uniqueSuffix, 1)
+		require.NoError(t, err)
+
+		updateOp.OperationBuffer = []byte("{}")
+		require.NoError(t, store.Put(updateOp))
+
+		result, err := New("test", store, pc).Resolve(uniqueSuffix, document.WithOperationHistory())
+		require.NoError(t, err)
+
+		history := result.OperationHistory
+		require.Len(t, history, 2)
+		require.False(t, history[1].Applied)
+		require.Contains(t, history[1].Reason, "get operation reveal value")
 	})
 }
 
@@ -1353,3 +1864,82 @@ func newMockProtocolClient() *mocks.MockProtocolClient {
 
 	return pc
 }
+
+// addUpdateOperations adds update operations for the given range of block numbers and returns the next update key.
+func addUpdateOperations(t *testing.T, store *mocks.MockOperationStore, updateKey *ecdsa.PrivateKey,
+	uniqueSuffix string, from, to uint64) *ecdsa.PrivateKey {
+	t.Helper()
+
+	for i := from; i <= to; i++ {
+		updateOp, nextUpdateKey, err := getAnchoredUpdateOperation(updateKey, uniqueSuffix, i)
+		require.NoError(t, err)
+		require.NoError(t, store.Put(updateOp))
+
+		updateKey = nextUpdateKey
+	}
+
+	return updateKey
+}
+
+// newCountingProtocolClient returns a protocol client whose operation appliers count the number of applied operations.
+func newCountingProtocolClient() (*mocks.MockProtocolClient, *int32) {
+	pc := newMockProtocolClient()
+
+	var applied int32
+
+	for _, v := range pc.Versions {
+		v.OperationApplierReturns(&countingApplier{OperationApplier: v.OperationApplier(), applied: &applied})
+	}
+
+	return pc, &applied
+}
+
+type countingApplier struct {
+	protocol.OperationApplier
+
+	applied *int32
+}
+
+func (a *countingApplier) Apply(op *operation.AnchoredOperation, rm *protocol.ResolutionModel) (*protocol.ResolutionModel, error) {
+	*a.applied++
+
+	return a.OperationApplier.Apply(op, rm)
+}
+
+type mockSnapshotStore struct {
+	*MemSnapshotStore
+
+	getErr    error
+	putErr    error
+	deleteErr error
+}
+
+func (m *mockSnapshotStore) Get(uniqueSuffix string) (*Snapshot, error) {
+	if m.getErr != nil {
+		return nil, m.getErr
+	}
+
+	return m.MemSnapshotStore.Get(uniqueSuffix)
+}
+
+func (m *mockSnapshotStore) Put(uniqueSuffix string, snapshot *Snapshot) error {
+	if m.putErr != nil {
+		return m.putErr
+	}
+
+	return m.MemSnapshotStore.Put(uniqueSuffix, snapshot)
+}
+
+func (m *mockSnapshotStore) Delete(uniqueSuffix string) error {
+	if m.deleteErr != nil {
+		return m.deleteErr
+	}
+
+	return m.MemSnapshotStore.Delete(uniqueSuffix)
+}
+
+type txnTimeProviderFunc func(t time.Time) (uint64, error)
+
+func (f txnTimeProviderFunc) TransactionTime(t time.Time) (uint64, error) {
+	return f(t)
+}
@@ -0,0 +1,231 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package processor
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/trustbloc/sidetree-core-go/pkg/api/protocol"
+)
+
+// ErrSnapshotNotFound is returned when no snapshot has been stored for a unique suffix.
+var ErrSnapshotNotFound = errors.New("snapshot not found")
+
+// Snapshot contains the resolution state of a document after all operations that were known at the time
+// the snapshot was taken have been processed. Snapshot stores return copies of the stored snapshots so that
+// the resolution models of a returned snapshot may be handed to callers.
+type Snapshot struct {
+	// State is the resolution model after all operations were applied.
+	State *protocol.ResolutionModel `json:"state"`
+
+	// FullState is the resolution model after the create operation and all full (recover and deactivate)
+	// operations were applied. Update operations are applied on top of this state.
+	FullState *protocol.ResolutionModel `json:"fullState"`
+
+	// RecoveryCommitments contains the recovery commitments that were processed for full operations.
+	RecoveryCommitments []string `json:"recoveryCommitments,omitempty"`
+
+	// UpdateCommitments contains the update commitments that were processed for update operations
+	// (since the last full operation).
+	UpdateCommitments []string `json:"updateCommitments,omitempty"`
+
+	// TransactionTime is the transaction time of the latest operation that was known when the snapshot was taken.
+	TransactionTime uint64 `json:"transactionTime"`
+
+	// TransactionNumber is the transaction number of the latest operation that was known when the snapshot was taken.
+	TransactionNumber uint64 `json:"transactionNumber"`
+
+	// OperationCount is the number of operations that were known when the snapshot was taken.
+	OperationCount int `json:"operationCount"`
+}
+
+// SnapshotStore persists resolution snapshots.
+type SnapshotStore interface {
+	// Get returns the snapshot for the given unique suffix or ErrSnapshotNotFound.
+	Get(uniqueSuffix string) (*Snapshot, error)
+	// Put stores the snapshot for the given unique suffix (replacing an existing snapshot).
+	Put(uniqueSuffix string, snapshot *Snapshot) error
+	// Delete deletes the snapshot for the given unique suffix.
+	Delete(uniqueSuffix string) error
+}
+
+// MemSnapshotStore implements an in-memory snapshot store. Snapshots are stored in serialized form
+// so that stored snapshots aren't affected by modifications of the snapshots that were put or returned.
+type MemSnapshotStore struct {
+	snapshots map[string][]byte
+	mutex     sync.RWMutex
+}
+
+// NewMemSnapshotStore returns a new in-memory snapshot store.
+func NewMemSnapshotStore() *MemSnapshotStore {
+	return &MemSnapshotStore{
+		snapshots: make(map[string][]byte),
+	}
+}
+
+// Get returns a copy of the snapshot for the given unique suffix.
+func (s *MemSnapshotStore) Get(uniqueSuffix string) (*Snapshot, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	content, ok := s.snapshots[uniqueSuffix]
+	if !ok {
+		return nil, ErrSnapshotNotFound
+	}
+
+	return unmarshalSnapshot(content)
+}
+
+// Put stores a copy of the snapshot for the given unique suffix.
+func (s *MemSnapshotStore) Put(uniqueSuffix string, snapshot *Snapshot) error {
+	content, err := json.Marshal(snapshot)
+	if err != nil {
+		return fmt.Errorf("marshal snapshot: %w", err)
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.snapshots[uniqueSuffix] = content
+
+	return nil
+}
+
+// Delete deletes the snapshot for the given unique suffix.
+func (s *MemSnapshotStore) Delete(uniqueSuffix string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	delete(s.snapshots, uniqueSuffix)
+
+	return nil
+}
+
+// FileSnapshotStore implements a snapshot store that persists the snapshot of each document in a JSON file
+// (named after the unique suffix) in the given directory. Files are replaced atomically.
+type FileSnapshotStore struct {
+	dir string
+}
+
+// NewFileSnapshotStore returns a new snapshot store that persists snapshots in the given directory.
+func NewFileSnapshotStore(dir string) (*FileSnapshotStore, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("create snapshot directory: %w", err)
+	}
+
+	return &FileSnapshotStore{dir: dir}, nil
+}
+
+// Get returns the snapshot for the given unique suffix.
+func (s *FileSnapshotStore) Get(uniqueSuffix string) (*Snapshot, error) {
+	path, err := s.path(uniqueSuffix)
+	if err != nil {
+		return nil, err
+	}
+
+	content, err := ioutil.ReadFile(filepath.Clean(path))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, ErrSnapshotNotFound
+		}
+
+		return nil, fmt.Errorf("read snapshot file[%s]: %w", path, err)
+	}
+
+	snapshot, err := unmarshalSnapshot(content)
+	if err != nil {
+		return nil, fmt.Errorf("snapshot file[%s]: %w", path, err)
+	}
+
+	return snapshot, nil
+}
+
+// Put stores the snapshot for the given unique suffix.
+func (s *FileSnapshotStore) Put(uniqueSuffix string, snapshot *Snapshot) error {
+	path, err := s.path(uniqueSuffix)
+	if err != nil {
+		return err
+	}
+
+	content, err := json.Marshal(snapshot)
+	if err != nil {
+		return fmt.Errorf("marshal snapshot: %w", err)
+	}
+
+	// concurrent puts for the same suffix use different temporary files
+	tmp, err := ioutil.TempFile(s.dir, uniqueSuffix+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("create snapshot file: %w", err)
+	}
+
+	if _, err := tmp.Write(content); err != nil {
+		removeFile(tmp)
+
+		return fmt.Errorf("write snapshot file: %w", err)
+	}
+
+	if err := tmp.Close(); err != nil {
+		removeFile(tmp)
+
+		return fmt.Errorf("close snapshot file: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		removeFile(tmp)
+
+		return fmt.Errorf("replace snapshot file: %w", err)
+	}
+
+	return nil
+}
+
+// Delete deletes the snapshot for the given unique suffix.
+func (s *FileSnapshotStore) Delete(uniqueSuffix string) error {
+	path, err := s.path(uniqueSuffix)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("delete snapshot file: %w", err)
+	}
+
+	return nil
+}
+
+func (s *FileSnapshotStore) path(uniqueSuffix string) (string, error) {
+	if uniqueSuffix == "" || strings.ContainsAny(uniqueSuffix, `/\.`) {
+		return "", fmt.Errorf("invalid unique suffix [%s]", uniqueSuffix)
+	}
+
+	return filepath.Join(s.dir, uniqueSuffix+".json"), nil
+}
+
+func removeFile(file *os.File) {
+	_ = file.Close() //nolint:errcheck
+
+	if err := os.Remove(file.Name()); err != nil {
+		logger.Warnf("Error removing file [%s]: %s", file.Name(), err)
+	}
+}
+
+func unmarshalSnapshot(content []byte) (*Snapshot, error) {
+	snapshot := &Snapshot{}
+
+	if err := json.Unmarshal(content, snapshot); err != nil {
+		return nil, fmt.Errorf("unmarshal snapshot: %w", err)
+	}
+
+	return snapshot, nil
+}
@@ -0,0 +1,168 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package processor
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/sidetree-core-go/pkg/api/protocol"
+	"github.com/trustbloc/sidetree-core-go/pkg/document"
+)
+
+func TestMemSnapshotStore(t *testing.T) {
+	s := NewMemSnapshotStore()
+
+	snapshot, err := s.Get("suffix")
+	require.True(t, errors.Is(err, ErrSnapshotNotFound))
+	require.Nil(t, snapshot)
+
+	expected := &Snapshot{
+		State:             &protocol.ResolutionModel{UpdateCommitment: "uc"},
+		FullState:         &protocol.ResolutionModel{RecoveryCommitment: "rc"},
+		TransactionTime:   10,
+		TransactionNumber: 2,
+		OperationCount:    3,
+	}
+
+	require.NoError(t, s.Put("suffix", expected))
+
+	snapshot, err = s.Get("suffix")
+	require.NoError(t, err)
+	require.Equal(t, expected, snapshot)
+
+	t.Run("stored snapshot is a copy", func(t *testing.T) {
+		testSnapshotCopies(t, s)
+	})
+
+	require.NoError(t, s.Delete("suffix"))
+	require.NoError(t, s.Delete("suffix"))
+
+	snapshot, err = s.Get("suffix")
+	require.True(t, errors.Is(err, ErrSnapshotNotFound))
+	require.Nil(t, snapshot)
+
+	t.Run("error - marshal snapshot", func(t *testing.T) {
+		err := s.Put("suffix", &Snapshot{State: &protocol.ResolutionModel{AnchorOrigin: make(chan int)}})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "marshal snapshot")
+	})
+}
+
+func TestFileSnapshotStore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "snapshot")
+	require.NoError(t, err)
+
+	defer func() {
+		require.NoError(t, os.RemoveAll(dir))
+	}()
+
+	s, err := NewFileSnapshotStore(filepath.Join(dir, "snapshots"))
+	require.NoError(t, err)
+
+	snapshot, err := s.Get("suffix")
+	require.True(t, errors.Is(err, ErrSnapshotNotFound))
+	require.Nil(t, snapshot)
+
+	expected := &Snapshot{
+		State: &protocol.ResolutionModel{
+			Doc:              document.Document{"id": "suffix"},
+			UpdateCommitment: "uc",
+		},
+		FullState:           &protocol.ResolutionModel{RecoveryCommitment: "rc"},
+		RecoveryCommitments: []string{"rc0"},
+		UpdateCommitments:   []string{"uc0", "uc1"},
+		TransactionTime:     10,
+		TransactionNumber:   2,
+		OperationCount:      3,
+	}
+
+	require.NoError(t, s.Put("suffix", expected))
+
+	// snapshots are persisted
+	s, err = NewFileSnapshotStore(filepath.Join(dir, "snapshots"))
+	require.NoError(t, err)
+
+	snapshot, err = s.Get("suffix")
+	require.NoError(t, err)
+	require.Equal(t, expected, snapshot)
+
+	t.Run("stored snapshot is a copy", func(t *testing.T) {
+		testSnapshotCopies(t, s)
+	})
+
+	require.NoError(t, s.Delete("suffix"))
+	require.NoError(t, s.Delete("suffix"))
+
+	snapshot, err = s.Get("suffix")
+	require.True(t, errors.Is(err, ErrSnapshotNotFound))
+	require.Nil(t, snapshot)
+
+	t.Run("error - invalid unique suffix", func(t *testing.T) {
+		for _, suffix := range []string{"", "../suffix", "a/b"} {
+			_, err := s.Get(suffix)
+			require.Error(t, err)
+			require.Contains(t, err.Error(), "invalid unique suffix")
+
+			require.Error(t, s.Put(suffix, expected))
+			require.Error(t, s.Delete(suffix))
+		}
+	})
+
+	t.Run("error - corrupted snapshot file", func(t *testing.T) {
+		require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "snapshots", "corrupted.json"), []byte("{"), 0o600))
+
+		snapshot, err := s.Get("corrupted")
+		require.Error(t, err)
+		require.Nil(t, snapshot)
+		require.Contains(t, err.Error(), "unmarshal snapshot")
+	})
+
+	t.Run("error - marshal snapshot", func(t *testing.T) {
+		err := s.Put("suffix", &Snapshot{State: &protocol.ResolutionModel{AnchorOrigin: make(chan int)}})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "marshal snapshot")
+	})
+
+	t.Run("error - invalid directory", func(t *testing.T) {
+		file := filepath.Join(dir, "file")
+		require.NoError(t, ioutil.WriteFile(file, []byte("content"), 0o600))
+
+		s, err := NewFileSnapshotStore(filepath.Join(file, "snapshots"))
+		require.Error(t, err)
+		require.Nil(t, s)
+		require.Contains(t, err.Error(), "create snapshot directory")
+	})
+}
+
+// testSnapshotCopies verifies that modifications of put or returned snapshots don't affect the stored snapshot.
+func testSnapshotCopies(t *testing.T, s SnapshotStore) {
+	t.Helper()
+
+	snapshot := &Snapshot{State: &protocol.ResolutionModel{Doc: document.Document{"key": "value"}}}
+
+	require.NoError(t, s.Put("copy", snapshot))
+
+	snapshot.State.Doc["key"] = "modified after put"
+
+	stored, err := s.Get("copy")
+	require.NoError(t, err)
+	require.Equal(t, "value", stored.State.Doc["key"])
+
+	stored.State.Doc["key"] = "modified after get"
+
+	stored, err = s.Get("copy")
+	require.NoError(t, err)
+	require.Equal(t, "value", stored.State.Doc["key"])
+
+	require.NoError(t, s.Delete("copy"))
+}
@@ -0,0 +1,215 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package processor
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/trustbloc/sidetree-core-go/pkg/api/operation"
+	"github.com/trustbloc/sidetree-core-go/pkg/api/protocol"
+	"github.com/trustbloc/sidetree-core-go/pkg/document"
+	"github.com/trustbloc/sidetree-core-go/pkg/encoder"
+	"github.com/trustbloc/sidetree-core-go/pkg/hashing"
+)
+
+// resolveWithOptions resolves the requested version of the document and (optionally) records the operation
+// history. Snapshots are not used since they may include operations that were anchored after the requested
+// version and since they don't contain the history of the operations.
+func (s *OperationProcessor) resolveWithOptions(uniqueSuffix string, ops []*operation.AnchoredOperation,
+	opts document.ResolutionOptions) (*protocol.ResolutionModel, error) {
+	opts, err := s.resolveVersionTimestamp(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	versionOps := s.getOpsUpToVersion(ops, opts)
+	if len(versionOps) == 0 {
+		return nil, fmt.Errorf("version of unique suffix [%s] not found: %w", uniqueSuffix, operation.ErrNotFound)
+	}
+
+	logger.Debugf("[%s] Resolving unique suffix [%s] from %d operations with options %+v", s.name, uniqueSuffix, len(versionOps), opts)
+
+	var history *operationHistory
+	if opts.OperationHistory {
+		history = newOperationHistory()
+	}
+
+	r, err := s.resolve(versionOps, nil, history)
+	if err != nil {
+		return nil, err
+	}
+
+	// the resolved state may be shared (e.g. with the full state) so return a copy
+	state := *r.state
+
+	if history != nil {
+		state.OperationHistory = history.get(versionOps, r)
+	}
+
+	if len(versionOps) < len(ops) {
+		s.setNextVersion(&state, ops, versionOps)
+	}
+
+	return &state, nil
+}
+
+// resolveVersionTimestamp converts the version timestamp (if any) into a version time.
+func (s *OperationProcessor) resolveVersionTimestamp(opts document.ResolutionOptions) (document.ResolutionOptions, error) {
+	if opts.VersionTimestamp == nil {
+		return opts, nil
+	}
+
+	if s.txnTimeProvider == nil {
+		return opts, fmt.Errorf("%w: %s timestamps are not supported, use a transaction time instead",
+			document.ErrInvalidOptions, document.VersionTimeParam)
+	}
+
+	transactionTime, err := s.txnTimeProvider.TransactionTime(*opts.VersionTimestamp)
+	if err != nil {
+		return opts, fmt.Errorf("%w: %s[%s]: %s", document.ErrInvalidOptions, document.VersionTimeParam,
+			opts.VersionTimestamp.Format(time.RFC3339), err.Error())
+	}
+
+	document.WithVersionTime(transactionTime)(&opts)
+
+	return opts, nil
+}
+
+// setNextVersion sets the version ID and the time of the first operation that was anchored after the given
+// version operations and that is applied when resolving the latest version of the document.
+func (s *OperationProcessor) setNextVersion(state *protocol.ResolutionModel, ops, versionOps []*operation.AnchoredOperation) {
+	history := newOperationHistory()
+
+	if _, err := s.resolve(ops, nil, history); err != nil {
+		logger.Infof("[%s] Unable to resolve next version of unique suffix [%s]: %s", s.name, ops[0].UniqueSuffix, err)
+
+		return
+	}
+
+	included := make(map[*operation.AnchoredOperation]bool, len(versionOps))
+	for _, op := range versionOps {
+		included[op] = true
+	}
+
+	for _, op := range ops {
+		entry, ok := history.entries[op]
+		if included[op] || !ok || !entry.Applied {
+			continue
+		}
+
+		versionID, err := s.getOperationVersionID(op)
+		if err != nil {
+			logger.Infof("[%s] Unable to get version ID of next operation for unique suffix [%s]: %s", s.name, op.UniqueSuffix, err)
+
+			return
+		}
+
+		state.NextVersionID = versionID
+		state.NextUpdateTime = op.TransactionTime
+
+		return
+	}
+}
+
+// getOpsUpToVersion returns the operations that belong to the requested version (pre-condition: operations
+// have to be sorted). Nil is returned if the version doesn't exist.
+func (s *OperationProcessor) getOpsUpToVersion(ops []*operation.AnchoredOperation, opts document.ResolutionOptions) []*operation.AnchoredOperation {
+	if opts.VersionTime != nil {
+		var filtered []*operation.AnchoredOperation
+
+		for _, op := range ops {
+			if !isAfter(op, opts.VersionTime.TransactionTime, opts.VersionTime.TransactionNumber) {
+				filtered = append(filtered, op)
+			}
+		}
+
+		ops = filtered
+	}
+
+	if opts.VersionID > 0 {
+		ops = s.getOpsUpToAppliedCount(ops, opts.VersionID)
+	}
+
+	if opts.VersionHash != "" {
+		return s.getOpsUpToVersionHash(ops, opts.VersionHash)
+	}
+
+	return ops
+}
+
+// getOpsUpToAppliedCount returns the operations up to (and including) the operation that is applied as the
+// given number of applied operations. Operations that are not applied (e.g. rejected operations) are not counted.
+// Nil is returned if fewer operations are applied.
+func (s *OperationProcessor) getOpsUpToAppliedCount(ops []*operation.AnchoredOperation, count uint64) []*operation.AnchoredOperation {
+	history := newOperationHistory()
+
+	if _, err := s.resolve(ops, nil, history); err != nil {
+		logger.Debugf("[%s] Unable to resolve operations while searching version %d: %s", s.name, count, err)
+
+		return nil
+	}
+
+	var applied uint64
+
+	for i, op := range ops {
+		if entry, ok := history.entries[op]; !ok || !entry.Applied {
+			continue
+		}
+
+		applied++
+
+		if applied == count {
+			return ops[:i+1]
+		}
+	}
+
+	return nil
+}
+
+// getOpsUpToVersionHash returns the operations up to (and including) the operation with the given version ID.
+func (s *OperationProcessor) getOpsUpToVersionHash(ops []*operation.AnchoredOperation, versionHash string) []*operation.AnchoredOperation {
+	for i, op := range ops {
+		versionID, err := s.getOperationVersionID(op)
+		if err != nil {
+			logger.Infof("[%s] Skipped operation while searching version {UniqueSuffix: %s, Type: %s, TransactionTime: %d, TransactionNumber: %d}. Reason: %s", s.name, op.UniqueSuffix, op.Type, op.TransactionTime, op.TransactionNumber, err)
+
+			continue
+		}
+
+		if versionID == versionHash {
+			return ops[:i+1]
+		}
+	}
+
+	return nil
+}
+
+func (s *OperationProcessor) getOperationVersionID(op *operation.AnchoredOperation) (string, error) {
+	p, err := s.pc.Get(op.ProtocolGenesisTime)
+	if err != nil {
+		return "", fmt.Errorf("get version ID: %s", err.Error())
+	}
+
+	return getVersionID(op, p.Protocol())
+}
+
+// getVersionID returns the version ID for the given operation which is the multihash of the operation
+// (computed with the first multihash algorithm of the given protocol).
+func getVersionID(op *operation.AnchoredOperation, p protocol.Protocol) (string, error) {
+	if len(p.MultihashAlgorithms) == 0 {
+		return "", errors.New("protocol doesn't define a multihash algorithm")
+	}
+
+	mh, err := hashing.ComputeMultihash(p.MultihashAlgorithms[0], op.OperationBuffer)
+	if err != nil {
+		return "", fmt.Errorf("compute version ID: %s", err.Error())
+	}
+
+	return encoder.EncodeToString(mh), nil
+}
@@ -0,0 +1,149 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package protocolclient provides protocol.Client and protocol.ClientProvider implementations that hold
+// an ordered set of protocol versions per namespace.
+//
+// Protocol versions are ordered by protocol genesis time. The version that applies to an operation is
+// the latest version with a genesis time that is less than or equal to the operation's anchoring time.
+package protocolclient
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/pkg/errors"
+	"github.com/trustbloc/edge-core/pkg/log"
+
+	"github.com/trustbloc/sidetree-core-go/pkg/api/protocol"
+	"github.com/trustbloc/sidetree-core-go/pkg/protocolvalidator"
+)
+
+var logger = log.New("sidetree-core-protocolclient")
+
+// ParametersValidator validates protocol parameters.
+type ParametersValidator interface {
+	Validate(p protocol.Protocol) error
+}
+
+// Option is a protocol client option.
+type Option func(opts *Client)
+
+// WithValidator sets the validator that is used to validate protocol parameters of each registered
+// version (protocolvalidator with default settings is used by default).
+func WithValidator(v ParametersValidator) Option {
+	return func(opts *Client) {
+		opts.validator = v
+	}
+}
+
+// Client implements protocol client. It holds protocol versions sorted by genesis time.
+type Client struct {
+	versions  []protocol.Version
+	validator ParametersValidator
+	mutex     sync.RWMutex
+}
+
+// New returns a new protocol client for the given versions. Versions don't have to be sorted, however
+// genesis time has to be unique across versions.
+func New(versions []protocol.Version, opts ...Option) (*Client, error) {
+	c := newClient(opts...)
+
+	for _, v := range versions {
+		if err := c.Register(v); err != nil {
+			return nil, err
+		}
+	}
+
+	return c, nil
+}
+
+func newClient(opts ...Option) *Client {
+	c := &Client{
+		validator: protocolvalidator.New(),
+	}
+
+	// apply options
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
+}
+
+// Register adds the given protocol version to the client (e.g. protocol upgrade at given genesis time).
+// Protocol parameters are validated before the version is added.
+func (c *Client) Register(v protocol.Version) error {
+	if v == nil {
+		return errors.New("protocol version is required")
+	}
+
+	genesisTime := v.Protocol().GenesisTime
+
+	if err := c.validator.Validate(v.Protocol()); err != nil {
+		return fmt.Errorf("protocol version[%s] with genesis time[%d]: %w", v.Version(), genesisTime, err)
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	for _, existing := range c.versions {
+		if existing.Protocol().GenesisTime == genesisTime {
+			return fmt.Errorf("protocol version[%s] with genesis time[%d] conflicts with existing version[%s]",
+				v.Version(), genesisTime, existing.Version())
+		}
+	}
+
+	versions := append(c.versions, v) //nolint:gocritic
+
+	sort.Slice(versions, func(i, j int) bool {
+		return versions[i].Protocol().GenesisTime < versions[j].Protocol().GenesisTime
+	})
+
+	c.versions = versions
+
+	logger.Debugf("registered protocol version[%s] with genesis time[%d]", v.Version(), genesisTime)
+
+	return nil
+}
+
+// Current returns the latest version of protocol.
+func (c *Client) Current() (protocol.Version, error) {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	if len(c.versions) == 0 {
+		return nil, errors.New("protocol versions are not defined")
+	}
+
+	return c.versions[len(c.versions)-1], nil
+}
+
+// Get returns the protocol version that applies to the given anchoring(transaction) time.
+func (c *Client) Get(transactionTime uint64) (protocol.Version, error) {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	for i := len(c.versions) - 1; i >= 0; i-- {
+		if transactionTime >= c.versions[i].Protocol().GenesisTime {
+			return c.versions[i], nil
+		}
+	}
+
+	return nil, fmt.Errorf("protocol parameters are not defined for anchoring time: %d", transactionTime)
+}
+
+// Versions returns all registered protocol versions sorted by genesis time.
+func (c *Client) Versions() []protocol.Version {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	versions := make([]protocol.Version, len(c.versions))
+	copy(versions, c.versions)
+
+	return versions
+}
@@ -0,0 +1,190 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package protocolclient
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/sidetree-core-go/pkg/api/protocol"
+	"github.com/trustbloc/sidetree-core-go/pkg/mocks"
+)
+
+func TestNew(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		c, err := New([]protocol.Version{newVersion("1.0", 0), newVersion("1.1", 100)})
+		require.NoError(t, err)
+		require.NotNil(t, c)
+		require.Len(t, c.Versions(), 2)
+	})
+
+	t.Run("error - duplicate genesis time", func(t *testing.T) {
+		c, err := New([]protocol.Version{newVersion("1.0", 100), newVersion("1.1", 100)})
+		require.Error(t, err)
+		require.Nil(t, c)
+		require.Contains(t, err.Error(), "protocol version[1.1] with genesis time[100] conflicts with existing version[1.0]")
+	})
+
+	t.Run("error - nil version", func(t *testing.T) {
+		c, err := New([]protocol.Version{nil})
+		require.Error(t, err)
+		require.Nil(t, c)
+		require.Contains(t, err.Error(), "protocol version is required")
+	})
+
+	t.Run("error - invalid protocol parameters", func(t *testing.T) {
+		p := mocks.GetDefaultProtocolParameters()
+		p.CompressionAlgorithm = "LZ4"
+		p.MaxDeltaSize = p.MaxOperationSize
+
+		v := mocks.GetProtocolVersion(p)
+		v.VersionReturns("1.0")
+
+		c, err := New([]protocol.Version{v})
+		require.Error(t, err)
+		require.Nil(t, c)
+		require.Contains(t, err.Error(), "protocol version[1.0] with genesis time[0]: invalid protocol parameters")
+		require.Contains(t, err.Error(), "compression algorithm 'LZ4' is not supported")
+		require.Contains(t, err.Error(), "maxOperationSize[2000] must be greater than maxDeltaSize[2000]")
+	})
+
+	t.Run("success - custom validator", func(t *testing.T) {
+		p := mocks.GetDefaultProtocolParameters()
+		p.CompressionAlgorithm = "LZ4"
+
+		v := mocks.GetProtocolVersion(p)
+		v.VersionReturns("1.0")
+
+		c, err := New([]protocol.Version{v}, WithValidator(&mockValidator{}))
+		require.NoError(t, err)
+		require.Len(t, c.Versions(), 1)
+	})
+}
+
+func TestClient_Current(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		// versions are intentionally not sorted
+		c, err := New([]protocol.Version{newVersion("1.2", 200), newVersion("1.0", 0), newVersion("1.1", 100)})
+		require.NoError(t, err)
+
+		v, err := c.Current()
+		require.NoError(t, err)
+		require.Equal(t, "1.2", v.Version())
+	})
+
+	t.Run("error - no versions", func(t *testing.T) {
+		c, err := New(nil)
+		require.NoError(t, err)
+
+		v, err := c.Current()
+		require.Error(t, err)
+		require.Nil(t, v)
+		require.Contains(t, err.Error(), "protocol versions are not defined")
+	})
+}
+
+func TestClient_Get(t *testing.T) {
+	c, err := New([]protocol.Version{newVersion("1.2", 200), newVersion("1.0", 10), newVersion("1.1", 100)})
+	require.NoError(t, err)
+
+	t.Run("success", func(t *testing.T) {
+		v, err := c.Get(10)
+		require.NoError(t, err)
+		require.Equal(t, "1.0", v.Version())
+
+		v, err = c.Get(99)
+		require.NoError(t, err)
+		require.Equal(t, "1.0", v.Version())
+
+		v, err = c.Get(100)
+		require.NoError(t, err)
+		require.Equal(t, "1.1", v.Version())
+
+		v, err = c.Get(1000)
+		require.NoError(t, err)
+		require.Equal(t, "1.2", v.Version())
+	})
+
+	t.Run("error - transaction time before first genesis time", func(t *testing.T) {
+		v, err := c.Get(5)
+		require.Error(t, err)
+		require.Nil(t, v)
+		require.Contains(t, err.Error(), "protocol parameters are not defined for anchoring time: 5")
+	})
+}
+
+func TestClient_Register(t *testing.T) {
+	c, err := New([]protocol.Version{newVersion("1.0", 0)})
+	require.NoError(t, err)
+
+	v, err := c.Get(500)
+	require.NoError(t, err)
+	require.Equal(t, "1.0", v.Version())
+
+	require.NoError(t, c.Register(newVersion("1.1", 500)))
+
+	v, err = c.Get(500)
+	require.NoError(t, err)
+	require.Equal(t, "1.1", v.Version())
+
+	v, err = c.Get(499)
+	require.NoError(t, err)
+	require.Equal(t, "1.0", v.Version())
+
+	err = c.Register(newVersion("1.2", 500))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "conflicts with existing version[1.1]")
+
+	p := mocks.GetDefaultProtocolParameters()
+	p.GenesisTime = 1000
+	p.MultihashAlgorithms = []uint{99}
+
+	invalid := mocks.GetProtocolVersion(p)
+	invalid.VersionReturns("1.2")
+
+	err = c.Register(invalid)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "protocol version[1.2] with genesis time[1000]: invalid protocol parameters: "+
+		"multihash algorithm[99] is not supported")
+
+	v, err = c.Current()
+	require.NoError(t, err)
+	require.Equal(t, "1.1", v.Version())
+}
+
+func TestClientProvider(t *testing.T) {
+	c, err := New([]protocol.Version{newVersion("1.0", 0)})
+	require.NoError(t, err)
+
+	p := NewProvider().Add(mocks.DefaultNS, c)
+
+	pc, err := p.ForNamespace(mocks.DefaultNS)
+	require.NoError(t, err)
+	require.Equal(t, c, pc)
+
+	pc, err = p.ForNamespace("did:other")
+	require.Error(t, err)
+	require.Nil(t, pc)
+	require.Contains(t, err.Error(), "protocol client not found for namespace [did:other]")
+}
+
+func newVersion(version string, genesisTime uint64) protocol.Version {
+	p := mocks.GetDefaultProtocolParameters()
+	p.GenesisTime = genesisTime
+
+	v := mocks.GetProtocolVersion(p)
+	v.VersionReturns(version)
+
+	return v
+}
+
+type mockValidator struct{}
+
+func (m *mockValidator) Validate(protocol.Protocol) error {
+	return nil
+}
@@ -0,0 +1,196 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package protocolclient
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+
+	"github.com/trustbloc/sidetree-core-go/pkg/api/protocol"
+)
+
+const (
+	extJSON = ".json"
+	extYAML = ".yaml"
+	extYML  = ".yml"
+)
+
+// ParameterSet contains protocol parameters for a given protocol version (e.g. "1.0").
+type ParameterSet struct {
+	// Version is protocol version that is used for creating protocol version implementation.
+	Version string `json:"version"`
+
+	// Protocol contains protocol parameters (including genesis time).
+	Protocol protocol.Protocol `json:"protocol"`
+}
+
+// VersionFactory creates protocol version implementation for the given version and protocol parameters.
+type VersionFactory interface {
+	Create(version string, p protocol.Protocol) (protocol.Version, error)
+}
+
+// Load creates a protocol client from parameter set files. Each path can be either a parameter set file
+// (JSON or YAML) or a directory in which case all JSON and YAML files in that directory are loaded.
+func Load(factory VersionFactory, paths []string, opts ...Option) (*Client, error) {
+	var sets []*ParameterSet
+
+	for _, path := range paths {
+		pathSets, err := ReadParameterSets(path)
+		if err != nil {
+			return nil, err
+		}
+
+		sets = append(sets, pathSets...)
+	}
+
+	return NewFromParameterSets(factory, sets, opts...)
+}
+
+// NewFromParameterSets creates a protocol client with protocol versions created from the given parameter sets.
+// Protocol parameters are validated when the created protocol version is registered.
+func NewFromParameterSets(factory VersionFactory, sets []*ParameterSet, opts ...Option) (*Client, error) {
+	if factory == nil {
+		return nil, errors.New("version factory is required")
+	}
+
+	c := newClient(opts...)
+
+	for _, set := range sets {
+		v, err := factory.Create(set.Version, set.Protocol)
+		if err != nil {
+			return nil, fmt.Errorf("create protocol version[%s] for genesis time[%d]: %w",
+				set.Version, set.Protocol.GenesisTime, err)
+		}
+
+		if err := c.Register(v); err != nil {
+			return nil, err
+		}
+	}
+
+	return c, nil
+}
+
+// ReadParameterSets reads parameter sets from the given path. If the path is a directory then all
+// JSON and YAML files in the directory are read (in lexical order).
+func ReadParameterSets(path string) ([]*ParameterSet, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("parameter set path[%s]: %w", path, err)
+	}
+
+	if !info.IsDir() {
+		set, e := ReadParameterSet(path)
+		if e != nil {
+			return nil, e
+		}
+
+		return []*ParameterSet{set}, nil
+	}
+
+	entries, err := ioutil.ReadDir(path)
+	if err != nil {
+		return nil, fmt.Errorf("read parameter set directory[%s]: %w", path, err)
+	}
+
+	var files []string
+
+	for _, entry := range entries {
+		if entry.IsDir() || !isParameterSetFile(entry.Name()) {
+			continue
+		}
+
+		files = append(files, filepath.Join(path, entry.Name()))
+	}
+
+	sort.Strings(files)
+
+	var sets []*ParameterSet
+
+	for _, file := range files {
+		set, e := ReadParameterSet(file)
+		if e != nil {
+			return nil, e
+		}
+
+		sets = append(sets, set)
+	}
+
+	return sets, nil
+}
+
+// ReadParameterSet reads a parameter set from the given JSON or YAML file.
+func ReadParameterSet(file string) (*ParameterSet, error) {
+	content, err := ioutil.ReadFile(filepath.Clean(file))
+	if err != nil {
+		return nil, fmt.Errorf("read parameter set file[%s]: %w", file, err)
+	}
+
+	set, err := ParseParameterSet(content, filepath.Ext(file))
+	if err != nil {
+		return nil, fmt.Errorf("parameter set file[%s]: %w", file, err)
+	}
+
+	return set, nil
+}
+
+// ParseParameterSet parses parameter set content. Format is determined by file extension (.json, .yaml, .yml).
+func ParseParameterSet(content []byte, ext string) (*ParameterSet, error) {
+	var err error
+
+	switch strings.ToLower(ext) {
+	case extJSON:
+	case extYAML, extYML:
+		content, err = yamlToJSON(content)
+		if err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("parameter set format '%s' not supported", ext)
+	}
+
+	set := &ParameterSet{}
+
+	err = json.Unmarshal(content, set)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal parameter set: %w", err)
+	}
+
+	if set.Version == "" {
+		return nil, errors.New("missing protocol version")
+	}
+
+	return set, nil
+}
+
+// yamlToJSON converts YAML content to JSON so that JSON tags of protocol parameters apply.
+func yamlToJSON(content []byte) ([]byte, error) {
+	var obj map[string]interface{}
+
+	err := yaml.Unmarshal(content, &obj)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal YAML parameter set: %w", err)
+	}
+
+	return json.Marshal(obj)
+}
+
+func isParameterSetFile(name string) bool {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case extJSON, extYAML, extYML:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,246 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package protocolclient
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/sidetree-core-go/pkg/api/protocol"
+	"github.com/trustbloc/sidetree-core-go/pkg/mocks"
+)
+
+const jsonParameterSet = `{
+  "version": "1.0",
+  "protocol": {
+    "genesisTime": 0,
+    "multihashAlgorithms": [18],
+    "maxOperationCount": 10,
+    "maxOperationSize": 2500,
+    "maxOperationHashLength": 100,
+    "maxDeltaSize": 1700,
+    "maxCasUriLength": 100,
+    "compressionAlgorithm": "GZIP",
+    "maxCoreIndexFileSize": 1000000,
+    "maxProofFileSize": 2500000,
+    "maxProvisionalIndexFileSize": 1000000,
+    "maxChunkFileSize": 10000000,
+    "patches": ["add-public-keys", "remove-public-keys"],
+    "signatureAlgorithms": ["EdDSA", "ES256"],
+    "keyAlgorithms": ["Ed25519", "P-256"],
+    "maxMemoryDecompressionFactor": 3
+  }
+}`
+
+const yamlParameterSet = `
+version: "1.1"
+protocol:
+  genesisTime: 500
+  multihashAlgorithms:
+    - 18
+  maxOperationCount: 100
+  maxOperationSize: 2500
+  maxOperationHashLength: 100
+  maxDeltaSize: 1700
+  maxCasUriLength: 100
+  compressionAlgorithm: GZIP
+  maxCoreIndexFileSize: 1000000
+  maxProofFileSize: 2500000
+  maxProvisionalIndexFileSize: 1000000
+  maxChunkFileSize: 10000000
+  patches:
+    - ietf-json-patch
+  signatureAlgorithms:
+    - EdDSA
+  keyAlgorithms:
+    - Ed25519
+  maxMemoryDecompressionFactor: 3
+`
+
+func TestParseParameterSet(t *testing.T) {
+	t.Run("success - JSON", func(t *testing.T) {
+		set, err := ParseParameterSet([]byte(jsonParameterSet), ".json")
+		require.NoError(t, err)
+		require.Equal(t, "1.0", set.Version)
+		require.Equal(t, uint64(0), set.Protocol.GenesisTime)
+		require.Equal(t, []uint{18}, set.Protocol.MultihashAlgorithms)
+		require.Equal(t, uint(10), set.Protocol.MaxOperationCount)
+		require.Equal(t, "GZIP", set.Protocol.CompressionAlgorithm)
+		require.Equal(t, []string{"add-public-keys", "remove-public-keys"}, set.Protocol.Patches)
+	})
+
+	t.Run("success - YAML", func(t *testing.T) {
+		set, err := ParseParameterSet([]byte(yamlParameterSet), ".yml")
+		require.NoError(t, err)
+		require.Equal(t, "1.1", set.Version)
+		require.Equal(t, uint64(500), set.Protocol.GenesisTime)
+		require.Equal(t, []uint{18}, set.Protocol.MultihashAlgorithms)
+		require.Equal(t, uint(100), set.Protocol.MaxOperationCount)
+		require.Equal(t, uint(1700), set.Protocol.MaxDeltaSize)
+		require.Equal(t, []string{"ietf-json-patch"}, set.Protocol.Patches)
+	})
+
+	t.Run("error - format not supported", func(t *testing.T) {
+		set, err := ParseParameterSet([]byte(jsonParameterSet), ".txt")
+		require.Error(t, err)
+		require.Nil(t, set)
+		require.Contains(t, err.Error(), "parameter set format '.txt' not supported")
+	})
+
+	t.Run("error - invalid JSON", func(t *testing.T) {
+		set, err := ParseParameterSet([]byte("{"), ".json")
+		require.Error(t, err)
+		require.Nil(t, set)
+		require.Contains(t, err.Error(), "failed to unmarshal parameter set")
+	})
+
+	t.Run("error - invalid YAML", func(t *testing.T) {
+		set, err := ParseParameterSet([]byte("version: [1.0"), ".yaml")
+		require.Error(t, err)
+		require.Nil(t, set)
+		require.Contains(t, err.Error(), "failed to unmarshal YAML parameter set")
+	})
+
+	t.Run("error - missing version", func(t *testing.T) {
+		set, err := ParseParameterSet([]byte(`{"protocol":{}}`), ".json")
+		require.Error(t, err)
+		require.Nil(t, set)
+		require.Contains(t, err.Error(), "missing protocol version")
+	})
+}
+
+func TestLoad(t *testing.T) {
+	dir, err := ioutil.TempDir("", "protocol")
+	require.NoError(t, err)
+
+	defer func() {
+		require.NoError(t, os.RemoveAll(dir))
+	}()
+
+	writeFile(t, dir, "v1_0.json", jsonParameterSet)
+	writeFile(t, dir, "v1_1.yaml", yamlParameterSet)
+	writeFile(t, dir, "README.md", "ignored")
+
+	t.Run("success - directory", func(t *testing.T) {
+		c, err := Load(&mockFactory{}, []string{dir})
+		require.NoError(t, err)
+
+		v, err := c.Current()
+		require.NoError(t, err)
+		require.Equal(t, "1.1", v.Version())
+		require.Equal(t, uint64(500), v.Protocol().GenesisTime)
+
+		v, err = c.Get(499)
+		require.NoError(t, err)
+		require.Equal(t, "1.0", v.Version())
+	})
+
+	t.Run("success - files", func(t *testing.T) {
+		c, err := Load(&mockFactory{}, []string{filepath.Join(dir, "v1_0.json")})
+		require.NoError(t, err)
+
+		v, err := c.Current()
+		require.NoError(t, err)
+		require.Equal(t, "1.0", v.Version())
+	})
+
+	t.Run("error - path not found", func(t *testing.T) {
+		c, err := Load(&mockFactory{}, []string{filepath.Join(dir, "invalid.json")})
+		require.Error(t, err)
+		require.Nil(t, c)
+		require.Contains(t, err.Error(), "parameter set path")
+	})
+
+	t.Run("error - invalid file in directory", func(t *testing.T) {
+		invalidDir, err := ioutil.TempDir("", "protocol")
+		require.NoError(t, err)
+
+		defer func() {
+			require.NoError(t, os.RemoveAll(invalidDir))
+		}()
+
+		writeFile(t, invalidDir, "invalid.json", "{")
+
+		c, err := Load(&mockFactory{}, []string{invalidDir})
+		require.Error(t, err)
+		require.Nil(t, c)
+		require.Contains(t, err.Error(), "invalid.json")
+	})
+
+	t.Run("error - factory error", func(t *testing.T) {
+		c, err := Load(&mockFactory{err: errors.New("injected factory error")}, []string{dir})
+		require.Error(t, err)
+		require.Nil(t, c)
+		require.Contains(t, err.Error(), "create protocol version[1.0] for genesis time[0]: injected factory error")
+	})
+
+	t.Run("error - duplicate genesis time", func(t *testing.T) {
+		c, err := Load(&mockFactory{}, []string{dir, filepath.Join(dir, "v1_0.json")})
+		require.Error(t, err)
+		require.Nil(t, c)
+		require.Contains(t, err.Error(), "conflicts with existing version")
+	})
+
+	t.Run("error - missing factory", func(t *testing.T) {
+		c, err := Load(nil, []string{dir})
+		require.Error(t, err)
+		require.Nil(t, c)
+		require.Contains(t, err.Error(), "version factory is required")
+	})
+
+	t.Run("error - invalid protocol parameters", func(t *testing.T) {
+		invalidDir, err := ioutil.TempDir("", "protocol")
+		require.NoError(t, err)
+
+		defer func() {
+			require.NoError(t, os.RemoveAll(invalidDir))
+		}()
+
+		writeFile(t, invalidDir, "v1_0.json", strings.Replace(jsonParameterSet, `"GZIP"`, `"LZ4"`, 1))
+
+		factory := &mockFactory{}
+
+		c, err := Load(factory, []string{invalidDir})
+		require.Error(t, err)
+		require.Nil(t, c)
+		require.Contains(t, err.Error(),
+			"protocol version[1.0] with genesis time[0]: invalid protocol parameters: compression algorithm 'LZ4' is not supported")
+
+		// the parameters are validated (once) when the created version is registered
+		require.Equal(t, 1, factory.createCount)
+	})
+}
+
+func writeFile(t *testing.T, dir, name, content string) {
+	t.Helper()
+
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0o600))
+}
+
+type mockFactory struct {
+	err         error
+	createCount int
+}
+
+func (m *mockFactory) Create(version string, p protocol.Protocol) (protocol.Version, error) {
+	m.createCount++
+
+	if m.err != nil {
+		return nil, m.err
+	}
+
+	v := mocks.GetProtocolVersion(p)
+	v.VersionReturns(version)
+
+	return v, nil
+}
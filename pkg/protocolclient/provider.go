@@ -0,0 +1,51 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package protocolclient
+
+import (
+	"sync"
+
+	"github.com/pkg/errors"
+
+	"github.com/trustbloc/sidetree-core-go/pkg/api/protocol"
+)
+
+// ClientProvider implements protocol client provider. It holds a protocol client per namespace.
+type ClientProvider struct {
+	clients map[string]protocol.Client
+	mutex   sync.RWMutex
+}
+
+// NewProvider returns a new protocol client provider.
+func NewProvider() *ClientProvider {
+	return &ClientProvider{
+		clients: make(map[string]protocol.Client),
+	}
+}
+
+// Add sets the protocol client for the given namespace.
+func (p *ClientProvider) Add(namespace string, pc protocol.Client) *ClientProvider {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	p.clients[namespace] = pc
+
+	return p
+}
+
+// ForNamespace returns the protocol client for the given namespace.
+func (p *ClientProvider) ForNamespace(namespace string) (protocol.Client, error) {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+
+	pc, ok := p.clients[namespace]
+	if !ok {
+		return nil, errors.Errorf("protocol client not found for namespace [%s]", namespace)
+	}
+
+	return pc, nil
+}
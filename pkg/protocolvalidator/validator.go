@@ -0,0 +1,207 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package protocolvalidator validates that protocol parameters are consistent and that they only
+// reference algorithms and patches that are supported by this implementation.
+package protocolvalidator
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/trustbloc/sidetree-core-go/pkg/api/protocol"
+	"github.com/trustbloc/sidetree-core-go/pkg/compression"
+	"github.com/trustbloc/sidetree-core-go/pkg/hashing"
+	"github.com/trustbloc/sidetree-core-go/pkg/patch"
+)
+
+// defaultMaxProofSize is the default maximum size of operation proof (signed data) in bytes.
+const defaultMaxProofSize = 600
+
+var (
+	defaultPatches = []string{
+		string(patch.Replace),
+		string(patch.AddPublicKeys),
+		string(patch.RemovePublicKeys),
+		string(patch.AddServiceEndpoints),
+		string(patch.RemoveServiceEndpoints),
+		string(patch.JSONPatch),
+	}
+
+	defaultSignatureAlgorithms = []string{"EdDSA", "ES256", "ES384", "ES512", "ES256K"}
+
+	defaultKeyAlgorithms = []string{"Ed25519", "P-256", "P-384", "P-521", "secp256k1"}
+)
+
+// Error contains all protocol parameter violations.
+type Error struct {
+	Violations []string
+}
+
+// Error returns the error string.
+func (e *Error) Error() string {
+	return fmt.Sprintf("invalid protocol parameters: %s", strings.Join(e.Violations, "; "))
+}
+
+type compressionRegistry interface {
+	IsSupported(alg string) bool
+}
+
+// Option is a validator option.
+type Option func(opts *Validator)
+
+// WithCompressionRegistry sets the registry used for checking compression algorithm (default algorithms by default).
+func WithCompressionRegistry(r compressionRegistry) Option {
+	return func(opts *Validator) {
+		opts.compression = r
+	}
+}
+
+// WithMaxProofSize sets maximum operation proof size that has to fit into operation together with delta.
+func WithMaxProofSize(size uint) Option {
+	return func(opts *Validator) {
+		opts.maxProofSize = size
+	}
+}
+
+// WithPatches sets the list of known patches.
+func WithPatches(patches ...string) Option {
+	return func(opts *Validator) {
+		opts.patches = patches
+	}
+}
+
+// WithSignatureAlgorithms sets the list of known signature algorithms.
+func WithSignatureAlgorithms(algs ...string) Option {
+	return func(opts *Validator) {
+		opts.signatureAlgorithms = algs
+	}
+}
+
+// WithKeyAlgorithms sets the list of known key algorithms.
+func WithKeyAlgorithms(algs ...string) Option {
+	return func(opts *Validator) {
+		opts.keyAlgorithms = algs
+	}
+}
+
+// Validator validates protocol parameters.
+type Validator struct {
+	compression         compressionRegistry
+	maxProofSize        uint
+	patches             []string
+	signatureAlgorithms []string
+	keyAlgorithms       []string
+}
+
+// New returns a new protocol parameters validator.
+func New(opts ...Option) *Validator {
+	v := &Validator{
+		compression:         compression.New(compression.WithDefaultAlgorithms()),
+		maxProofSize:        defaultMaxProofSize,
+		patches:             defaultPatches,
+		signatureAlgorithms: defaultSignatureAlgorithms,
+		keyAlgorithms:       defaultKeyAlgorithms,
+	}
+
+	// apply options
+	for _, opt := range opts {
+		opt(v)
+	}
+
+	return v
+}
+
+// Validate validates protocol parameters. All violations are returned in a single error of type *Error.
+func (v *Validator) Validate(p protocol.Protocol) error {
+	var violations []string
+
+	addViolation := func(format string, args ...interface{}) {
+		violations = append(violations, fmt.Sprintf(format, args...))
+	}
+
+	v.validateMultihashAlgorithms(p, addViolation)
+	v.validateSizes(p, addViolation)
+
+	if !v.compression.IsSupported(p.CompressionAlgorithm) {
+		addViolation("compression algorithm '%s' is not supported", p.CompressionAlgorithm)
+	}
+
+	validateKnownValues("patch", p.Patches, v.patches, addViolation)
+	validateKnownValues("signature algorithm", p.SignatureAlgorithms, v.signatureAlgorithms, addViolation)
+	validateKnownValues("key algorithm", p.KeyAlgorithms, v.keyAlgorithms, addViolation)
+
+	if len(violations) > 0 {
+		return &Error{Violations: violations}
+	}
+
+	return nil
+}
+
+type violationFunc func(format string, args ...interface{})
+
+func (v *Validator) validateMultihashAlgorithms(p protocol.Protocol, addViolation violationFunc) {
+	if len(p.MultihashAlgorithms) == 0 {
+		addViolation("at least one multihash algorithm is required")
+	}
+
+	for _, code := range p.MultihashAlgorithms {
+		if _, err := hashing.GetHashFromMultihash(code); err != nil {
+			addViolation("multihash algorithm[%d] is not supported", code)
+		}
+	}
+}
+
+func (v *Validator) validateSizes(p protocol.Protocol, addViolation violationFunc) {
+	required := []struct {
+		name  string
+		value uint
+	}{
+		{"maxOperationCount", p.MaxOperationCount},
+		{"maxOperationSize", p.MaxOperationSize},
+		{"maxOperationHashLength", p.MaxOperationHashLength},
+		{"maxDeltaSize", p.MaxDeltaSize},
+		{"maxCasUriLength", p.MaxCasURILength},
+		{"maxCoreIndexFileSize", p.MaxCoreIndexFileSize},
+		{"maxProofFileSize", p.MaxProofFileSize},
+		{"maxProvisionalIndexFileSize", p.MaxProvisionalIndexFileSize},
+		{"maxChunkFileSize", p.MaxChunkFileSize},
+		{"maxMemoryDecompressionFactor", p.MaxMemoryDecompressionFactor},
+	}
+
+	for _, r := range required {
+		if r.value == 0 {
+			addViolation("%s must be greater than zero", r.name)
+		}
+	}
+
+	if p.MaxOperationSize <= p.MaxDeltaSize+v.maxProofSize {
+		addViolation("maxOperationSize[%d] must be greater than maxDeltaSize[%d] plus maximum proof size[%d]",
+			p.MaxOperationSize, p.MaxDeltaSize, v.maxProofSize)
+	}
+}
+
+func validateKnownValues(alias string, values, known []string, addViolation violationFunc) {
+	if len(values) == 0 {
+		addViolation("at least one %s is required", alias)
+	}
+
+	for _, val := range values {
+		if !contains(known, val) {
+			addViolation("%s '%s' is not supported", alias, val)
+		}
+	}
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
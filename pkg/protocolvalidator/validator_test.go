@@ -0,0 +1,91 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package protocolvalidator
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/sidetree-core-go/pkg/api/protocol"
+	"github.com/trustbloc/sidetree-core-go/pkg/compression"
+	"github.com/trustbloc/sidetree-core-go/pkg/mocks"
+)
+
+func TestValidator_Validate(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		require.NoError(t, New().Validate(mocks.GetDefaultProtocolParameters()))
+	})
+
+	t.Run("success - custom known values", func(t *testing.T) {
+		p := mocks.GetDefaultProtocolParameters()
+		p.Patches = []string{"custom-patch"}
+		p.SignatureAlgorithms = []string{"custom-sig"}
+		p.KeyAlgorithms = []string{"custom-key"}
+
+		v := New(
+			WithPatches("custom-patch"),
+			WithSignatureAlgorithms("custom-sig"),
+			WithKeyAlgorithms("custom-key"),
+			WithCompressionRegistry(compression.New(compression.WithDefaultAlgorithms())),
+		)
+
+		require.NoError(t, v.Validate(p))
+	})
+
+	t.Run("error - all violations are returned", func(t *testing.T) {
+		p := mocks.GetDefaultProtocolParameters()
+		p.MultihashAlgorithms = []uint{18, 99}
+		p.CompressionAlgorithm = "LZ4"
+		p.Patches = []string{"add-public-keys", "invalid-patch"}
+		p.SignatureAlgorithms = []string{"ES256", "RS256"}
+		p.KeyAlgorithms = []string{"P-256", "RSA"}
+
+		err := New().Validate(p)
+		require.Error(t, err)
+
+		validationErr := &Error{}
+		require.True(t, errors.As(err, &validationErr))
+		require.Len(t, validationErr.Violations, 5)
+
+		require.Contains(t, err.Error(), "invalid protocol parameters")
+		require.Contains(t, err.Error(), "multihash algorithm[99] is not supported")
+		require.Contains(t, err.Error(), "compression algorithm 'LZ4' is not supported")
+		require.Contains(t, err.Error(), "patch 'invalid-patch' is not supported")
+		require.Contains(t, err.Error(), "signature algorithm 'RS256' is not supported")
+		require.Contains(t, err.Error(), "key algorithm 'RSA' is not supported")
+	})
+
+	t.Run("error - operation size", func(t *testing.T) {
+		p := mocks.GetDefaultProtocolParameters()
+		p.MaxOperationSize = p.MaxDeltaSize + 100
+
+		err := New().Validate(p)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "maxOperationSize[1100] must be greater than maxDeltaSize[1000] plus maximum proof size[600]")
+
+		require.NoError(t, New(WithMaxProofSize(50)).Validate(p))
+	})
+
+	t.Run("error - empty parameters", func(t *testing.T) {
+		err := New().Validate(protocol.Protocol{})
+		require.Error(t, err)
+
+		validationErr := &Error{}
+		require.True(t, errors.As(err, &validationErr))
+
+		require.Contains(t, validationErr.Violations, "at least one multihash algorithm is required")
+		require.Contains(t, validationErr.Violations, "maxOperationCount must be greater than zero")
+		require.Contains(t, validationErr.Violations, "maxChunkFileSize must be greater than zero")
+		require.Contains(t, validationErr.Violations, "maxMemoryDecompressionFactor must be greater than zero")
+		require.Contains(t, validationErr.Violations, "compression algorithm '' is not supported")
+		require.Contains(t, validationErr.Violations, "at least one patch is required")
+		require.Contains(t, validationErr.Violations, "at least one signature algorithm is required")
+		require.Contains(t, validationErr.Violations, "at least one key algorithm is required")
+	})
+}
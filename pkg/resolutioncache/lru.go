@@ -0,0 +1,100 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package resolutioncache
+
+import (
+	"container/list"
+	"time"
+)
+
+type lruEntry struct {
+	key     string
+	value   interface{}
+	expires time.Time
+}
+
+// lru is a least-recently-used cache with a maximum number of entries and an optional time-to-live.
+// The LRU is not thread-safe.
+type lru struct {
+	maxEntries int
+	ttl        time.Duration
+	items      map[string]*list.Element
+	order      *list.List
+	now        func() time.Time
+}
+
+func newLRU(maxEntries int, ttl time.Duration) *lru {
+	return &lru{
+		maxEntries: maxEntries,
+		ttl:        ttl,
+		items:      make(map[string]*list.Element),
+		order:      list.New(),
+		now:        time.Now,
+	}
+}
+
+// get returns the value for the given key. Expired entries are removed.
+func (c *lru) get(key string) (interface{}, bool) {
+	e, ok := c.items[key]
+	if !ok {
+		return nil, false
+	}
+
+	entry := e.Value.(*lruEntry)
+
+	if c.ttl > 0 && !c.now().Before(entry.expires) {
+		c.removeElement(e)
+
+		return nil, false
+	}
+
+	c.order.MoveToFront(e)
+
+	return entry.value, true
+}
+
+// add adds (or replaces) the value for the given key and evicts the least recently used entries
+// if the maximum number of entries is exceeded.
+func (c *lru) add(key string, value interface{}) {
+	if c.maxEntries <= 0 {
+		return
+	}
+
+	entry := &lruEntry{key: key, value: value}
+
+	if c.ttl > 0 {
+		entry.expires = c.now().Add(c.ttl)
+	}
+
+	if e, ok := c.items[key]; ok {
+		e.Value = entry
+		c.order.MoveToFront(e)
+
+		return
+	}
+
+	c.items[key] = c.order.PushFront(entry)
+
+	for c.order.Len() > c.maxEntries {
+		c.removeElement(c.order.Back())
+	}
+}
+
+func (c *lru) remove(key string) {
+	if e, ok := c.items[key]; ok {
+		c.removeElement(e)
+	}
+}
+
+func (c *lru) len() int {
+	return c.order.Len()
+}
+
+func (c *lru) removeElement(e *list.Element) {
+	c.order.Remove(e)
+	delete(c.items, e.Value.(*lruEntry).key)
+}
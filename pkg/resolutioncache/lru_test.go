@@ -0,0 +1,91 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package resolutioncache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLRU(t *testing.T) {
+	c := newLRU(2, 0)
+
+	c.add("a", 1)
+	c.add("b", 2)
+	require.Equal(t, 2, c.len())
+
+	// 'a' becomes the most recently used entry
+	value, ok := c.get("a")
+	require.True(t, ok)
+	require.Equal(t, 1, value)
+
+	// adding 'c' evicts the least recently used entry ('b')
+	c.add("c", 3)
+	require.Equal(t, 2, c.len())
+
+	_, ok = c.get("b")
+	require.False(t, ok)
+
+	// replacing an entry
+	c.add("c", 4)
+	require.Equal(t, 2, c.len())
+
+	value, ok = c.get("c")
+	require.True(t, ok)
+	require.Equal(t, 4, value)
+
+	c.remove("a")
+	c.remove("x")
+	require.Equal(t, 1, c.len())
+
+	_, ok = c.get("a")
+	require.False(t, ok)
+
+	t.Run("disabled", func(t *testing.T) {
+		c := newLRU(0, 0)
+
+		c.add("a", 1)
+		require.Equal(t, 0, c.len())
+	})
+}
+
+func TestLRU_TTL(t *testing.T) {
+	now := time.Now()
+
+	c := newLRU(10, time.Minute)
+	c.now = func() time.Time { return now }
+
+	c.add("a", 1)
+
+	now = now.Add(30 * time.Second)
+
+	c.add("b", 2)
+
+	_, ok := c.get("a")
+	require.True(t, ok)
+
+	// 'a' expired
+	now = now.Add(30 * time.Second)
+
+	_, ok = c.get("a")
+	require.False(t, ok)
+	require.Equal(t, 1, c.len())
+
+	_, ok = c.get("b")
+	require.True(t, ok)
+
+	// replacing an entry resets its expiry
+	c.add("b", 3)
+
+	now = now.Add(45 * time.Second)
+
+	value, ok := c.get("b")
+	require.True(t, ok)
+	require.Equal(t, 3, value)
+}
@@ -0,0 +1,133 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package resolutioncache
+
+import (
+	"sync"
+
+	"github.com/trustbloc/edge-core/pkg/log"
+
+	"github.com/trustbloc/sidetree-core-go/pkg/api/operation"
+	"github.com/trustbloc/sidetree-core-go/pkg/api/protocol"
+	"github.com/trustbloc/sidetree-core-go/pkg/document"
+)
+
+var logger = log.New("sidetree-core-resolutioncache")
+
+// OperationProcessor resolves the document for a unique suffix.
+type OperationProcessor interface {
+	Resolve(uniqueSuffix string, opts ...document.ResolutionOption) (*protocol.ResolutionModel, error)
+}
+
+// pendingResolution tracks the resolutions of a unique suffix that are in progress so that a result
+// is not cached if the suffix was invalidated while it was being resolved.
+type pendingResolution struct {
+	refs        int
+	invalidated bool
+}
+
+// ProcessorCache is an operation processor that caches the resolution models of published documents keyed
+// by unique suffix. An entry is invalidated when new operations are stored for its unique suffix, so the
+// cache should be registered as an operations stored listener with the transaction processor.
+// Cached resolution models are shared between callers and must not be modified.
+type ProcessorCache struct {
+	processor OperationProcessor
+	mutex     sync.Mutex
+	lru       *lru
+	pending   map[string]*pendingResolution
+}
+
+// NewProcessorCache returns a new cache in front of the given operation processor which holds at most
+// maxEntries resolution models.
+func NewProcessorCache(processor OperationProcessor, maxEntries int) *ProcessorCache {
+	return &ProcessorCache{
+		processor: processor,
+		lru:       newLRU(maxEntries, 0),
+		pending:   make(map[string]*pendingResolution),
+	}
+}
+
+// Resolve returns the cached resolution model for the given unique suffix or resolves (and caches) it
+// using the underlying operation processor. Errors are not cached. Resolutions with options (e.g. of a
+// specific version of the document) bypass the cache.
+func (c *ProcessorCache) Resolve(uniqueSuffix string, opts ...document.ResolutionOption) (*protocol.ResolutionModel, error) {
+	if len(opts) > 0 {
+		return c.processor.Resolve(uniqueSuffix, opts...)
+	}
+
+	c.mutex.Lock()
+
+	if rm, ok := c.lru.get(uniqueSuffix); ok {
+		c.mutex.Unlock()
+
+		logger.Debugf("resolution cache hit for suffix[%s]", uniqueSuffix)
+
+		return rm.(*protocol.ResolutionModel), nil
+	}
+
+	p, ok := c.pending[uniqueSuffix]
+	if !ok {
+		p = &pendingResolution{}
+		c.pending[uniqueSuffix] = p
+	}
+
+	p.refs++
+
+	c.mutex.Unlock()
+
+	rm, err := c.processor.Resolve(uniqueSuffix)
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	p.refs--
+
+	if p.refs == 0 && c.pending[uniqueSuffix] == p {
+		delete(c.pending, uniqueSuffix)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if !p.invalidated {
+		c.lru.add(uniqueSuffix, rm)
+	}
+
+	return rm, nil
+}
+
+// Invalidate removes the cached resolution models for the given unique suffixes. Resolutions of those
+// suffixes that are in progress are not cached.
+func (c *ProcessorCache) Invalidate(uniqueSuffixes ...string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	for _, suffix := range uniqueSuffixes {
+		c.lru.remove(suffix)
+
+		if p, ok := c.pending[suffix]; ok {
+			p.invalidated = true
+
+			// subsequent resolutions will see the new operations
+			delete(c.pending, suffix)
+		}
+	}
+}
+
+// OperationsStored invalidates the cached resolution models for the unique suffixes of the stored operations.
+func (c *ProcessorCache) OperationsStored(ops []*operation.AnchoredOperation) {
+	suffixes := make([]string, len(ops))
+
+	for i, op := range ops {
+		suffixes[i] = op.UniqueSuffix
+	}
+
+	logger.Debugf("invalidating cached resolution results for suffixes: %s", suffixes)
+
+	c.Invalidate(suffixes...)
+}
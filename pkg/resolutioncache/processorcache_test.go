@@ -0,0 +1,176 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package resolutioncache
+
+import (
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/sidetree-core-go/pkg/api/operation"
+	"github.com/trustbloc/sidetree-core-go/pkg/api/protocol"
+	"github.com/trustbloc/sidetree-core-go/pkg/document"
+)
+
+func TestProcessorCache_Resolve(t *testing.T) {
+	p := newMockProcessor()
+
+	c := NewProcessorCache(p, 10)
+
+	for i := 0; i < 3; i++ {
+		rm, err := c.Resolve("abc")
+		require.NoError(t, err)
+		require.Equal(t, "abc", rm.Doc.ID())
+	}
+
+	require.Equal(t, 1, p.resolutions("abc"))
+
+	t.Run("errors are not cached", func(t *testing.T) {
+		p := newMockProcessor()
+		p.err = operation.ErrNotFound
+
+		c := NewProcessorCache(p, 10)
+
+		for i := 0; i < 2; i++ {
+			rm, err := c.Resolve("abc")
+			require.True(t, errors.Is(err, operation.ErrNotFound))
+			require.Nil(t, rm)
+		}
+
+		require.Equal(t, 2, p.resolutions("abc"))
+	})
+}
+
+func TestProcessorCache_ResolveWithOptions(t *testing.T) {
+	p := newMockProcessor()
+
+	c := NewProcessorCache(p, 10)
+
+	rm, err := c.Resolve("abc")
+	require.NoError(t, err)
+	require.NotContains(t, rm.Doc, "versionId")
+
+	for i := 0; i < 2; i++ {
+		rm, err = c.Resolve("abc", document.WithVersionID(1))
+		require.NoError(t, err)
+		require.Equal(t, uint64(1), rm.Doc["versionId"])
+	}
+
+	require.Equal(t, 3, p.resolutions("abc"))
+
+	// the latest version is still cached
+	rm, err = c.Resolve("abc")
+	require.NoError(t, err)
+	require.NotContains(t, rm.Doc, "versionId")
+	require.Equal(t, 3, p.resolutions("abc"))
+}
+
+func TestProcessorCache_OperationsStored(t *testing.T) {
+	p := newMockProcessor()
+
+	c := NewProcessorCache(p, 10)
+
+	for _, suffix := range []string{"abc", "def", "ghi"} {
+		_, err := c.Resolve(suffix)
+		require.NoError(t, err)
+	}
+
+	c.OperationsStored([]*operation.AnchoredOperation{{UniqueSuffix: "abc"}, {UniqueSuffix: "ghi"}})
+
+	for _, suffix := range []string{"abc", "def", "ghi"} {
+		_, err := c.Resolve(suffix)
+		require.NoError(t, err)
+	}
+
+	// only the entries for the suffixes of the stored operations were invalidated
+	require.Equal(t, 2, p.resolutions("abc"))
+	require.Equal(t, 1, p.resolutions("def"))
+	require.Equal(t, 2, p.resolutions("ghi"))
+}
+
+func TestProcessorCache_InvalidateDuringResolve(t *testing.T) {
+	p := newMockProcessor()
+	p.started = make(chan struct{})
+	p.proceed = make(chan struct{})
+
+	c := NewProcessorCache(p, 10)
+
+	var wg sync.WaitGroup
+
+	wg.Add(1)
+
+	go func() {
+		defer wg.Done()
+
+		_, err := c.Resolve("abc")
+		require.NoError(t, err)
+	}()
+
+	<-p.started
+
+	// new operations were stored while the document was being resolved
+	c.Invalidate("abc")
+
+	close(p.proceed)
+	wg.Wait()
+
+	p.started = nil
+	p.proceed = nil
+
+	// the (possibly stale) result was not cached
+	_, err := c.Resolve("abc")
+	require.NoError(t, err)
+	require.Equal(t, 2, p.resolutions("abc"))
+
+	_, err = c.Resolve("abc")
+	require.NoError(t, err)
+	require.Equal(t, 2, p.resolutions("abc"))
+}
+
+type mockProcessor struct {
+	err     error
+	started chan struct{}
+	proceed chan struct{}
+	mutex   sync.Mutex
+	counts  map[string]int
+}
+
+func newMockProcessor() *mockProcessor {
+	return &mockProcessor{counts: make(map[string]int)}
+}
+
+func (m *mockProcessor) Resolve(uniqueSuffix string, opts ...document.ResolutionOption) (*protocol.ResolutionModel, error) {
+	m.mutex.Lock()
+	m.counts[uniqueSuffix]++
+	m.mutex.Unlock()
+
+	if m.started != nil {
+		m.started <- struct{}{}
+		<-m.proceed
+	}
+
+	if m.err != nil {
+		return nil, m.err
+	}
+
+	doc := document.Document{"id": uniqueSuffix}
+
+	if versionID := document.GetResolutionOptions(opts...).VersionID; versionID > 0 {
+		doc["versionId"] = versionID
+	}
+
+	return &protocol.ResolutionModel{Doc: doc}, nil
+}
+
+func (m *mockProcessor) resolutions(uniqueSuffix string) int {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	return m.counts[uniqueSuffix]
+}
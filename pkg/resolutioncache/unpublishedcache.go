@@ -0,0 +1,51 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package resolutioncache
+
+import (
+	"sync"
+	"time"
+
+	"github.com/trustbloc/sidetree-core-go/pkg/document"
+)
+
+// UnpublishedCache caches the resolution results of unpublished (long-form) DIDs. Since an unpublished
+// DID may be published at any time, entries expire after a time-to-live.
+// Cached resolution results are shared between callers and must not be modified.
+type UnpublishedCache struct {
+	mutex sync.Mutex
+	lru   *lru
+}
+
+// NewUnpublishedCache returns a new cache which holds at most maxEntries resolution results for the given
+// time-to-live.
+func NewUnpublishedCache(maxEntries int, ttl time.Duration) *UnpublishedCache {
+	return &UnpublishedCache{
+		lru: newLRU(maxEntries, ttl),
+	}
+}
+
+// Get returns the cached resolution result for the given long-form DID.
+func (c *UnpublishedCache) Get(longFormDID string) (*document.ResolutionResult, bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	result, ok := c.lru.get(longFormDID)
+	if !ok {
+		return nil, false
+	}
+
+	return result.(*document.ResolutionResult), true
+}
+
+// Put caches the resolution result for the given long-form DID.
+func (c *UnpublishedCache) Put(longFormDID string, result *document.ResolutionResult) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.lru.add(longFormDID, result)
+}
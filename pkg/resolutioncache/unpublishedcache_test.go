@@ -0,0 +1,44 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package resolutioncache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/sidetree-core-go/pkg/document"
+)
+
+func TestUnpublishedCache(t *testing.T) {
+	const longFormDID = "did:sidetree:abc:xyz"
+
+	now := time.Now()
+
+	c := NewUnpublishedCache(10, time.Minute)
+	c.lru.now = func() time.Time { return now }
+
+	result, ok := c.Get(longFormDID)
+	require.False(t, ok)
+	require.Nil(t, result)
+
+	expected := &document.ResolutionResult{Document: document.Document{"id": longFormDID}}
+
+	c.Put(longFormDID, expected)
+
+	result, ok = c.Get(longFormDID)
+	require.True(t, ok)
+	require.Same(t, expected, result)
+
+	// the result expires after the time-to-live
+	now = now.Add(time.Minute)
+
+	result, ok = c.Get(longFormDID)
+	require.False(t, ok)
+	require.Nil(t, result)
+}
@@ -9,11 +9,18 @@ package common
 import (
 	"encoding/json"
 	"net/http"
+
+	"github.com/fxamacker/cbor/v2"
 )
 
 // WriteResponse writes a response to the response writer.
 func WriteResponse(rw http.ResponseWriter, status int, v interface{}) {
-	rw.Header().Set("Content-Type", "application/did+ld+json")
+	WriteJSONResponse(rw, status, DIDLDJSONMediaType, v)
+}
+
+// WriteJSONResponse writes a JSON response with the given content type to the response writer.
+func WriteJSONResponse(rw http.ResponseWriter, status int, contentType string, v interface{}) {
+	rw.Header().Set("Content-Type", contentType)
 	rw.WriteHeader(status)
 	err := json.NewEncoder(rw).Encode(v)
 	if err != nil {
@@ -21,6 +28,25 @@ func WriteResponse(rw http.ResponseWriter, status int, v interface{}) {
 	}
 }
 
+// WriteCBORResponse writes a CBOR response with the given content type to the response writer.
+func WriteCBORResponse(rw http.ResponseWriter, status int, contentType string, v interface{}) {
+	data, err := cbor.Marshal(v)
+	if err != nil {
+		logger.Errorf("Unable to marshal CBOR response: %s", err)
+
+		WriteError(rw, http.StatusInternalServerError, err)
+
+		return
+	}
+
+	rw.Header().Set("Content-Type", contentType)
+	rw.WriteHeader(status)
+
+	if _, err := rw.Write(data); err != nil {
+		logger.Errorf("Unable to write response: %s", err)
+	}
+}
+
 // WriteError writes an error to the response writer.
 func WriteError(rw http.ResponseWriter, status int, err error) {
 	if status >= http.StatusInternalServerError {
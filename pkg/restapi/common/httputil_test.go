@@ -12,6 +12,7 @@ import (
 	"net/http/httptest"
 	"testing"
 
+	"github.com/fxamacker/cbor/v2"
 	"github.com/stretchr/testify/require"
 )
 
@@ -23,6 +24,34 @@ func TestWriteResponse(t *testing.T) {
 	require.Equal(t, "application/did+ld+json", rw.Header().Get("content-type"))
 }
 
+func TestWriteJSONResponse(t *testing.T) {
+	rw := httptest.NewRecorder()
+	WriteJSONResponse(rw, http.StatusOK, DIDJSONMediaType, map[string]interface{}{"id": "did:sidetree:abc"})
+	require.Equal(t, http.StatusOK, rw.Code)
+	require.Equal(t, "{\"id\":\"did:sidetree:abc\"}\n", rw.Body.String())
+	require.Equal(t, DIDJSONMediaType, rw.Header().Get("content-type"))
+}
+
+func TestWriteCBORResponse(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		rw := httptest.NewRecorder()
+		WriteCBORResponse(rw, http.StatusOK, DIDCBORMediaType, map[string]interface{}{"id": "did:sidetree:abc"})
+		require.Equal(t, http.StatusOK, rw.Code)
+		require.Equal(t, DIDCBORMediaType, rw.Header().Get("content-type"))
+
+		var doc map[string]interface{}
+		require.NoError(t, cbor.Unmarshal(rw.Body.Bytes(), &doc))
+		require.Equal(t, "did:sidetree:abc", doc["id"])
+	})
+
+	t.Run("marshal error", func(t *testing.T) {
+		rw := httptest.NewRecorder()
+		WriteCBORResponse(rw, http.StatusOK, DIDCBORMediaType, make(chan int))
+		require.Equal(t, http.StatusInternalServerError, rw.Code)
+		require.Equal(t, "text/plain", rw.Header().Get("content-type"))
+	})
+}
+
 func TestWriteError(t *testing.T) {
 	errExpected := errors.New("some error")
 
@@ -0,0 +1,118 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package common
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	// DIDJSONMediaType is the media type of the JSON representation of a DID document (without '@context').
+	DIDJSONMediaType = "application/did+json"
+
+	// DIDLDJSONMediaType is the media type of the JSON-LD representation of a DID document.
+	DIDLDJSONMediaType = "application/did+ld+json"
+
+	// DIDCBORMediaType is the media type of the CBOR representation of a DID document.
+	DIDCBORMediaType = "application/did+cbor"
+
+	// DIDResolutionMediaType is the media type of a DID resolution result.
+	DIDResolutionMediaType = `application/ld+json;profile="https://w3id.org/did-resolution"`
+
+	// DIDResolutionProfile is the profile parameter of the DID resolution result media type.
+	DIDResolutionProfile = "https://w3id.org/did-resolution"
+
+	// JSONLDMediaType is the JSON-LD media type.
+	JSONLDMediaType = "application/ld+json"
+
+	// JSONMediaType is the JSON media type.
+	JSONMediaType = "application/json"
+)
+
+// MediaRange is a media range of an Accept header.
+type MediaRange struct {
+	// MediaType is the lower case media type (without parameters), e.g. 'application/did+json' or '*/*'.
+	MediaType string
+
+	// Params contains the media type parameters (except for the quality parameter 'q').
+	Params map[string]string
+
+	// Quality is the value of the quality parameter 'q' (1 if not specified).
+	Quality float64
+}
+
+// HasProfile returns true if the 'profile' parameter (a space-separated list of URIs) contains the given profile.
+func (r *MediaRange) HasProfile(profile string) bool {
+	for _, p := range strings.Fields(r.Params["profile"]) {
+		if p == profile {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ParseAccept parses the given Accept header and returns the accepted media ranges ordered by quality
+// (media ranges with the same quality keep the order of the header). Media ranges with quality 0
+// (i.e. not acceptable) are not returned.
+func ParseAccept(accept string) []*MediaRange {
+	var ranges []*MediaRange
+
+	for _, part := range strings.Split(accept, ",") {
+		r := parseMediaRange(part)
+		if r.MediaType == "" || r.Quality <= 0 {
+			continue
+		}
+
+		ranges = append(ranges, r)
+	}
+
+	sort.SliceStable(ranges, func(i, j int) bool {
+		return ranges[i].Quality > ranges[j].Quality
+	})
+
+	return ranges
+}
+
+func parseMediaRange(value string) *MediaRange {
+	parts := strings.Split(value, ";")
+
+	r := &MediaRange{
+		MediaType: strings.ToLower(strings.TrimSpace(parts[0])),
+		Params:    make(map[string]string),
+		Quality:   1,
+	}
+
+	for _, param := range parts[1:] {
+		pos := strings.Index(param, "=")
+		if pos < 0 {
+			continue
+		}
+
+		name := strings.ToLower(strings.TrimSpace(param[:pos]))
+		paramValue := strings.Trim(strings.TrimSpace(param[pos+1:]), `"`)
+
+		if name != "q" {
+			r.Params[name] = paramValue
+
+			continue
+		}
+
+		quality, err := strconv.ParseFloat(paramValue, 64)
+		if err != nil {
+			logger.Debugf("Ignoring invalid quality [%s] of media range [%s]", paramValue, value)
+
+			continue
+		}
+
+		r.Quality = quality
+	}
+
+	return r
+}
@@ -0,0 +1,50 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package common
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseAccept(t *testing.T) {
+	t.Run("ordered by quality", func(t *testing.T) {
+		ranges := ParseAccept(`application/did+json;q=0.5, Application/DID+LD+JSON, */*;q=0.1, application/did+cbor;q=0`)
+		require.Len(t, ranges, 3)
+		require.Equal(t, DIDLDJSONMediaType, ranges[0].MediaType)
+		require.Equal(t, float64(1), ranges[0].Quality)
+		require.Equal(t, DIDJSONMediaType, ranges[1].MediaType)
+		require.Equal(t, 0.5, ranges[1].Quality)
+		require.Equal(t, "*/*", ranges[2].MediaType)
+	})
+
+	t.Run("profile", func(t *testing.T) {
+		ranges := ParseAccept(`application/ld+json; profile="https://w3id.org/did-resolution https://example.com/profile"`)
+		require.Len(t, ranges, 1)
+		require.Equal(t, JSONLDMediaType, ranges[0].MediaType)
+		require.True(t, ranges[0].HasProfile(DIDResolutionProfile))
+		require.True(t, ranges[0].HasProfile("https://example.com/profile"))
+		require.False(t, ranges[0].HasProfile("https://example.com/other"))
+
+		ranges = ParseAccept(DIDResolutionMediaType)
+		require.Len(t, ranges, 1)
+		require.True(t, ranges[0].HasProfile(DIDResolutionProfile))
+	})
+
+	t.Run("invalid parameters are ignored", func(t *testing.T) {
+		ranges := ParseAccept("application/did+json;q=abc;invalid, ,")
+		require.Len(t, ranges, 1)
+		require.Equal(t, DIDJSONMediaType, ranges[0].MediaType)
+		require.Equal(t, float64(1), ranges[0].Quality)
+		require.Empty(t, ranges[0].Params)
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		require.Empty(t, ParseAccept(""))
+	})
+}
@@ -0,0 +1,72 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package common
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/trustbloc/sidetree-core-go/pkg/document"
+)
+
+// NegotiateMediaType returns the supported media type with the highest quality in the given Accept header.
+// The supported media types are 'application/did+json', 'application/json', 'application/did+ld+json',
+// 'application/did+cbor' and 'application/ld+json;profile="https://w3id.org/did-resolution"'.
+// An empty media type is returned if no Accept header is provided or if any media type is accepted.
+// False is returned if none of the accepted media types is supported.
+func NegotiateMediaType(accept string) (string, bool) {
+	if strings.TrimSpace(accept) == "" {
+		return "", true
+	}
+
+	for _, r := range ParseAccept(accept) {
+		switch r.MediaType {
+		case "*/*", "application/*":
+			return "", true
+		case DIDJSONMediaType, DIDLDJSONMediaType, DIDCBORMediaType, JSONMediaType:
+			return r.MediaType, true
+		case JSONLDMediaType:
+			if r.HasProfile(DIDResolutionProfile) {
+				return DIDResolutionMediaType, true
+			}
+		}
+	}
+
+	return "", false
+}
+
+// WriteResolutionResult writes the representation of the resolution result for the given (negotiated) media type:
+// the JSON document without '@context' for 'application/did+json' and 'application/json', the JSON-LD document for
+// 'application/did+ld+json', the CBOR document without '@context' for 'application/did+cbor' and the resolution
+// result for the DID resolution media type (or for an empty media type).
+func WriteResolutionResult(rw http.ResponseWriter, status int, mediaType string, result *document.ResolutionResult) {
+	switch mediaType {
+	case DIDJSONMediaType, JSONMediaType:
+		WriteJSONResponse(rw, status, mediaType, withoutContext(result.Document))
+	case DIDLDJSONMediaType:
+		WriteJSONResponse(rw, status, mediaType, result.Document)
+	case DIDCBORMediaType:
+		WriteCBORResponse(rw, status, mediaType, withoutContext(result.Document))
+	case DIDResolutionMediaType:
+		WriteJSONResponse(rw, status, mediaType, result)
+	default:
+		WriteResponse(rw, status, result)
+	}
+}
+
+// withoutContext returns a copy of the document without the '@context' property.
+func withoutContext(doc document.Document) document.Document {
+	result := make(document.Document, len(doc))
+
+	for key, value := range doc {
+		if key != document.ContextProperty {
+			result[key] = value
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,40 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package common
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNegotiateMediaType(t *testing.T) {
+	tests := map[string]string{
+		"":                               "",
+		"*/*":                            "",
+		"text/html, application/*;q=0.5": "",
+		DIDJSONMediaType:                 DIDJSONMediaType,
+		JSONMediaType:                    JSONMediaType,
+		DIDCBORMediaType:                 DIDCBORMediaType,
+		DIDResolutionMediaType:           DIDResolutionMediaType,
+		"application/did+json;q=0.5, application/did+ld+json": DIDLDJSONMediaType,
+	}
+
+	for accept, expected := range tests {
+		mediaType, ok := NegotiateMediaType(accept)
+		require.True(t, ok, accept)
+		require.Equal(t, expected, mediaType, accept)
+	}
+
+	t.Run("not acceptable", func(t *testing.T) {
+		for _, accept := range []string{"text/html", JSONLDMediaType, "application/did+json;q=0"} {
+			mediaType, ok := NegotiateMediaType(accept)
+			require.False(t, ok, accept)
+			require.Empty(t, mediaType)
+		}
+	})
+}
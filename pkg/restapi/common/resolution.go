@@ -0,0 +1,43 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package common
+
+import (
+	"net/http"
+
+	"github.com/trustbloc/sidetree-core-go/pkg/document"
+)
+
+// ResolutionStatus returns the HTTP status of a successful resolution: 410 (Gone) for a deactivated document
+// and 200 (OK) otherwise.
+func ResolutionStatus(docMetadata document.Metadata) int {
+	if deactivated, ok := docMetadata[document.DeactivatedProperty].(bool); ok && deactivated {
+		return http.StatusGone
+	}
+
+	return http.StatusOK
+}
+
+// ResolutionErrorStatus returns the HTTP status for the DID Resolution error code of the given error.
+func ResolutionErrorStatus(err error) int {
+	switch document.GetErrorCode(err) {
+	case document.InvalidDIDErrorCode, document.InvalidOptionsErrorCode:
+		return http.StatusBadRequest
+	case document.NotFoundErrorCode:
+		return http.StatusNotFound
+	case document.RepresentationNotSupportedErrorCode:
+		return http.StatusNotAcceptable
+	case document.MethodNotSupportedErrorCode:
+		return http.StatusNotImplemented
+	case document.DeactivatedErrorCode:
+		return http.StatusGone
+	default:
+		logger.Errorf("internal server error:  %s", err.Error())
+
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,41 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package common
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/sidetree-core-go/pkg/document"
+)
+
+func TestResolutionStatus(t *testing.T) {
+	require.Equal(t, http.StatusOK, ResolutionStatus(nil))
+	require.Equal(t, http.StatusOK, ResolutionStatus(document.Metadata{document.DeactivatedProperty: false}))
+	require.Equal(t, http.StatusGone, ResolutionStatus(document.Metadata{document.DeactivatedProperty: true}))
+}
+
+func TestResolutionErrorStatus(t *testing.T) {
+	tests := map[error]int{
+		fmt.Errorf("%w: did", document.ErrInvalidDID):   http.StatusBadRequest,
+		document.ErrInvalidOptions:                      http.StatusBadRequest,
+		fmt.Errorf("resolve: %w", document.ErrNotFound): http.StatusNotFound,
+		document.ErrRepresentationNotSupported:          http.StatusNotAcceptable,
+		document.ErrMethodNotSupported:                  http.StatusNotImplemented,
+		document.ErrDeactivated:                         http.StatusGone,
+		document.ErrInternal:                            http.StatusInternalServerError,
+		errors.New("injected error"):                    http.StatusInternalServerError,
+	}
+
+	for err, status := range tests {
+		require.Equal(t, status, ResolutionErrorStatus(err), err.Error())
+	}
+}
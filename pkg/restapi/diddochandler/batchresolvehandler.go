@@ -0,0 +1,30 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package diddochandler
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/trustbloc/sidetree-core-go/pkg/restapi/dochandler"
+)
+
+// BatchResolveHandler resolves multiple DID documents with one request.
+type BatchResolveHandler struct {
+	*handler
+}
+
+// NewBatchResolveHandler returns a new DID document batch resolve handler.
+func NewBatchResolveHandler(basePath string, resolver dochandler.BatchResolver, opts ...dochandler.BatchResolveHandlerOption) *BatchResolveHandler {
+	return &BatchResolveHandler{
+		handler: newHandler(
+			fmt.Sprintf("%s/resolve", basePath),
+			http.MethodPost,
+			dochandler.NewBatchResolveHandler(resolver, opts...).Resolve,
+		),
+	}
+}
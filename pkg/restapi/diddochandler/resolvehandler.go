@@ -19,12 +19,12 @@ type ResolveHandler struct {
 }
 
 // NewResolveHandler returns a new DID document resolve handler.
-func NewResolveHandler(basePath string, resolver dochandler.Resolver) *ResolveHandler {
+func NewResolveHandler(basePath string, resolver dochandler.Resolver, opts ...dochandler.ResolveHandlerOption) *ResolveHandler {
 	return &ResolveHandler{
 		handler: newHandler(
 			fmt.Sprintf("%s/{id}", basePath),
 			http.MethodGet,
-			dochandler.NewResolveHandler(resolver).Resolve,
+			dochandler.NewResolveHandler(resolver, opts...).Resolve,
 		),
 	}
 }
@@ -13,7 +13,9 @@ import (
 
 	"github.com/stretchr/testify/require"
 
+	"github.com/trustbloc/sidetree-core-go/pkg/dereferencer"
 	"github.com/trustbloc/sidetree-core-go/pkg/mocks"
+	"github.com/trustbloc/sidetree-core-go/pkg/restapi/dochandler"
 )
 
 func TestResolveHandler_Resolve(t *testing.T) {
@@ -28,4 +30,7 @@ func TestResolveHandler_Resolve(t *testing.T) {
 	handler.Handler()(rw, req)
 	require.Equal(t, http.StatusBadRequest, rw.Code)
 	require.Contains(t, rw.Body.String(), "must start with supported namespace")
+
+	handler = NewResolveHandler(resolutionPath, docHandler, dochandler.WithDereferencer(dereferencer.New(docHandler)))
+	require.NotNil(t, handler.Handler())
 }
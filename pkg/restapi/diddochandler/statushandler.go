@@ -0,0 +1,30 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package diddochandler
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/trustbloc/sidetree-core-go/pkg/restapi/dochandler"
+)
+
+// OperationStatusHandler returns the status of operations.
+type OperationStatusHandler struct {
+	*handler
+}
+
+// NewOperationStatusHandler returns a new operation status handler. The operation ID is appended to the base path.
+func NewOperationStatusHandler(basePath string, provider dochandler.OperationStatusProvider) *OperationStatusHandler {
+	return &OperationStatusHandler{
+		handler: newHandler(
+			fmt.Sprintf("%s/{id}", basePath),
+			http.MethodGet,
+			dochandler.NewStatusHandler(provider).GetStatus,
+		),
+	}
+}
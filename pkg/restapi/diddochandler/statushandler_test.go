@@ -0,0 +1,31 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package diddochandler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/sidetree-core-go/pkg/opstatus"
+)
+
+const operationStatusPath = "/document/operations/status"
+
+func TestOperationStatusHandler(t *testing.T) {
+	handler := NewOperationStatusHandler(operationStatusPath, opstatus.New(opstatus.NewMemStore()))
+	require.Equal(t, operationStatusPath+"/{id}", handler.Path())
+	require.Equal(t, http.MethodGet, handler.Method())
+	require.NotNil(t, handler.Handler())
+
+	rw := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, operationStatusPath+"/unknown", nil)
+	handler.Handler()(rw, req)
+	require.Equal(t, http.StatusNotFound, rw.Code)
+}
@@ -0,0 +1,175 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package dochandler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/trustbloc/sidetree-core-go/pkg/document"
+	"github.com/trustbloc/sidetree-core-go/pkg/restapi/common"
+)
+
+const (
+	defaultMaxBatchSize = 100
+	defaultMaxIDLength  = 8192
+
+	// idOverhead is the number of bytes allowed for quoting, separating and indenting each ID in the request.
+	idOverhead = 16
+	// requestOverhead is the number of bytes allowed for the rest of the request.
+	requestOverhead = 1024
+)
+
+// BatchResolver resolves multiple documents.
+type BatchResolver interface {
+	ResolveDocuments(ids []string, opts ...document.ResolutionOption) []*document.BatchResolutionResult
+}
+
+// BatchResolveRequest contains the IDs of the documents to resolve.
+type BatchResolveRequest struct {
+	IDs []string `json:"ids"`
+}
+
+// BatchResolveResponse contains a result for each of the requested IDs (in the order of the request).
+type BatchResolveResponse struct {
+	Results []*BatchResolveResult `json:"results"`
+}
+
+// BatchResolveResult is the resolution result for one of the requested IDs. The DID Resolution error code
+// is returned in the 'error' property of the DID resolution metadata if the ID could not be resolved.
+type BatchResolveResult struct {
+	ID string `json:"id"`
+
+	*document.ResolutionResult
+}
+
+// BatchResolveHandler resolves multiple documents with one request.
+type BatchResolveHandler struct {
+	resolver     BatchResolver
+	maxBatchSize int
+	maxIDLength  int
+}
+
+// BatchResolveHandlerOption is an option for the batch resolve handler.
+type BatchResolveHandlerOption func(opts *BatchResolveHandler)
+
+// WithMaxBatchSize sets the maximum number of IDs of a batch resolve request (default 100).
+func WithMaxBatchSize(size int) BatchResolveHandlerOption {
+	return func(opts *BatchResolveHandler) {
+		opts.maxBatchSize = size
+	}
+}
+
+// WithMaxIDLength sets the maximum length of an ID, including long-form DIDs, in a batch resolve
+// request (default 8192). The request body is limited to the maximum batch size of IDs of this length.
+func WithMaxIDLength(length int) BatchResolveHandlerOption {
+	return func(opts *BatchResolveHandler) {
+		opts.maxIDLength = length
+	}
+}
+
+// NewBatchResolveHandler returns a new batch resolve handler.
+func NewBatchResolveHandler(resolver BatchResolver, opts ...BatchResolveHandlerOption) *BatchResolveHandler {
+	h := &BatchResolveHandler{
+		resolver:     resolver,
+		maxBatchSize: defaultMaxBatchSize,
+		maxIDLength:  defaultMaxIDLength,
+	}
+
+	// apply options
+	for _, opt := range opts {
+		opt(h)
+	}
+
+	return h
+}
+
+// Resolve resolves the documents for the IDs in the request body. The 'versionId' and 'versionTime'
+// query parameters apply to all IDs.
+//
+// The response contains a result for each ID. A failed resolution of an ID doesn't fail the request;
+// instead the error is returned in the result of the ID. 400 (Bad Request) is returned if the request
+// is invalid or if it exceeds the maximum batch size.
+func (h *BatchResolveHandler) Resolve(rw http.ResponseWriter, req *http.Request) {
+	request, err := h.getRequest(rw, req)
+	if err != nil {
+		common.WriteError(rw, http.StatusBadRequest, err)
+
+		return
+	}
+
+	opts, err := document.ParseResolutionOptions(req.URL.Query())
+	if err != nil {
+		common.WriteError(rw, http.StatusBadRequest, fmt.Errorf("%w: %s", document.ErrInvalidOptions, err.Error()))
+
+		return
+	}
+
+	logger.Debugf("Resolving DID documents for %d IDs", len(request.IDs))
+
+	results := h.resolver.ResolveDocuments(request.IDs, opts...)
+
+	response := &BatchResolveResponse{
+		Results: make([]*BatchResolveResult, len(results)),
+	}
+
+	for i, result := range results {
+		response.Results[i] = newBatchResolveResult(result)
+	}
+
+	common.WriteJSONResponse(rw, http.StatusOK, common.JSONMediaType, response)
+}
+
+func (h *BatchResolveHandler) getRequest(rw http.ResponseWriter, req *http.Request) (*BatchResolveRequest, error) {
+	maxRequestSize := int64(h.maxBatchSize)*int64(h.maxIDLength+idOverhead) + requestOverhead
+
+	body, err := ioutil.ReadAll(http.MaxBytesReader(rw, req.Body, maxRequestSize))
+	if err != nil {
+		return nil, fmt.Errorf("read batch resolve request (maximum size [%d] bytes): %s", maxRequestSize, err.Error())
+	}
+
+	request := &BatchResolveRequest{}
+
+	err = json.Unmarshal(body, request)
+	if err != nil {
+		return nil, fmt.Errorf("invalid batch resolve request: %s", err.Error())
+	}
+
+	if len(request.IDs) == 0 {
+		return nil, errors.New("missing IDs in batch resolve request")
+	}
+
+	if len(request.IDs) > h.maxBatchSize {
+		return nil, fmt.Errorf("number of IDs [%d] exceeds the maximum batch size [%d]", len(request.IDs), h.maxBatchSize)
+	}
+
+	return request, nil
+}
+
+func newBatchResolveResult(result *document.BatchResolutionResult) *BatchResolveResult {
+	if result.Error != nil {
+		return &BatchResolveResult{
+			ID: result.ID,
+			ResolutionResult: &document.ResolutionResult{
+				Context:               document.DIDResolutionContext,
+				DIDResolutionMetadata: document.NewErrorMetadata(result.Error),
+			},
+		}
+	}
+
+	return &BatchResolveResult{
+		ID: result.ID,
+		ResolutionResult: &document.ResolutionResult{
+			Context:          document.DIDResolutionContext,
+			Document:         result.Result.Document,
+			DocumentMetadata: result.Result.DocumentMetadata,
+		},
+	}
+}
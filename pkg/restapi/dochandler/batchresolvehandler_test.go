@@ -0,0 +1,136 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package dochandler
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/sidetree-core-go/pkg/canonicalizer"
+	"github.com/trustbloc/sidetree-core-go/pkg/document"
+	"github.com/trustbloc/sidetree-core-go/pkg/mocks"
+	"github.com/trustbloc/sidetree-core-go/pkg/restapi/common"
+)
+
+func TestNewBatchResolveHandler(t *testing.T) {
+	docHandler := mocks.NewMockDocumentHandler().WithNamespace(namespace)
+
+	handler := NewBatchResolveHandler(docHandler)
+	require.Equal(t, defaultMaxBatchSize, handler.maxBatchSize)
+	require.Equal(t, defaultMaxIDLength, handler.maxIDLength)
+
+	handler = NewBatchResolveHandler(docHandler, WithMaxBatchSize(5), WithMaxIDLength(100))
+	require.Equal(t, 5, handler.maxBatchSize)
+	require.Equal(t, 100, handler.maxIDLength)
+}
+
+func TestBatchResolveHandler_Resolve(t *testing.T) {
+	docHandler := mocks.NewMockDocumentHandler().
+		WithNamespace(namespace).WithProtocolClient(newMockProtocolClient())
+
+	create, err := getCreateRequest()
+	require.NoError(t, err)
+
+	createReq, err := canonicalizer.MarshalCanonical(create)
+	require.NoError(t, err)
+
+	result, err := docHandler.ProcessOperation(createReq, 0)
+	require.NoError(t, err)
+
+	docID := result.Document.ID()
+	notFoundID := namespace + ":notfound"
+
+	t.Run("success", func(t *testing.T) {
+		handler := NewBatchResolveHandler(docHandler)
+
+		rw := httptest.NewRecorder()
+		handler.Resolve(rw, newBatchResolveRequest(t, "", docID, "invalid", notFoundID))
+		require.Equal(t, http.StatusOK, rw.Code)
+		require.Equal(t, common.JSONMediaType, rw.Header().Get("content-type"))
+
+		response := &BatchResolveResponse{}
+		require.NoError(t, json.Unmarshal(rw.Body.Bytes(), response))
+		require.Len(t, response.Results, 3)
+
+		require.Equal(t, docID, response.Results[0].ID)
+		require.Equal(t, document.DIDResolutionContext, response.Results[0].Context)
+		require.Equal(t, docID, response.Results[0].Document.ID())
+		require.Empty(t, response.Results[0].DIDResolutionMetadata)
+
+		require.Equal(t, "invalid", response.Results[1].ID)
+		require.Empty(t, response.Results[1].Document)
+		require.Equal(t, document.InvalidDIDErrorCode, response.Results[1].DIDResolutionMetadata[document.ErrorProperty])
+
+		require.Equal(t, notFoundID, response.Results[2].ID)
+		require.Empty(t, response.Results[2].Document)
+		require.Equal(t, document.NotFoundErrorCode, response.Results[2].DIDResolutionMetadata[document.ErrorProperty])
+		require.NotEmpty(t, response.Results[2].DIDResolutionMetadata[document.ErrorMessageProperty])
+	})
+
+	t.Run("invalid request", func(t *testing.T) {
+		handler := NewBatchResolveHandler(docHandler)
+
+		rw := httptest.NewRecorder()
+		handler.Resolve(rw, httptest.NewRequest(http.MethodPost, "/identifiers/resolve", bytes.NewReader([]byte("{"))))
+		require.Equal(t, http.StatusBadRequest, rw.Code)
+		require.Contains(t, rw.Body.String(), "invalid batch resolve request")
+	})
+
+	t.Run("missing IDs", func(t *testing.T) {
+		handler := NewBatchResolveHandler(docHandler)
+
+		rw := httptest.NewRecorder()
+		handler.Resolve(rw, newBatchResolveRequest(t, ""))
+		require.Equal(t, http.StatusBadRequest, rw.Code)
+		require.Contains(t, rw.Body.String(), "missing IDs in batch resolve request")
+	})
+
+	t.Run("maximum batch size exceeded", func(t *testing.T) {
+		handler := NewBatchResolveHandler(docHandler, WithMaxBatchSize(2))
+
+		rw := httptest.NewRecorder()
+		handler.Resolve(rw, newBatchResolveRequest(t, "", docID, notFoundID, docID))
+		require.Equal(t, http.StatusBadRequest, rw.Code)
+		require.Contains(t, rw.Body.String(), "number of IDs [3] exceeds the maximum batch size [2]")
+	})
+
+	t.Run("maximum request size exceeded", func(t *testing.T) {
+		handler := NewBatchResolveHandler(docHandler, WithMaxBatchSize(2), WithMaxIDLength(100))
+
+		rw := httptest.NewRecorder()
+		handler.Resolve(rw, newBatchResolveRequest(t, "", docID, strings.Repeat("a", 2000)))
+		require.Equal(t, http.StatusBadRequest, rw.Code)
+		require.Contains(t, rw.Body.String(), "request body too large")
+	})
+
+	t.Run("invalid resolution options", func(t *testing.T) {
+		handler := NewBatchResolveHandler(docHandler)
+
+		rw := httptest.NewRecorder()
+		handler.Resolve(rw, newBatchResolveRequest(t, "versionTime=abc", docID))
+		require.Equal(t, http.StatusBadRequest, rw.Code)
+		require.Contains(t, rw.Body.String(), "invalid resolution options")
+	})
+}
+
+func newBatchResolveRequest(t *testing.T, query string, ids ...string) *http.Request {
+	body, err := json.Marshal(&BatchResolveRequest{IDs: ids})
+	require.NoError(t, err)
+
+	target := "/identifiers/resolve"
+	if query != "" {
+		target += "?" + query
+	}
+
+	return httptest.NewRequest(http.MethodPost, target, bytes.NewReader(body))
+}
@@ -7,11 +7,12 @@ SPDX-License-Identifier: Apache-2.0
 package dochandler
 
 import (
+	"fmt"
 	"net/http"
+	"net/url"
 	"strings"
 
 	"github.com/gorilla/mux"
-	"github.com/pkg/errors"
 	"github.com/trustbloc/edge-core/pkg/log"
 
 	"github.com/trustbloc/sidetree-core-go/pkg/document"
@@ -22,52 +23,138 @@ var logger = log.New("sidetree-core-restapi-dochandler")
 
 // Resolver resolves documents.
 type Resolver interface {
-	ResolveDocument(idOrDocument string) (*document.ResolutionResult, error)
+	ResolveDocument(idOrDocument string, opts ...document.ResolutionOption) (*document.ResolutionResult, error)
+}
+
+// Dereferencer dereferences DID URLs.
+type Dereferencer interface {
+	Dereference(didURL string, opts ...document.ResolutionOption) (*document.DereferencingResult, error)
 }
 
 // ResolveHandler resolves generic documents.
 type ResolveHandler struct {
-	resolver Resolver
+	resolver     Resolver
+	dereferencer Dereferencer
+}
+
+// ResolveHandlerOption is an option for the resolve handler.
+type ResolveHandlerOption func(opts *ResolveHandler)
+
+// WithDereferencer sets an optional DID URL dereferencer. If set then requests for a DID URL with a fragment
+// (encoded as '%23') or with the 'service' or 'relativeRef' parameter are dereferenced.
+func WithDereferencer(d Dereferencer) ResolveHandlerOption {
+	return func(opts *ResolveHandler) {
+		opts.dereferencer = d
+	}
 }
 
 // NewResolveHandler returns a new document resolve handler.
-func NewResolveHandler(resolver Resolver) *ResolveHandler {
-	return &ResolveHandler{
+func NewResolveHandler(resolver Resolver, opts ...ResolveHandlerOption) *ResolveHandler {
+	rh := &ResolveHandler{
 		resolver: resolver,
 	}
+
+	// apply options
+	for _, opt := range opts {
+		opt(rh)
+	}
+
+	return rh
 }
 
-// Resolve resolves a document.
+// Resolve resolves a document. A specific version of the document may be requested with the
+// 'versionId' and 'versionTime' query parameters. DID URLs are dereferenced if a dereferencer is configured.
+//
+// Errors are returned as a resolution (or dereferencing) result with the DID Resolution error code in the
+// 'error' property of the metadata and the HTTP status is set according to the error code. The status of
+// a deactivated document is 410 (Gone).
+//
+// The representation of a resolved document is selected with the Accept header:
+// 'application/did+json' or 'application/json' (JSON document without '@context'), 'application/did+ld+json' (JSON-LD document),
+// 'application/did+cbor' (CBOR document) or 'application/ld+json;profile="https://w3id.org/did-resolution"'
+// (resolution result). The resolution result is returned if no Accept header is provided (or for '*/*') and
+// 406 (Not Acceptable) is returned if none of the accepted media types is supported.
 func (o *ResolveHandler) Resolve(rw http.ResponseWriter, req *http.Request) {
 	id := getID(req)
+
+	if o.dereferencer != nil && isDereferencingRequest(id, req.URL.Query()) {
+		o.dereference(rw, getDIDURL(id, req.URL.RawQuery))
+
+		return
+	}
+
+	accept := req.Header.Get("Accept")
+
+	mediaType, ok := common.NegotiateMediaType(accept)
+	if !ok {
+		writeResolutionError(rw, fmt.Errorf("%w: none of the accepted media types [%s] is supported",
+			document.ErrRepresentationNotSupported, accept))
+
+		return
+	}
+
 	logger.Debugf("Resolving DID document for ID [%s]", id)
-	response, err := o.doResolve(id)
+
+	opts, err := document.ParseResolutionOptions(req.URL.Query())
 	if err != nil {
-		common.WriteError(rw, err.(*common.HTTPError).Status(), err)
+		writeResolutionError(rw, fmt.Errorf("%w: %s", document.ErrInvalidOptions, err.Error()))
+
+		return
+	}
+
+	response, err := o.resolver.ResolveDocument(id, opts...)
+	if err != nil {
+		writeResolutionError(rw, err)
 
 		return
 	}
 
 	logger.Debugf("... resolved DID document for ID [%s]: %s", id, response.Document)
-	common.WriteResponse(rw, http.StatusOK, response)
+	common.WriteResolutionResult(rw, common.ResolutionStatus(response.DocumentMetadata), mediaType, response)
 }
 
-func (o *ResolveHandler) doResolve(id string) (*document.ResolutionResult, error) {
-	resolutionResult, err := o.resolver.ResolveDocument(id)
+func (o *ResolveHandler) dereference(rw http.ResponseWriter, didURL string) {
+	logger.Debugf("Dereferencing DID URL [%s]", didURL)
+
+	response, err := o.dereferencer.Dereference(didURL)
 	if err != nil {
-		if strings.Contains(err.Error(), "bad request") {
-			return nil, common.NewHTTPError(http.StatusBadRequest, err)
-		}
-		if strings.Contains(err.Error(), "not found") {
-			return nil, common.NewHTTPError(http.StatusNotFound, errors.New("document not found"))
-		}
+		common.WriteResponse(rw, common.ResolutionErrorStatus(err), &document.DereferencingResult{
+			Context:               document.DIDResolutionContext,
+			DereferencingMetadata: document.NewErrorMetadata(err),
+		})
+
+		return
+	}
 
-		logger.Errorf("internal server error:  %s", err.Error())
+	common.WriteResponse(rw, common.ResolutionStatus(response.ContentMetadata), response)
+}
+
+// writeResolutionError writes a resolution result that contains the error code and message in the
+// DID resolution metadata.
+func writeResolutionError(rw http.ResponseWriter, err error) {
+	common.WriteResponse(rw, common.ResolutionErrorStatus(err), &document.ResolutionResult{
+		Context:               document.DIDResolutionContext,
+		DIDResolutionMetadata: document.NewErrorMetadata(err),
+	})
+}
+
+// isDereferencingRequest returns true if the ID contains a fragment or if a service is requested.
+func isDereferencingRequest(id string, query url.Values) bool {
+	return strings.Contains(id, "#") || query.Get(document.ServiceParam) != "" || query.Get(document.RelativeRefParam) != ""
+}
+
+// getDIDURL adds the query to the given ID (in front of the fragment).
+func getDIDURL(id, rawQuery string) string {
+	if rawQuery == "" {
+		return id
+	}
 
-		return nil, common.NewHTTPError(http.StatusInternalServerError, err)
+	did, fragment := id, ""
+	if pos := strings.Index(id, "#"); pos >= 0 {
+		did, fragment = id[:pos], id[pos:]
 	}
 
-	return resolutionResult, nil
+	return did + "?" + rawQuery + fragment
 }
 
 var getID = func(req *http.Request) string {
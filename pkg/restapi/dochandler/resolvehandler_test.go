@@ -7,22 +7,27 @@ SPDX-License-Identifier: Apache-2.0
 package dochandler
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 
+	"github.com/fxamacker/cbor/v2"
 	"github.com/stretchr/testify/require"
 
 	"github.com/trustbloc/sidetree-core-go/pkg/api/operation"
 	"github.com/trustbloc/sidetree-core-go/pkg/canonicalizer"
+	"github.com/trustbloc/sidetree-core-go/pkg/dereferencer"
+	"github.com/trustbloc/sidetree-core-go/pkg/document"
 	"github.com/trustbloc/sidetree-core-go/pkg/docutil"
 	"github.com/trustbloc/sidetree-core-go/pkg/encoder"
 	"github.com/trustbloc/sidetree-core-go/pkg/hashing"
 	"github.com/trustbloc/sidetree-core-go/pkg/jws"
 	"github.com/trustbloc/sidetree-core-go/pkg/mocks"
 	"github.com/trustbloc/sidetree-core-go/pkg/patch"
+	"github.com/trustbloc/sidetree-core-go/pkg/restapi/common"
 	"github.com/trustbloc/sidetree-core-go/pkg/versions/1_0/model"
 )
 
@@ -83,6 +88,20 @@ func TestResolveHandler_Resolve(t *testing.T) {
 		req := httptest.NewRequest(http.MethodGet, "/document", nil)
 		handler.Resolve(rw, req)
 		require.Equal(t, http.StatusBadRequest, rw.Code)
+		require.Contains(t, rw.Body.String(), `"error":"invalidDid"`)
+		require.Contains(t, rw.Body.String(), "must start with supported namespace")
+	})
+	t.Run("Method not supported", func(t *testing.T) {
+		getID = func(req *http.Request) string { return "did:other:someid" }
+		docHandler := mocks.NewMockDocumentHandler().WithNamespace(namespace).
+			WithError(fmt.Errorf("%w: did:other", document.ErrMethodNotSupported))
+		handler := NewResolveHandler(docHandler)
+
+		rw := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/document", nil)
+		handler.Resolve(rw, req)
+		require.Equal(t, http.StatusNotImplemented, rw.Code)
+		require.Contains(t, rw.Body.String(), `"error":"methodNotSupported"`)
 	})
 	t.Run("Not found", func(t *testing.T) {
 		getID = func(req *http.Request) string {
@@ -95,6 +114,7 @@ func TestResolveHandler_Resolve(t *testing.T) {
 		req := httptest.NewRequest(http.MethodGet, "/document", nil)
 		handler.Resolve(rw, req)
 		require.Equal(t, http.StatusNotFound, rw.Code)
+		require.Contains(t, rw.Body.String(), `"error":"notFound"`)
 	})
 	t.Run("Error", func(t *testing.T) {
 		getID = func(req *http.Request) string {
@@ -108,8 +128,31 @@ func TestResolveHandler_Resolve(t *testing.T) {
 		req := httptest.NewRequest(http.MethodGet, "/document", nil)
 		handler.Resolve(rw, req)
 		require.Equal(t, http.StatusInternalServerError, rw.Code)
+		require.Contains(t, rw.Body.String(), `"error":"internalError"`)
 		require.Contains(t, rw.Body.String(), errExpected.Error())
 	})
+	t.Run("Version", func(t *testing.T) {
+		getID = func(req *http.Request) string {
+			return namespace + docutil.NamespaceDelimiter + "someid"
+		}
+
+		resolver := &optionsRecordingResolver{}
+		handler := NewResolveHandler(resolver)
+
+		rw := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/document?versionId=2&versionTime=100", nil)
+		handler.Resolve(rw, req)
+		require.Equal(t, http.StatusOK, rw.Code)
+		require.Equal(t, uint64(2), resolver.opts.VersionID)
+		require.Equal(t, uint64(100), resolver.opts.VersionTime.TransactionTime)
+
+		rw = httptest.NewRecorder()
+		req = httptest.NewRequest(http.MethodGet, "/document?versionId=0", nil)
+		handler.Resolve(rw, req)
+		require.Equal(t, http.StatusBadRequest, rw.Code)
+		require.Contains(t, rw.Body.String(), `"error":"invalidOptions"`)
+		require.Contains(t, rw.Body.String(), "invalid versionId[0]")
+	})
 	t.Run("Document is no longer available", func(t *testing.T) {
 		docHandler := mocks.NewMockDocumentHandler().WithNamespace(namespace)
 
@@ -142,10 +185,213 @@ func TestResolveHandler_Resolve(t *testing.T) {
 		rw := httptest.NewRecorder()
 		req := httptest.NewRequest(http.MethodGet, "/document", nil)
 		handler.Resolve(rw, req)
+		require.Equal(t, http.StatusGone, rw.Code)
+		require.Contains(t, rw.Body.String(), `"deactivated":true`)
+	})
+}
+
+func TestResolveHandler_ContentNegotiation(t *testing.T) {
+	did := namespace + docutil.NamespaceDelimiter + "someid"
+
+	getID = func(req *http.Request) string { return did }
+
+	resolver := &resultResolver{result: &document.ResolutionResult{
+		Context: document.DIDResolutionContext,
+		Document: document.Document{
+			document.ContextProperty: []interface{}{"https://www.w3.org/ns/did/v1"},
+			document.IDProperty:      did,
+		},
+		DocumentMetadata: document.Metadata{document.PublishedProperty: true},
+	}}
+
+	handler := NewResolveHandler(resolver)
+
+	resolve := func(accept string) *httptest.ResponseRecorder {
+		rw := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/document", nil)
+		req.Header.Set("Accept", accept)
+		handler.Resolve(rw, req)
+
+		return rw
+	}
+
+	t.Run("default", func(t *testing.T) {
+		for _, accept := range []string{"", "*/*", "text/html;q=0.9, application/*;q=0.8"} {
+			rw := resolve(accept)
+			require.Equal(t, http.StatusOK, rw.Code)
+			require.Equal(t, common.DIDLDJSONMediaType, rw.Header().Get("content-type"))
+			require.Contains(t, rw.Body.String(), `"didDocumentMetadata"`)
+		}
+	})
+
+	t.Run("DID document (JSON)", func(t *testing.T) {
+		rw := resolve(common.DIDJSONMediaType)
+		require.Equal(t, http.StatusOK, rw.Code)
+		require.Equal(t, common.DIDJSONMediaType, rw.Header().Get("content-type"))
+
+		var doc document.Document
+		require.NoError(t, json.Unmarshal(rw.Body.Bytes(), &doc))
+		require.Equal(t, did, doc.ID())
+		require.NotContains(t, doc, document.ContextProperty)
+
+		// the resolved document is not modified
+		require.Contains(t, resolver.result.Document, document.ContextProperty)
+	})
+
+	t.Run("DID document (plain JSON)", func(t *testing.T) {
+		rw := resolve("application/json")
+		require.Equal(t, http.StatusOK, rw.Code)
+		require.Equal(t, common.JSONMediaType, rw.Header().Get("content-type"))
+
+		var doc document.Document
+		require.NoError(t, json.Unmarshal(rw.Body.Bytes(), &doc))
+		require.Equal(t, did, doc.ID())
+		require.NotContains(t, doc, document.ContextProperty)
+		require.NotContains(t, doc, "didDocument")
+	})
+
+	t.Run("DID document (JSON-LD)", func(t *testing.T) {
+		rw := resolve("application/did+json;q=0.5, application/did+ld+json")
+		require.Equal(t, http.StatusOK, rw.Code)
+		require.Equal(t, common.DIDLDJSONMediaType, rw.Header().Get("content-type"))
+
+		var doc document.Document
+		require.NoError(t, json.Unmarshal(rw.Body.Bytes(), &doc))
+		require.Equal(t, did, doc.ID())
+		require.Contains(t, doc, document.ContextProperty)
+		require.NotContains(t, doc, "didDocument")
+	})
+
+	t.Run("DID document (CBOR)", func(t *testing.T) {
+		rw := resolve(common.DIDCBORMediaType)
+		require.Equal(t, http.StatusOK, rw.Code)
+		require.Equal(t, common.DIDCBORMediaType, rw.Header().Get("content-type"))
+
+		var doc map[string]interface{}
+		require.NoError(t, cbor.Unmarshal(rw.Body.Bytes(), &doc))
+		require.Equal(t, did, doc[document.IDProperty])
+		require.NotContains(t, doc, document.ContextProperty)
+	})
+
+	t.Run("resolution result", func(t *testing.T) {
+		rw := resolve(`application/ld+json;profile="https://w3id.org/did-resolution"`)
+		require.Equal(t, http.StatusOK, rw.Code)
+		require.Equal(t, common.DIDResolutionMediaType, rw.Header().Get("content-type"))
+
+		var result document.ResolutionResult
+		require.NoError(t, json.Unmarshal(rw.Body.Bytes(), &result))
+		require.Equal(t, did, result.Document.ID())
+		require.Equal(t, true, result.DocumentMetadata[document.PublishedProperty])
+	})
+
+	t.Run("not acceptable", func(t *testing.T) {
+		for _, accept := range []string{"text/html", "application/ld+json", "application/did+json;q=0"} {
+			rw := resolve(accept)
+			require.Equal(t, http.StatusNotAcceptable, rw.Code)
+			require.Contains(t, rw.Body.String(), `"error":"representationNotSupported"`)
+			require.Contains(t, rw.Body.String(), "none of the accepted media types")
+		}
+	})
+}
+
+func TestResolveHandler_Dereference(t *testing.T) {
+	did := namespace + docutil.NamespaceDelimiter + "someid"
+
+	t.Run("Success", func(t *testing.T) {
+		getID = func(req *http.Request) string { return did + "#key-1" }
+
+		d := &mockDereferencer{result: &document.DereferencingResult{ContentStream: "content"}}
+		handler := NewResolveHandler(&optionsRecordingResolver{}, WithDereferencer(d))
+
+		rw := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/document?versionId=2", nil)
+		handler.Resolve(rw, req)
+		require.Equal(t, http.StatusOK, rw.Code)
+		require.Contains(t, rw.Body.String(), `"contentStream":"content"`)
+		require.Equal(t, did+"?versionId=2#key-1", d.didURL)
+	})
+	t.Run("Service", func(t *testing.T) {
+		getID = func(req *http.Request) string { return did }
+
+		d := &mockDereferencer{result: &document.DereferencingResult{}}
+		handler := NewResolveHandler(&optionsRecordingResolver{}, WithDereferencer(d))
+
+		rw := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/document?service=hub&relativeRef=%2Fpath", nil)
+		handler.Resolve(rw, req)
+		require.Equal(t, http.StatusOK, rw.Code)
+		require.Equal(t, did+"?service=hub&relativeRef=%2Fpath", d.didURL)
+	})
+	t.Run("Resolve without fragment or service", func(t *testing.T) {
+		getID = func(req *http.Request) string { return did }
+
+		d := &mockDereferencer{}
+		handler := NewResolveHandler(&optionsRecordingResolver{}, WithDereferencer(d))
+
+		rw := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/document?versionId=2", nil)
+		handler.Resolve(rw, req)
 		require.Equal(t, http.StatusOK, rw.Code)
+		require.Empty(t, d.didURL)
+	})
+	t.Run("Errors", func(t *testing.T) {
+		getID = func(req *http.Request) string { return did + "#key-1" }
+
+		tests := []struct {
+			err    error
+			status int
+			body   string
+		}{
+			{err: fmt.Errorf("%w: invalid DID URL", document.ErrInvalidDID), status: http.StatusBadRequest, body: `"error":"invalidDid"`},
+			{err: fmt.Errorf("key: %w", dereferencer.ErrNotFound), status: http.StatusNotFound, body: "key: DID URL resource not found"},
+			{err: fmt.Errorf("%w: uniqueSuffix not found", document.ErrNotFound), status: http.StatusNotFound, body: `"error":"notFound"`},
+			{err: document.ErrDeactivated, status: http.StatusGone, body: `"error":"deactivated"`},
+			{err: errors.New("injected error"), status: http.StatusInternalServerError, body: "injected error"},
+		}
+
+		for _, test := range tests {
+			handler := NewResolveHandler(&optionsRecordingResolver{}, WithDereferencer(&mockDereferencer{err: test.err}))
+
+			rw := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, "/document", nil)
+			handler.Resolve(rw, req)
+			require.Equal(t, test.status, rw.Code)
+			require.Contains(t, rw.Body.String(), test.body)
+		}
 	})
 }
 
+type mockDereferencer struct {
+	result *document.DereferencingResult
+	err    error
+	didURL string
+}
+
+func (m *mockDereferencer) Dereference(didURL string, _ ...document.ResolutionOption) (*document.DereferencingResult, error) {
+	m.didURL = didURL
+
+	return m.result, m.err
+}
+
+type resultResolver struct {
+	result *document.ResolutionResult
+}
+
+func (r *resultResolver) ResolveDocument(string, ...document.ResolutionOption) (*document.ResolutionResult, error) {
+	return r.result, nil
+}
+
+// optionsRecordingResolver records the resolution options of the last resolution.
+type optionsRecordingResolver struct {
+	opts document.ResolutionOptions
+}
+
+func (r *optionsRecordingResolver) ResolveDocument(id string, opts ...document.ResolutionOption) (*document.ResolutionResult, error) {
+	r.opts = document.GetResolutionOptions(opts...)
+
+	return &document.ResolutionResult{Document: document.Document{"id": id}}, nil
+}
+
 func getCreateRequest() (*model.CreateRequest, error) {
 	delta, err := getDelta()
 	if err != nil {
@@ -0,0 +1,56 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package dochandler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/trustbloc/sidetree-core-go/pkg/opstatus"
+	"github.com/trustbloc/sidetree-core-go/pkg/restapi/common"
+)
+
+// OperationStatusProvider returns the status of an operation.
+type OperationStatusProvider interface {
+	Get(id string) (*opstatus.OperationStatus, error)
+}
+
+// StatusHandler returns the status of operations.
+type StatusHandler struct {
+	provider OperationStatusProvider
+}
+
+// NewStatusHandler returns a new operation status handler.
+func NewStatusHandler(provider OperationStatusProvider) *StatusHandler {
+	return &StatusHandler{
+		provider: provider,
+	}
+}
+
+// GetStatus returns the status of the operation with the given ID.
+func (h *StatusHandler) GetStatus(rw http.ResponseWriter, req *http.Request) {
+	id := getID(req)
+
+	logger.Debugf("Retrieving status for operation [%s]", id)
+
+	status, err := h.provider.Get(id)
+	if err != nil {
+		if errors.Is(err, opstatus.ErrNotFound) {
+			common.WriteError(rw, http.StatusNotFound, errors.New("operation status not found"))
+
+			return
+		}
+
+		logger.Errorf("internal server error:  %s", err.Error())
+
+		common.WriteError(rw, http.StatusInternalServerError, err)
+
+		return
+	}
+
+	common.WriteResponse(rw, http.StatusOK, status)
+}
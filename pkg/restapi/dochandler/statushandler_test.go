@@ -0,0 +1,77 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package dochandler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/sidetree-core-go/pkg/api/operation"
+	"github.com/trustbloc/sidetree-core-go/pkg/opstatus"
+)
+
+func TestStatusHandler_GetStatus(t *testing.T) {
+	tracker := opstatus.New(opstatus.NewMemStore())
+
+	opBuffer := []byte(`{"type":"update"}`)
+
+	id, err := opstatus.GetOperationID(opBuffer)
+	require.NoError(t, err)
+
+	require.NoError(t, tracker.Anchored([]*operation.QueuedOperation{
+		{Namespace: namespace, UniqueSuffix: "suffix", OperationBuffer: opBuffer},
+	}, "1.anchor", nil))
+
+	handler := NewStatusHandler(tracker)
+
+	t.Run("success", func(t *testing.T) {
+		getID = func(req *http.Request) string { return id }
+
+		rw := httptest.NewRecorder()
+		handler.GetStatus(rw, httptest.NewRequest(http.MethodGet, "/operations", nil))
+		require.Equal(t, http.StatusOK, rw.Code)
+
+		status := &opstatus.OperationStatus{}
+		require.NoError(t, json.Unmarshal(rw.Body.Bytes(), status))
+		require.Equal(t, id, status.ID)
+		require.Equal(t, "suffix", status.UniqueSuffix)
+		require.Equal(t, opstatus.StatusAnchored, status.Status)
+		require.Equal(t, "1.anchor", status.AnchorString)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		getID = func(req *http.Request) string { return "unknown" }
+
+		rw := httptest.NewRecorder()
+		handler.GetStatus(rw, httptest.NewRequest(http.MethodGet, "/operations", nil))
+		require.Equal(t, http.StatusNotFound, rw.Code)
+		require.Equal(t, "operation status not found", rw.Body.String())
+	})
+
+	t.Run("internal error", func(t *testing.T) {
+		getID = func(req *http.Request) string { return id }
+
+		rw := httptest.NewRecorder()
+		NewStatusHandler(&mockStatusProvider{err: errors.New("injected error")}).GetStatus(rw,
+			httptest.NewRequest(http.MethodGet, "/operations", nil))
+		require.Equal(t, http.StatusInternalServerError, rw.Code)
+		require.Equal(t, "injected error", rw.Body.String())
+	})
+}
+
+type mockStatusProvider struct {
+	err error
+}
+
+func (m *mockStatusProvider) Get(string) (*opstatus.OperationStatus, error) {
+	return nil, m.err
+}
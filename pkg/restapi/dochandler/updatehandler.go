@@ -7,15 +7,21 @@ SPDX-License-Identifier: Apache-2.0
 package dochandler
 
 import (
+	"errors"
 	"io/ioutil"
 	"net/http"
-	"strings"
 
+	"github.com/trustbloc/sidetree-core-go/pkg/api/operation"
 	"github.com/trustbloc/sidetree-core-go/pkg/api/protocol"
 	"github.com/trustbloc/sidetree-core-go/pkg/document"
+	"github.com/trustbloc/sidetree-core-go/pkg/opstatus"
 	"github.com/trustbloc/sidetree-core-go/pkg/restapi/common"
 )
 
+// OperationIDHeader is the response header that contains the ID of the accepted operation. The ID may be
+// used for querying the operation status.
+const OperationIDHeader = "Sidetree-Operation-Id"
+
 // Processor processes document operations.
 type Processor interface {
 	Namespace() string
@@ -53,18 +59,25 @@ func (h *UpdateHandler) Update(rw http.ResponseWriter, req *http.Request) {
 
 		return
 	}
+
+	operationID, err := opstatus.GetOperationID(request)
+	if err != nil {
+		logger.Warnf("failed to compute operation ID: %s", err.Error())
+	} else {
+		rw.Header().Set(OperationIDHeader, operationID)
+	}
 	common.WriteResponse(rw, http.StatusOK, response)
 }
 
-func (h *UpdateHandler) doUpdate(operation []byte) (*document.ResolutionResult, error) {
+func (h *UpdateHandler) doUpdate(operationBuffer []byte) (*document.ResolutionResult, error) {
 	currentProtocol, err := h.protocol.Current()
 	if err != nil {
 		return nil, err
 	}
 
-	result, err := h.processor.ProcessOperation(operation, currentProtocol.Protocol().GenesisTime)
+	result, err := h.processor.ProcessOperation(operationBuffer, currentProtocol.Protocol().GenesisTime)
 	if err != nil {
-		if strings.Contains(err.Error(), "bad request") {
+		if errors.Is(err, operation.ErrBadRequest) {
 			logger.Warnf("operation validation error: %s", err.Error())
 
 			return nil, common.NewHTTPError(http.StatusBadRequest, err)
@@ -27,6 +27,7 @@ import (
 	"github.com/trustbloc/sidetree-core-go/pkg/encoder"
 	"github.com/trustbloc/sidetree-core-go/pkg/hashing"
 	"github.com/trustbloc/sidetree-core-go/pkg/mocks"
+	"github.com/trustbloc/sidetree-core-go/pkg/opstatus"
 	"github.com/trustbloc/sidetree-core-go/pkg/patch"
 	"github.com/trustbloc/sidetree-core-go/pkg/util/ecsigner"
 	"github.com/trustbloc/sidetree-core-go/pkg/util/pubkey"
@@ -72,6 +73,10 @@ func TestUpdateHandler_Update(t *testing.T) {
 		require.Equal(t, http.StatusOK, rw.Code)
 		require.Equal(t, "application/did+ld+json", rw.Header().Get("content-type"))
 
+		operationID, err := opstatus.GetOperationID(create)
+		require.NoError(t, err)
+		require.Equal(t, operationID, rw.Header().Get(OperationIDHeader))
+
 		body, err := ioutil.ReadAll(rw.Body)
 		require.NoError(t, err)
 
@@ -0,0 +1,121 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package uniresolver implements the HTTP endpoint of a DIF Universal Resolver driver.
+//
+// The endpoint 'GET /1.0/identifiers/{did}' returns the resolution result (with the DID resolution metadata,
+// the DID document metadata and the DID document) as 'application/ld+json;profile="https://w3id.org/did-resolution"'
+// unless another representation of the DID document (e.g. 'application/did+ld+json' or 'application/did+json')
+// is requested with the Accept header.
+// Resolution options (e.g. 'versionId' and 'versionTime') are passed as query parameters. Errors are returned as
+// a resolution result with the DID Resolution error code in the 'error' property of the DID resolution metadata
+// and the status code is set according to the error code (e.g. 404 for 'notFound' and 410 for a deactivated document).
+package uniresolver
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/trustbloc/edge-core/pkg/log"
+
+	"github.com/trustbloc/sidetree-core-go/pkg/document"
+	"github.com/trustbloc/sidetree-core-go/pkg/restapi/common"
+	"github.com/trustbloc/sidetree-core-go/pkg/restapi/dochandler"
+)
+
+var logger = log.New("sidetree-core-restapi-uniresolver")
+
+// ResolutionPath is the path of the Universal Resolver driver endpoint.
+const ResolutionPath = "/1.0/identifiers"
+
+// ResolveHandler resolves DIDs according to the Universal Resolver driver contract.
+type ResolveHandler struct {
+	resolver dochandler.Resolver
+}
+
+// NewResolveHandler returns a new Universal Resolver driver handler.
+func NewResolveHandler(resolver dochandler.Resolver) *ResolveHandler {
+	return &ResolveHandler{
+		resolver: resolver,
+	}
+}
+
+// Path returns the context path.
+func (h *ResolveHandler) Path() string {
+	return fmt.Sprintf("%s/{id}", ResolutionPath)
+}
+
+// Method returns the HTTP method.
+func (h *ResolveHandler) Method() string {
+	return http.MethodGet
+}
+
+// Handler returns the handler.
+func (h *ResolveHandler) Handler() common.HTTPRequestHandler {
+	return h.resolve
+}
+
+func (h *ResolveHandler) resolve(rw http.ResponseWriter, req *http.Request) {
+	did := getID(req)
+
+	accept := req.Header.Get("Accept")
+
+	mediaType, ok := common.NegotiateMediaType(accept)
+	if !ok {
+		writeError(rw, fmt.Errorf("%w: none of the accepted media types [%s] is supported",
+			document.ErrRepresentationNotSupported, accept))
+
+		return
+	}
+
+	if mediaType == "" {
+		mediaType = common.DIDResolutionMediaType
+	}
+
+	opts, err := document.ParseResolutionOptions(req.URL.Query())
+	if err != nil {
+		writeError(rw, fmt.Errorf("%w: %s", document.ErrInvalidOptions, err.Error()))
+
+		return
+	}
+
+	logger.Debugf("Resolving DID [%s]", did)
+
+	result, err := h.resolver.ResolveDocument(did, opts...)
+	if err != nil {
+		logger.Debugf("Failed to resolve DID [%s]: %s", did, err)
+
+		writeError(rw, err)
+
+		return
+	}
+
+	common.WriteResolutionResult(rw, common.ResolutionStatus(result.DocumentMetadata), mediaType,
+		&document.ResolutionResult{
+			Context:          document.DIDResolutionContext,
+			Document:         result.Document,
+			DocumentMetadata: result.DocumentMetadata,
+			DIDResolutionMetadata: document.Metadata{
+				document.ContentTypeProperty: common.DIDLDJSONMediaType,
+			},
+		},
+	)
+}
+
+// writeError writes a resolution result that contains the error code and message in the DID resolution metadata.
+func writeError(rw http.ResponseWriter, err error) {
+	common.WriteJSONResponse(rw, common.ResolutionErrorStatus(err), common.DIDResolutionMediaType,
+		&document.ResolutionResult{
+			Context:               document.DIDResolutionContext,
+			DIDResolutionMetadata: document.NewErrorMetadata(err),
+		},
+	)
+}
+
+var getID = func(req *http.Request) string {
+	return mux.Vars(req)["id"]
+}
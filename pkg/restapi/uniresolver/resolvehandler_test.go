@@ -0,0 +1,182 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package uniresolver
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/sidetree-core-go/pkg/document"
+	"github.com/trustbloc/sidetree-core-go/pkg/restapi/common"
+)
+
+const did = "did:sidetree:abc"
+
+func TestResolveHandler(t *testing.T) {
+	handler := NewResolveHandler(&mockResolver{})
+	require.Equal(t, "/1.0/identifiers/{id}", handler.Path())
+	require.Equal(t, http.MethodGet, handler.Method())
+	require.NotNil(t, handler.Handler())
+}
+
+func TestResolveHandler_Resolve(t *testing.T) {
+	doc := document.Document{document.IDProperty: did}
+
+	t.Run("success", func(t *testing.T) {
+		resolver := &mockResolver{result: &document.ResolutionResult{
+			Document:         doc,
+			DocumentMetadata: document.Metadata{document.PublishedProperty: true},
+		}}
+
+		rw := serve(t, NewResolveHandler(resolver), "/1.0/identifiers/"+did+"?versionId=2&versionTime=100",
+			`application/ld+json;profile="https://w3id.org/did-resolution"`)
+		require.Equal(t, http.StatusOK, rw.Code)
+		require.Equal(t, common.DIDResolutionMediaType, rw.Header().Get("content-type"))
+
+		result := unmarshalResult(t, rw)
+		require.Equal(t, document.DIDResolutionContext, result.Context)
+		require.Equal(t, did, result.Document.ID())
+		require.Equal(t, true, result.DocumentMetadata[document.PublishedProperty])
+		require.Equal(t, common.DIDLDJSONMediaType, result.DIDResolutionMetadata[document.ContentTypeProperty])
+
+		// resolution options are passed through
+		require.Equal(t, did, resolver.did)
+		require.Equal(t, uint64(2), resolver.opts.VersionID)
+		require.Equal(t, uint64(100), resolver.opts.VersionTime.TransactionTime)
+	})
+
+	t.Run("success - any media type", func(t *testing.T) {
+		resolver := &mockResolver{result: &document.ResolutionResult{Document: doc}}
+
+		for _, accept := range []string{"", "*/*", "text/html, application/*;q=0.5"} {
+			rw := serve(t, NewResolveHandler(resolver), "/1.0/identifiers/"+did, accept)
+			require.Equal(t, http.StatusOK, rw.Code, accept)
+			require.Equal(t, common.DIDResolutionMediaType, rw.Header().Get("content-type"))
+		}
+	})
+
+	t.Run("success - DID document representation", func(t *testing.T) {
+		resolver := &mockResolver{result: &document.ResolutionResult{Document: document.Document{
+			document.ContextProperty: []interface{}{"https://www.w3.org/ns/did/v1"},
+			document.IDProperty:      did,
+		}}}
+
+		for _, accept := range []string{common.DIDLDJSONMediaType, common.DIDJSONMediaType, common.JSONMediaType} {
+			rw := serve(t, NewResolveHandler(resolver), "/1.0/identifiers/"+did, accept)
+			require.Equal(t, http.StatusOK, rw.Code, accept)
+			require.Equal(t, accept, rw.Header().Get("content-type"))
+
+			var result document.Document
+			require.NoError(t, json.Unmarshal(rw.Body.Bytes(), &result))
+			require.Equal(t, did, result.ID())
+			require.Equal(t, accept == common.DIDLDJSONMediaType, result[document.ContextProperty] != nil)
+		}
+	})
+
+	t.Run("deactivated", func(t *testing.T) {
+		resolver := &mockResolver{result: &document.ResolutionResult{
+			Document:         document.Document{document.IDProperty: did},
+			DocumentMetadata: document.Metadata{document.DeactivatedProperty: true},
+		}}
+
+		rw := serve(t, NewResolveHandler(resolver), "/1.0/identifiers/"+did, "")
+		require.Equal(t, http.StatusGone, rw.Code)
+
+		result := unmarshalResult(t, rw)
+		require.Equal(t, true, result.DocumentMetadata[document.DeactivatedProperty])
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		tests := []struct {
+			err    error
+			status int
+			code   string
+		}{
+			{err: fmt.Errorf("%w: did", document.ErrInvalidDID), status: http.StatusBadRequest, code: document.InvalidDIDErrorCode},
+			{err: document.ErrNotFound, status: http.StatusNotFound, code: document.NotFoundErrorCode},
+			{err: document.ErrMethodNotSupported, status: http.StatusNotImplemented, code: document.MethodNotSupportedErrorCode},
+			{err: errors.New("injected error"), status: http.StatusInternalServerError, code: document.InternalErrorCode},
+		}
+
+		for _, test := range tests {
+			rw := serve(t, NewResolveHandler(&mockResolver{err: test.err}), "/1.0/identifiers/"+did, "")
+			require.Equal(t, test.status, rw.Code)
+			require.Equal(t, common.DIDResolutionMediaType, rw.Header().Get("content-type"))
+
+			result := unmarshalResult(t, rw)
+			require.Nil(t, result.Document)
+			require.Equal(t, test.code, result.DIDResolutionMetadata[document.ErrorProperty])
+			require.Equal(t, test.err.Error(), result.DIDResolutionMetadata[document.ErrorMessageProperty])
+		}
+	})
+
+	t.Run("error - invalid options", func(t *testing.T) {
+		resolver := &mockResolver{}
+
+		rw := serve(t, NewResolveHandler(resolver), "/1.0/identifiers/"+did+"?versionId=0", "")
+		require.Equal(t, http.StatusBadRequest, rw.Code)
+		require.Equal(t, document.InvalidOptionsErrorCode, unmarshalResult(t, rw).DIDResolutionMetadata[document.ErrorProperty])
+		require.Empty(t, resolver.did)
+	})
+
+	t.Run("error - representation not supported", func(t *testing.T) {
+		resolver := &mockResolver{}
+
+		rw := serve(t, NewResolveHandler(resolver), "/1.0/identifiers/"+did, "text/html")
+		require.Equal(t, http.StatusNotAcceptable, rw.Code)
+		require.Equal(t, document.RepresentationNotSupportedErrorCode,
+			unmarshalResult(t, rw).DIDResolutionMetadata[document.ErrorProperty])
+		require.Empty(t, resolver.did)
+	})
+}
+
+func serve(t *testing.T, handler *ResolveHandler, path, accept string) *httptest.ResponseRecorder {
+	t.Helper()
+
+	router := mux.NewRouter()
+	router.HandleFunc(handler.Path(), handler.Handler()).Methods(handler.Method())
+
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+
+	rw := httptest.NewRecorder()
+	router.ServeHTTP(rw, req)
+
+	return rw
+}
+
+func unmarshalResult(t *testing.T, rw *httptest.ResponseRecorder) *document.ResolutionResult {
+	t.Helper()
+
+	var result document.ResolutionResult
+	require.NoError(t, json.Unmarshal(rw.Body.Bytes(), &result))
+
+	return &result
+}
+
+type mockResolver struct {
+	result *document.ResolutionResult
+	err    error
+	did    string
+	opts   document.ResolutionOptions
+}
+
+func (m *mockResolver) ResolveDocument(did string, opts ...document.ResolutionOption) (*document.ResolutionResult, error) {
+	m.did = did
+	m.opts = document.GetResolutionOptions(opts...)
+
+	return m.result, m.err
+}
@@ -66,6 +66,13 @@ func WithKeyContext(ctx map[string]string) Option {
 	}
 }
 
+// WithTimestampProvider sets the provider that converts transaction times into document metadata timestamps.
+func WithTimestampProvider(p doctransformer.TimestampProvider) Option {
+	return func(opts *Transformer) {
+		opts.timestampProvider = p
+	}
+}
+
 // WithBase sets optional @base context.
 func WithBase(enabled bool) Option {
 	return func(opts *Transformer) {
@@ -78,6 +85,8 @@ type Transformer struct {
 	keyCtx      map[string]string
 	methodCtx   []string // used for setting additional contexts during resolution
 	includeBase bool
+
+	timestampProvider doctransformer.TimestampProvider
 }
 
 // New creates a new DID Transformer.
@@ -100,7 +109,7 @@ func New(opts ...Option) *Transformer {
 // TransformDocument takes internal resolution model and transformation info and creates
 // external representation of document (resolution result).
 func (t *Transformer) TransformDocument(rm *protocol.ResolutionModel, info protocol.TransformationInfo) (*document.ResolutionResult, error) { //nolint:funlen,gocyclo
-	docMetadata, err := doctransformer.CreateDocumentMetadata(rm, info)
+	docMetadata, err := doctransformer.CreateDocumentMetadata(rm, info, doctransformer.WithTimestampProvider(t.timestampProvider))
 	if err != nil {
 		return nil, err
 	}
@@ -22,6 +22,7 @@ import (
 	"github.com/trustbloc/sidetree-core-go/pkg/api/protocol"
 	"github.com/trustbloc/sidetree-core-go/pkg/document"
 	"github.com/trustbloc/sidetree-core-go/pkg/util/pubkey"
+	"github.com/trustbloc/sidetree-core-go/pkg/versions/1_0/doctransformer"
 )
 
 const testID = "doc:abc:123"
@@ -52,6 +53,10 @@ func TestNewTransformer(t *testing.T) {
 
 	transformer = New(WithKeyContext(keyCtx))
 	require.Equal(t, 2, len(transformer.keyCtx))
+
+	p := &doctransformer.UnixTimestampProvider{}
+	transformer = New(WithTimestampProvider(p))
+	require.Equal(t, p, transformer.timestampProvider)
 }
 
 func TestTransformDocument(t *testing.T) {
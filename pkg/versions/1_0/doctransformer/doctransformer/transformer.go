@@ -14,19 +14,37 @@ import (
 	"github.com/trustbloc/sidetree-core-go/pkg/versions/1_0/doctransformer"
 )
 
+// Option is a document transformer option.
+type Option func(opts *Transformer)
+
+// WithTimestampProvider sets the provider that converts transaction times into document metadata timestamps.
+func WithTimestampProvider(p doctransformer.TimestampProvider) Option {
+	return func(opts *Transformer) {
+		opts.timestampProvider = p
+	}
+}
+
 // Transformer is responsible for transforming internal to external document.
 type Transformer struct {
+	timestampProvider doctransformer.TimestampProvider
 }
 
 // New creates a new document transformer.
-func New() *Transformer {
-	return &Transformer{}
+func New(opts ...Option) *Transformer {
+	transformer := &Transformer{}
+
+	// apply options
+	for _, opt := range opts {
+		opt(transformer)
+	}
+
+	return transformer
 }
 
 // TransformDocument takes internal resolution model and transformation info and creates
 // external representation of document (resolution result).
 func (v *Transformer) TransformDocument(rm *protocol.ResolutionModel, info protocol.TransformationInfo) (*document.ResolutionResult, error) {
-	docMetadata, err := doctransformer.CreateDocumentMetadata(rm, info)
+	docMetadata, err := doctransformer.CreateDocumentMetadata(rm, info, doctransformer.WithTimestampProvider(v.timestampProvider))
 	if err != nil {
 		return nil, err
 	}
@@ -36,10 +54,16 @@ func (v *Transformer) TransformDocument(rm *protocol.ResolutionModel, info proto
 		return nil, errors.New("id is required for document transformation")
 	}
 
-	rm.Doc[document.IDProperty] = id
+	// the resolution model may be shared (e.g. if it was cached) so don't modify its document
+	doc := make(document.Document, len(rm.Doc)+1)
+	for key, value := range rm.Doc {
+		doc[key] = value
+	}
+
+	doc[document.IDProperty] = id
 
 	result := &document.ResolutionResult{
-		Document:         rm.Doc,
+		Document:         doc,
 		DocumentMetadata: docMetadata,
 	}
 
@@ -13,10 +13,14 @@ import (
 
 	"github.com/trustbloc/sidetree-core-go/pkg/api/protocol"
 	"github.com/trustbloc/sidetree-core-go/pkg/document"
+	"github.com/trustbloc/sidetree-core-go/pkg/versions/1_0/doctransformer"
 )
 
 func TestNewTransformer(t *testing.T) {
 	require.NotNil(t, New())
+
+	p := &doctransformer.UnixTimestampProvider{}
+	require.Equal(t, p, New(WithTimestampProvider(p)).timestampProvider)
 }
 
 func TestTransformDocument(t *testing.T) {
@@ -36,6 +40,9 @@ func TestTransformDocument(t *testing.T) {
 		require.NoError(t, err)
 		require.Equal(t, "did:abc:123", result.Document[document.IDProperty])
 
+		// the document of the resolution model is not modified
+		require.NotContains(t, internal.Doc, document.IDProperty)
+
 		methodMetadataEntry, ok := result.DocumentMetadata[document.MethodProperty]
 		require.True(t, ok)
 		methodMetadata, ok := methodMetadataEntry.(document.Metadata)
@@ -46,6 +53,21 @@ func TestTransformDocument(t *testing.T) {
 		require.Equal(t, "update", methodMetadata[document.UpdateCommitmentProperty])
 	})
 
+	t.Run("success - with timestamp provider", func(t *testing.T) {
+		transformer := New(WithTimestampProvider(&doctransformer.UnixTimestampProvider{}))
+
+		info := make(protocol.TransformationInfo)
+		info[document.IDProperty] = "did:abc:123"
+		info[document.PublishedProperty] = true
+
+		internal2 := &protocol.ResolutionModel{Doc: doc, VersionID: "version", CreatedTime: 1612173600}
+
+		result, err := transformer.TransformDocument(internal2, info)
+		require.NoError(t, err)
+		require.Equal(t, "version", result.DocumentMetadata[document.VersionIDProperty])
+		require.Equal(t, "2021-02-01T10:00:00Z", result.DocumentMetadata[document.CreatedProperty])
+	})
+
 	t.Run("success - with canonical, equivalent ID", func(t *testing.T) {
 		info := make(protocol.TransformationInfo)
 		info[document.IDProperty] = "did:abc:123"
@@ -8,13 +8,53 @@ package doctransformer
 
 import (
 	"errors"
+	"fmt"
+	"time"
 
 	"github.com/trustbloc/sidetree-core-go/pkg/api/protocol"
 	"github.com/trustbloc/sidetree-core-go/pkg/document"
 )
 
+// TimestampProvider converts the transaction time of an operation (e.g. a block number) into a timestamp.
+type TimestampProvider interface {
+	Timestamp(transactionTime uint64) (time.Time, error)
+}
+
+// UnixTimestampProvider interprets transaction times as Unix times (in seconds).
+type UnixTimestampProvider struct{}
+
+// Timestamp returns the timestamp for the given transaction time.
+func (p *UnixTimestampProvider) Timestamp(transactionTime uint64) (time.Time, error) {
+	return time.Unix(int64(transactionTime), 0), nil
+}
+
+// TransactionTime returns the transaction time for the given timestamp.
+func (p *UnixTimestampProvider) TransactionTime(t time.Time) (uint64, error) {
+	if t.Unix() < 0 {
+		return 0, errors.New("must not be before the Unix epoch")
+	}
+
+	return uint64(t.Unix()), nil
+}
+
+// MetadataOptions contains the options for creating document metadata.
+type MetadataOptions struct {
+	TimestampProvider TimestampProvider
+}
+
+// MetadataOption is an option for creating document metadata.
+type MetadataOption func(opts *MetadataOptions)
+
+// WithTimestampProvider sets the provider that converts transaction times into the timestamps of the 'created',
+// 'updated' and 'nextUpdate' document metadata properties. If not set then these properties are omitted.
+func WithTimestampProvider(p TimestampProvider) MetadataOption {
+	return func(opts *MetadataOptions) {
+		opts.TimestampProvider = p
+	}
+}
+
 // CreateDocumentMetadata will create document metadata.
-func CreateDocumentMetadata(rm *protocol.ResolutionModel, info protocol.TransformationInfo) (document.Metadata, error) { // nolint: gocyclo
+func CreateDocumentMetadata(rm *protocol.ResolutionModel, info protocol.TransformationInfo, opts ...MetadataOption) (document.Metadata, error) { // nolint: gocyclo
 	if rm == nil || rm.Doc == nil {
 		return nil, errors.New("resolution model is required for creating document metadata")
 	}
@@ -43,6 +83,10 @@ func CreateDocumentMetadata(rm *protocol.ResolutionModel, info protocol.Transfor
 		methodMetadata[document.AnchorOriginProperty] = rm.AnchorOrigin
 	}
 
+	if rm.OperationHistory != nil {
+		methodMetadata[document.OperationHistoryProperty] = rm.OperationHistory
+	}
+
 	docMetadata := make(document.Metadata)
 	docMetadata[document.MethodProperty] = methodMetadata
 
@@ -60,5 +104,53 @@ func CreateDocumentMetadata(rm *protocol.ResolutionModel, info protocol.Transfor
 		docMetadata[document.EquivalentIDProperty] = equivalentID
 	}
 
+	// version information is only available for published documents
+	if isPublished, ok := published.(bool); ok && isPublished && rm.VersionID != "" {
+		if err := addVersionMetadata(docMetadata, rm, opts...); err != nil {
+			return nil, err
+		}
+	}
+
 	return docMetadata, nil
 }
+
+func addVersionMetadata(docMetadata document.Metadata, rm *protocol.ResolutionModel, opts ...MetadataOption) error {
+	options := &MetadataOptions{}
+
+	// apply options
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	docMetadata[document.VersionIDProperty] = rm.VersionID
+
+	if rm.NextVersionID != "" {
+		docMetadata[document.NextVersionIDProperty] = rm.NextVersionID
+	}
+
+	if options.TimestampProvider == nil {
+		return nil
+	}
+
+	timestamps := map[string]uint64{document.CreatedProperty: rm.CreatedTime}
+
+	if rm.UpdatedTime > 0 {
+		timestamps[document.UpdatedProperty] = rm.UpdatedTime
+	}
+
+	if rm.NextVersionID != "" {
+		timestamps[document.NextUpdateProperty] = rm.NextUpdateTime
+	}
+
+	for property, transactionTime := range timestamps {
+		t, err := options.TimestampProvider.Timestamp(transactionTime)
+		if err != nil {
+			return fmt.Errorf("get timestamp for transaction time %d: %w", transactionTime, err)
+		}
+
+		// DID Core requires UTC timestamps without sub-second precision
+		docMetadata[property] = t.UTC().Truncate(time.Second).Format(time.RFC3339)
+	}
+
+	return nil
+}
@@ -7,7 +7,9 @@ SPDX-License-Identifier: Apache-2.0
 package doctransformer
 
 import (
+	"errors"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
@@ -43,6 +45,24 @@ func TestPopulateDocumentMetadata(t *testing.T) {
 		require.Equal(t, true, methodMetadata[document.PublishedProperty])
 		require.Equal(t, "recovery", methodMetadata[document.RecoveryCommitmentProperty])
 		require.Equal(t, "update", methodMetadata[document.UpdateCommitmentProperty])
+		require.NotContains(t, methodMetadata, document.OperationHistoryProperty)
+	})
+
+	t.Run("success - operation history", func(t *testing.T) {
+		history := []*protocol.OperationHistoryEntry{{Type: "create", Applied: true}}
+
+		internal2 := &protocol.ResolutionModel{Doc: doc, OperationHistory: history}
+
+		info := make(protocol.TransformationInfo)
+		info[document.IDProperty] = "did:abc:123"
+		info[document.PublishedProperty] = true
+
+		documentMetadata, err := CreateDocumentMetadata(internal2, info)
+		require.NoError(t, err)
+
+		methodMetadata, ok := documentMetadata[document.MethodProperty].(document.Metadata)
+		require.True(t, ok)
+		require.Equal(t, history, methodMetadata[document.OperationHistoryProperty])
 	})
 
 	t.Run("success - deactivated, commitments empty", func(t *testing.T) {
@@ -70,6 +90,65 @@ func TestPopulateDocumentMetadata(t *testing.T) {
 		require.Empty(t, methodMetadata[document.UpdateCommitmentProperty])
 	})
 
+	t.Run("success - version metadata", func(t *testing.T) {
+		internal2 := &protocol.ResolutionModel{Doc: doc, VersionID: "version", CreatedTime: 1612173600, UpdatedTime: 1612177200}
+
+		info := make(protocol.TransformationInfo)
+		info[document.IDProperty] = "did:abc:123"
+		info[document.PublishedProperty] = true
+
+		documentMetadata, err := CreateDocumentMetadata(internal2, info, WithTimestampProvider(&UnixTimestampProvider{}))
+		require.NoError(t, err)
+
+		require.Equal(t, "version", documentMetadata[document.VersionIDProperty])
+		require.Equal(t, "2021-02-01T10:00:00Z", documentMetadata[document.CreatedProperty])
+		require.Equal(t, "2021-02-01T11:00:00Z", documentMetadata[document.UpdatedProperty])
+		require.NotContains(t, documentMetadata, document.NextUpdateProperty)
+		require.NotContains(t, documentMetadata, document.NextVersionIDProperty)
+
+		// without timestamp provider
+		documentMetadata, err = CreateDocumentMetadata(internal2, info)
+		require.NoError(t, err)
+
+		require.Equal(t, "version", documentMetadata[document.VersionIDProperty])
+		require.NotContains(t, documentMetadata, document.CreatedProperty)
+		require.NotContains(t, documentMetadata, document.UpdatedProperty)
+
+		// not updated
+		internal2 = &protocol.ResolutionModel{
+			Doc: doc, VersionID: "version", CreatedTime: 1612173600, NextVersionID: "next", NextUpdateTime: 1612177200,
+		}
+
+		documentMetadata, err = CreateDocumentMetadata(internal2, info, WithTimestampProvider(&UnixTimestampProvider{}))
+		require.NoError(t, err)
+
+		require.Equal(t, "2021-02-01T10:00:00Z", documentMetadata[document.CreatedProperty])
+		require.NotContains(t, documentMetadata, document.UpdatedProperty)
+		require.Equal(t, "next", documentMetadata[document.NextVersionIDProperty])
+		require.Equal(t, "2021-02-01T11:00:00Z", documentMetadata[document.NextUpdateProperty])
+
+		// unpublished
+		info[document.PublishedProperty] = false
+
+		documentMetadata, err = CreateDocumentMetadata(internal2, info, WithTimestampProvider(&UnixTimestampProvider{}))
+		require.NoError(t, err)
+		require.NotContains(t, documentMetadata, document.VersionIDProperty)
+		require.NotContains(t, documentMetadata, document.CreatedProperty)
+	})
+
+	t.Run("error - timestamp provider", func(t *testing.T) {
+		internal2 := &protocol.ResolutionModel{Doc: doc, VersionID: "version", CreatedTime: 100}
+
+		info := make(protocol.TransformationInfo)
+		info[document.IDProperty] = "did:abc:123"
+		info[document.PublishedProperty] = true
+
+		result, err := CreateDocumentMetadata(internal2, info, WithTimestampProvider(&mockTimestampProvider{err: errors.New("injected error")}))
+		require.Error(t, err)
+		require.Nil(t, result)
+		require.Contains(t, err.Error(), "get timestamp for transaction time 100: injected error")
+	})
+
 	t.Run("error - internal document is missing", func(t *testing.T) {
 		info := make(protocol.TransformationInfo)
 		info[document.IDProperty] = "doc:abc:xyz"
@@ -98,4 +177,31 @@ func TestPopulateDocumentMetadata(t *testing.T) {
 	})
 }
 
+func TestUnixTimestampProvider(t *testing.T) {
+	p := &UnixTimestampProvider{}
+
+	ts, err := p.Timestamp(1612173600)
+	require.NoError(t, err)
+	require.Equal(t, time.Date(2021, 2, 1, 10, 0, 0, 0, time.UTC), ts.UTC())
+
+	transactionTime, err := p.TransactionTime(ts)
+	require.NoError(t, err)
+	require.Equal(t, uint64(1612173600), transactionTime)
+
+	t.Run("error - before Unix epoch", func(t *testing.T) {
+		transactionTime, err := p.TransactionTime(time.Date(1960, 1, 1, 0, 0, 0, 0, time.UTC))
+		require.Error(t, err)
+		require.Zero(t, transactionTime)
+		require.Contains(t, err.Error(), "must not be before the Unix epoch")
+	})
+}
+
 var validDoc = []byte(`{ "name": "John Smith" }`)
+
+type mockTimestampProvider struct {
+	err error
+}
+
+func (m *mockTimestampProvider) Timestamp(uint64) (time.Time, error) {
+	return time.Time{}, m.err
+}
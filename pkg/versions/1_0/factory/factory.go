@@ -0,0 +1,277 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package factory wires together the 1.0 implementations of the protocol version components
+// (operation parser, applier, handler, provider, transaction processor, document composer, validator
+// and transformer) for the given protocol parameters.
+package factory
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/trustbloc/sidetree-core-go/pkg/api/cas"
+	"github.com/trustbloc/sidetree-core-go/pkg/api/operation"
+	"github.com/trustbloc/sidetree-core-go/pkg/api/protocol"
+	"github.com/trustbloc/sidetree-core-go/pkg/compression"
+	"github.com/trustbloc/sidetree-core-go/pkg/versions/1_0/doccomposer"
+	"github.com/trustbloc/sidetree-core-go/pkg/versions/1_0/doctransformer"
+	"github.com/trustbloc/sidetree-core-go/pkg/versions/1_0/doctransformer/didtransformer"
+	"github.com/trustbloc/sidetree-core-go/pkg/versions/1_0/docvalidator/didvalidator"
+	"github.com/trustbloc/sidetree-core-go/pkg/versions/1_0/operationapplier"
+	"github.com/trustbloc/sidetree-core-go/pkg/versions/1_0/operationparser"
+	"github.com/trustbloc/sidetree-core-go/pkg/versions/1_0/txnprocessor"
+	"github.com/trustbloc/sidetree-core-go/pkg/versions/1_0/txnprovider"
+)
+
+// SupportedVersions are the protocol versions that are implemented by this factory.
+var SupportedVersions = []string{"1.0"}
+
+// OperationStore is the operation store used by the transaction processor (Put) and document validator (Get).
+type OperationStore interface {
+	Put(ops []*operation.AnchoredOperation) error
+	Get(uniqueSuffix string) ([]*operation.AnchoredOperation, error)
+}
+
+// Option is a factory option.
+type Option func(opts *Factory)
+
+// WithAnchorOriginValidator sets optional anchor origin validator for the operation parser.
+func WithAnchorOriginValidator(v operationparser.ObjectValidator) Option {
+	return func(opts *Factory) {
+		opts.anchorOriginValidator = v
+	}
+}
+
+// WithMethodContext sets optional method context(s) for the document transformer.
+func WithMethodContext(ctx []string) Option {
+	return func(opts *Factory) {
+		opts.methodCtx = ctx
+	}
+}
+
+// WithKeyContext sets optional key contexts for the document transformer.
+func WithKeyContext(ctx map[string]string) Option {
+	return func(opts *Factory) {
+		opts.keyCtx = ctx
+	}
+}
+
+// WithCompressionProvider sets optional compression provider (GZIP only registry is used by default).
+func WithCompressionProvider(cp CompressionProvider) Option {
+	return func(opts *Factory) {
+		opts.cp = cp
+	}
+}
+
+// WithOperationFilters sets optional filters that are applied by the transaction processor to the operations
+// of each unique suffix before they are persisted.
+func WithOperationFilters(filters ...protocol.OperationFilter) Option {
+	return func(opts *Factory) {
+		opts.filters = append(opts.filters, filters...)
+	}
+}
+
+// WithOperationsStoredListeners sets optional listeners that are notified by the transaction processor
+// after operations were persisted.
+func WithOperationsStoredListeners(listeners ...txnprocessor.OperationsStoredListener) Option {
+	return func(opts *Factory) {
+		opts.listeners = append(opts.listeners, listeners...)
+	}
+}
+
+// WithTimestampProvider sets an optional provider that converts transaction times into the timestamps
+// of the 'created', 'updated' and 'nextUpdate' document metadata properties.
+func WithTimestampProvider(p doctransformer.TimestampProvider) Option {
+	return func(opts *Factory) {
+		opts.timestampProvider = p
+	}
+}
+
+// WithMaxConcurrentDownloads sets the maximum number of batch files that the operation provider downloads
+// concurrently for a single transaction.
+func WithMaxConcurrentDownloads(maxDownloads int) Option {
+	return func(opts *Factory) {
+		opts.providerOpts = append(opts.providerOpts, txnprovider.WithMaxConcurrentDownloads(maxDownloads))
+	}
+}
+
+// CompressionProvider compresses and decompresses batch files.
+type CompressionProvider interface {
+	Compress(alg string, data []byte) ([]byte, error)
+	Decompress(alg string, data []byte) ([]byte, error)
+}
+
+// Factory creates 1.0 protocol versions.
+type Factory struct {
+	casClient cas.Client
+	opStore   OperationStore
+	cp        CompressionProvider
+
+	anchorOriginValidator operationparser.ObjectValidator
+	methodCtx             []string
+	keyCtx                map[string]string
+	filters               []protocol.OperationFilter
+	listeners             []txnprocessor.OperationsStoredListener
+	providerOpts          []txnprovider.Option
+	timestampProvider     doctransformer.TimestampProvider
+}
+
+// New returns a new 1.0 protocol version factory. The CAS client is used for writing and reading batch files
+// and the operation store is used for persisting anchored operations and validating operation requests.
+func New(casClient cas.Client, opStore OperationStore, opts ...Option) *Factory {
+	f := &Factory{
+		casClient: casClient,
+		opStore:   opStore,
+		cp:        compression.New(compression.WithDefaultAlgorithms()),
+	}
+
+	// apply options
+	for _, opt := range opts {
+		opt(f)
+	}
+
+	return f
+}
+
+// Create returns a fully wired 1.0 protocol version for the given version and protocol parameters.
+func (f *Factory) Create(version string, p protocol.Protocol) (protocol.Version, error) {
+	if !isSupported(version) {
+		return nil, fmt.Errorf("protocol version [%s] is not supported (supported versions: %s)",
+			version, strings.Join(SupportedVersions, ", "))
+	}
+
+	if f.casClient == nil {
+		return nil, errors.New("CAS client is required")
+	}
+
+	if f.opStore == nil {
+		return nil, errors.New("operation store is required")
+	}
+
+	parser := operationparser.New(p, operationparser.WithAnchorOriginValidator(f.anchorOriginValidator))
+	dc := doccomposer.New()
+	oa := operationapplier.New(p, parser, dc)
+	oh := txnprovider.NewOperationHandler(p, f.casClient, f.cp, parser)
+	op := txnprovider.NewOperationProvider(p, parser, f.casClient, f.cp, f.providerOpts...)
+	tp := txnprocessor.New(&txnprocessor.Providers{
+		OpStore:                   f.opStore,
+		OperationProtocolProvider: op,
+	},
+		txnprocessor.WithOperationFilters(f.filters...),
+		txnprocessor.WithOperationsStoredListeners(f.listeners...),
+	)
+	dv := didvalidator.New(f.opStore)
+
+	var transformerOpts []didtransformer.Option
+
+	if len(f.methodCtx) > 0 {
+		transformerOpts = append(transformerOpts, didtransformer.WithMethodContext(f.methodCtx))
+	}
+
+	if len(f.keyCtx) > 0 {
+		transformerOpts = append(transformerOpts, didtransformer.WithKeyContext(f.keyCtx))
+	}
+
+	if f.timestampProvider != nil {
+		transformerOpts = append(transformerOpts, didtransformer.WithTimestampProvider(f.timestampProvider))
+	}
+
+	dt := didtransformer.New(transformerOpts...)
+
+	return &vrsn{
+		version:     version,
+		protocol:    p,
+		parser:      parser,
+		applier:     oa,
+		handler:     oh,
+		provider:    op,
+		tp:          tp,
+		composer:    dc,
+		validator:   dv,
+		transformer: dt,
+	}, nil
+}
+
+// Create is a convenience function that returns a fully wired 1.0 protocol version for the given
+// protocol parameters, CAS client and operation store.
+func Create(version string, p protocol.Protocol, casClient cas.Client, opStore OperationStore, opts ...Option) (protocol.Version, error) {
+	return New(casClient, opStore, opts...).Create(version, p)
+}
+
+func isSupported(version string) bool {
+	for _, v := range SupportedVersions {
+		if v == version {
+			return true
+		}
+	}
+
+	return false
+}
+
+type vrsn struct {
+	version     string
+	protocol    protocol.Protocol
+	parser      protocol.OperationParser
+	applier     protocol.OperationApplier
+	handler     protocol.OperationHandler
+	provider    protocol.OperationProvider
+	tp          protocol.TxnProcessor
+	composer    protocol.DocumentComposer
+	validator   protocol.DocumentValidator
+	transformer protocol.DocumentTransformer
+}
+
+// Version returns the protocol version.
+func (v *vrsn) Version() string {
+	return v.version
+}
+
+// Protocol returns the protocol parameters.
+func (v *vrsn) Protocol() protocol.Protocol {
+	return v.protocol
+}
+
+// TransactionProcessor returns the transaction processor.
+func (v *vrsn) TransactionProcessor() protocol.TxnProcessor {
+	return v.tp
+}
+
+// OperationParser returns the operation parser.
+func (v *vrsn) OperationParser() protocol.OperationParser {
+	return v.parser
+}
+
+// OperationApplier returns the operation applier.
+func (v *vrsn) OperationApplier() protocol.OperationApplier {
+	return v.applier
+}
+
+// OperationHandler returns the operation handler.
+func (v *vrsn) OperationHandler() protocol.OperationHandler {
+	return v.handler
+}
+
+// OperationProvider returns the operation provider.
+func (v *vrsn) OperationProvider() protocol.OperationProvider {
+	return v.provider
+}
+
+// DocumentComposer returns the document composer.
+func (v *vrsn) DocumentComposer() protocol.DocumentComposer {
+	return v.composer
+}
+
+// DocumentValidator returns the document validator.
+func (v *vrsn) DocumentValidator() protocol.DocumentValidator {
+	return v.validator
+}
+
+// DocumentTransformer returns the document transformer.
+func (v *vrsn) DocumentTransformer() protocol.DocumentTransformer {
+	return v.transformer
+}
@@ -0,0 +1,290 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package factory
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/sidetree-core-go/pkg/api/operation"
+	"github.com/trustbloc/sidetree-core-go/pkg/api/protocol"
+	"github.com/trustbloc/sidetree-core-go/pkg/api/txn"
+	"github.com/trustbloc/sidetree-core-go/pkg/commitment"
+	"github.com/trustbloc/sidetree-core-go/pkg/compression"
+	"github.com/trustbloc/sidetree-core-go/pkg/document"
+	"github.com/trustbloc/sidetree-core-go/pkg/jws"
+	"github.com/trustbloc/sidetree-core-go/pkg/mocks"
+	"github.com/trustbloc/sidetree-core-go/pkg/opfilter"
+	"github.com/trustbloc/sidetree-core-go/pkg/protocolclient"
+	"github.com/trustbloc/sidetree-core-go/pkg/versions/1_0/client"
+	"github.com/trustbloc/sidetree-core-go/pkg/versions/1_0/doctransformer"
+)
+
+const (
+	sha2_256  = 18
+	namespace = "did:sidetree"
+	version   = "1.0"
+)
+
+func TestFactory_Create(t *testing.T) {
+	p := mocks.GetDefaultProtocolParameters()
+
+	t.Run("success", func(t *testing.T) {
+		f := New(mocks.NewMockCasClient(nil), newMockOperationStore(),
+			WithAnchorOriginValidator(nil),
+			WithMethodContext([]string{"https://example.com/method/v1"}),
+			WithKeyContext(map[string]string{"JsonWebKey2020": "https://example.com/jws/v1"}),
+			WithCompressionProvider(compression.New(compression.WithDefaultAlgorithms())),
+			WithMaxConcurrentDownloads(2),
+			WithTimestampProvider(&doctransformer.UnixTimestampProvider{}),
+		)
+
+		v, err := f.Create(version, p)
+		require.NoError(t, err)
+		require.NotNil(t, v)
+
+		require.Equal(t, version, v.Version())
+		require.Equal(t, p, v.Protocol())
+		require.NotNil(t, v.TransactionProcessor())
+		require.NotNil(t, v.OperationParser())
+		require.NotNil(t, v.OperationApplier())
+		require.NotNil(t, v.OperationHandler())
+		require.NotNil(t, v.OperationProvider())
+		require.NotNil(t, v.DocumentComposer())
+		require.NotNil(t, v.DocumentValidator())
+		require.NotNil(t, v.DocumentTransformer())
+	})
+
+	t.Run("error - unsupported version", func(t *testing.T) {
+		v, err := Create("2.0", p, mocks.NewMockCasClient(nil), newMockOperationStore())
+		require.Error(t, err)
+		require.Nil(t, v)
+		require.Contains(t, err.Error(), "protocol version [2.0] is not supported (supported versions: 1.0)")
+	})
+
+	t.Run("error - missing CAS client", func(t *testing.T) {
+		v, err := New(nil, newMockOperationStore()).Create(version, p)
+		require.Error(t, err)
+		require.Nil(t, v)
+		require.Contains(t, err.Error(), "CAS client is required")
+	})
+
+	t.Run("error - missing operation store", func(t *testing.T) {
+		v, err := Create(version, p, mocks.NewMockCasClient(nil), nil)
+		require.Error(t, err)
+		require.Nil(t, v)
+		require.Contains(t, err.Error(), "operation store is required")
+	})
+}
+
+func TestFactory_ProtocolClient(t *testing.T) {
+	p1 := mocks.GetDefaultProtocolParameters()
+
+	p2 := mocks.GetDefaultProtocolParameters()
+	p2.GenesisTime = 100
+	p2.MaxOperationCount = 10
+
+	pc, err := protocolclient.NewFromParameterSets(New(mocks.NewMockCasClient(nil), newMockOperationStore()),
+		[]*protocolclient.ParameterSet{
+			{Version: "1.0", Protocol: p1},
+			{Version: "1.0", Protocol: p2},
+		},
+	)
+	require.NoError(t, err)
+
+	v, err := pc.Get(99)
+	require.NoError(t, err)
+	require.Equal(t, "1.0", v.Version())
+	require.Equal(t, p1.MaxOperationCount, v.Protocol().MaxOperationCount)
+
+	v, err = pc.Current()
+	require.NoError(t, err)
+	require.Equal(t, "1.0", v.Version())
+	require.Equal(t, uint(10), v.Protocol().MaxOperationCount)
+
+	_, err = protocolclient.NewFromParameterSets(New(mocks.NewMockCasClient(nil), newMockOperationStore()),
+		[]*protocolclient.ParameterSet{{Version: "1.1", Protocol: p2}},
+	)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "protocol version [1.1] is not supported")
+}
+
+func TestVersion_EndToEnd(t *testing.T) {
+	p := mocks.GetDefaultProtocolParameters()
+	store := newMockOperationStore()
+
+	v, err := Create(version, p, mocks.NewMockCasClient(nil), store,
+		WithMethodContext([]string{"https://example.com/method/v1"}), WithMaxConcurrentDownloads(1))
+	require.NoError(t, err)
+
+	createRequest := newCreateRequest(t)
+
+	op, err := v.OperationParser().Parse(namespace, createRequest)
+	require.NoError(t, err)
+	require.Equal(t, operation.TypeCreate, op.Type)
+
+	anchorString, artifacts, refs, err := v.OperationHandler().PrepareTxnFiles([]*operation.QueuedOperation{
+		{Namespace: namespace, UniqueSuffix: op.UniqueSuffix, OperationBuffer: op.OperationBuffer},
+	})
+	require.NoError(t, err)
+	require.NotEmpty(t, anchorString)
+	require.NotEmpty(t, artifacts)
+	require.Len(t, refs, 1)
+
+	err = v.TransactionProcessor().Process(txn.SidetreeTxn{
+		Namespace:         namespace,
+		AnchorString:      anchorString,
+		TransactionTime:   1,
+		TransactionNumber: 1,
+	})
+	require.NoError(t, err)
+
+	ops, err := store.Get(op.UniqueSuffix)
+	require.NoError(t, err)
+	require.Len(t, ops, 1)
+
+	rm, err := v.OperationApplier().Apply(ops[0], &protocol.ResolutionModel{})
+	require.NoError(t, err)
+	require.NotEmpty(t, rm.UpdateCommitment)
+
+	result, err := v.DocumentTransformer().TransformDocument(rm, protocol.TransformationInfo{
+		document.IDProperty:        namespace + ":" + op.UniqueSuffix,
+		document.PublishedProperty: true,
+	})
+	require.NoError(t, err)
+	require.Equal(t, namespace+":"+op.UniqueSuffix, result.Document.ID())
+	require.Contains(t, result.Document.Context(), "https://example.com/method/v1")
+}
+
+func TestVersion_OperationFilters(t *testing.T) {
+	p := mocks.GetDefaultProtocolParameters()
+	casClient := mocks.NewMockCasClient(nil)
+
+	newTxn := func(t *testing.T, v protocol.Version, txnNumber uint64) (txn.SidetreeTxn, string) {
+		t.Helper()
+
+		op, err := v.OperationParser().Parse(namespace, newCreateRequest(t))
+		require.NoError(t, err)
+
+		anchorString, _, _, err := v.OperationHandler().PrepareTxnFiles([]*operation.QueuedOperation{
+			{Namespace: namespace, UniqueSuffix: op.UniqueSuffix, OperationBuffer: op.OperationBuffer},
+		})
+		require.NoError(t, err)
+
+		return txn.SidetreeTxn{
+			Namespace:         namespace,
+			AnchorString:      anchorString,
+			TransactionTime:   txnNumber,
+			TransactionNumber: txnNumber,
+		}, op.UniqueSuffix
+	}
+
+	t.Run("duplicate filter", func(t *testing.T) {
+		store := newMockOperationStore()
+
+		v, err := Create(version, p, casClient, store, WithOperationFilters(opfilter.NewDuplicateFilter(store)))
+		require.NoError(t, err)
+
+		sidetreeTxn, suffix := newTxn(t, v, 1)
+
+		require.NoError(t, v.TransactionProcessor().Process(sidetreeTxn))
+
+		sidetreeTxn.TransactionNumber = 2
+		require.NoError(t, v.TransactionProcessor().Process(sidetreeTxn))
+
+		ops, err := store.Get(suffix)
+		require.NoError(t, err)
+		require.Len(t, ops, 1)
+	})
+
+	t.Run("allow-list filter", func(t *testing.T) {
+		store := newMockOperationStore()
+
+		v, err := Create(version, p, casClient, store, WithOperationFilters(opfilter.NewAllowListFilter("other")))
+		require.NoError(t, err)
+
+		sidetreeTxn, suffix := newTxn(t, v, 1)
+
+		require.NoError(t, v.TransactionProcessor().Process(sidetreeTxn))
+
+		ops, err := store.Get(suffix)
+		require.NoError(t, err)
+		require.Empty(t, ops)
+	})
+
+	t.Run("operations stored listener", func(t *testing.T) {
+		store := newMockOperationStore()
+		l := &mockListener{}
+
+		v, err := Create(version, p, casClient, store, WithOperationsStoredListeners(l))
+		require.NoError(t, err)
+
+		sidetreeTxn, suffix := newTxn(t, v, 1)
+
+		require.NoError(t, v.TransactionProcessor().Process(sidetreeTxn))
+		require.Equal(t, []string{suffix}, l.suffixes)
+	})
+}
+
+type mockListener struct {
+	suffixes []string
+}
+
+func (m *mockListener) OperationsStored(ops []*operation.AnchoredOperation) {
+	for _, op := range ops {
+		m.suffixes = append(m.suffixes, op.UniqueSuffix)
+	}
+}
+
+func newCreateRequest(t *testing.T) []byte {
+	t.Helper()
+
+	updateCommitment, err := commitment.GetCommitment(&jws.JWK{Crv: "crv", Kty: "kty", X: "x"}, sha2_256)
+	require.NoError(t, err)
+
+	recoveryCommitment, err := commitment.GetCommitment(&jws.JWK{Crv: "crv", Kty: "kty", X: "x", Y: "y"}, sha2_256)
+	require.NoError(t, err)
+
+	request, err := client.NewCreateRequest(&client.CreateRequestInfo{
+		OpaqueDocument:     `{"test":1}`,
+		RecoveryCommitment: recoveryCommitment,
+		UpdateCommitment:   updateCommitment,
+		MultihashCode:      sha2_256,
+	})
+	require.NoError(t, err)
+
+	return request
+}
+
+type mockOperationStore struct {
+	mutex      sync.RWMutex
+	operations map[string][]*operation.AnchoredOperation
+}
+
+func newMockOperationStore() *mockOperationStore {
+	return &mockOperationStore{operations: make(map[string][]*operation.AnchoredOperation)}
+}
+
+func (m *mockOperationStore) Put(ops []*operation.AnchoredOperation) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	for _, op := range ops {
+		m.operations[op.UniqueSuffix] = append(m.operations[op.UniqueSuffix], op)
+	}
+
+	return nil
+}
+
+func (m *mockOperationStore) Get(uniqueSuffix string) ([]*operation.AnchoredOperation, error) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	return m.operations[uniqueSuffix], nil
+}
@@ -7,6 +7,7 @@ SPDX-License-Identifier: Apache-2.0
 package txnprocessor
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/pkg/errors"
@@ -24,6 +25,30 @@ type OperationStore interface {
 	Put(ops []*operation.AnchoredOperation) error
 }
 
+// OperationsStoredListener is notified after operations were persisted to the operation store.
+type OperationsStoredListener interface {
+	OperationsStored(ops []*operation.AnchoredOperation)
+}
+
+// Option is a transaction processor option.
+type Option func(opts *TxnProcessor)
+
+// WithOperationFilters sets the filters that are applied (in the given order) to the operations
+// of each unique suffix before they are persisted.
+func WithOperationFilters(filters ...protocol.OperationFilter) Option {
+	return func(opts *TxnProcessor) {
+		opts.filters = append(opts.filters, filters...)
+	}
+}
+
+// WithOperationsStoredListeners sets the listeners that are notified after operations were persisted
+// (e.g. to invalidate cached resolution results for the affected unique suffixes).
+func WithOperationsStoredListeners(listeners ...OperationsStoredListener) Option {
+	return func(opts *TxnProcessor) {
+		opts.listeners = append(opts.listeners, listeners...)
+	}
+}
+
 // Providers contains the providers required by the TxnProcessor.
 type Providers struct {
 	OpStore                   OperationStore
@@ -33,20 +58,31 @@ type Providers struct {
 // TxnProcessor processes Sidetree transactions by persisting them to an operation store.
 type TxnProcessor struct {
 	*Providers
+
+	filters   []protocol.OperationFilter
+	listeners []OperationsStoredListener
 }
 
 // New returns a new document operation processor.
-func New(providers *Providers) *TxnProcessor {
-	return &TxnProcessor{
+func New(providers *Providers, opts ...Option) *TxnProcessor {
+	p := &TxnProcessor{
 		Providers: providers,
 	}
+
+	// apply options
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	return p
 }
 
-// Process persists all of the operations for the given anchor.
+// Process persists the operations for the given anchor. If suffixes are provided then only the operations
+// for those suffixes are persisted (the operation provider only returns the operations for those suffixes).
 func (p *TxnProcessor) Process(sidetreeTxn txn.SidetreeTxn, suffixes ...string) error {
 	logger.Debugf("processing sidetree txn:%+v, suffixes: %s", sidetreeTxn, suffixes)
 
-	txnOps, err := p.OperationProtocolProvider.GetTxnOperations(&sidetreeTxn)
+	txnOps, err := p.OperationProtocolProvider.GetTxnOperations(context.Background(), &sidetreeTxn, suffixes...)
 	if err != nil {
 		return fmt.Errorf("failed to retrieve operations for anchor string[%s]: %s", sidetreeTxn.AnchorString, err)
 	}
@@ -76,14 +112,73 @@ func (p *TxnProcessor) processTxnOperations(txnOps []*operation.AnchoredOperatio
 		batchSuffixes[op.UniqueSuffix] = true
 	}
 
-	err := p.OpStore.Put(ops)
+	ops, err := p.filter(ops)
+	if err != nil {
+		return errors.Wrapf(err, "failed to filter operations from anchor string[%s]", sidetreeTxn.AnchorString)
+	}
+
+	if len(ops) == 0 {
+		logger.Debugf("[%s] no operations to store from anchor string[%s]", sidetreeTxn.Namespace, sidetreeTxn.AnchorString)
+
+		return nil
+	}
+
+	err = p.OpStore.Put(ops)
 	if err != nil {
 		return errors.Wrapf(err, "failed to store operation from anchor string[%s]", sidetreeTxn.AnchorString)
 	}
 
+	for _, l := range p.listeners {
+		l.OperationsStored(ops)
+	}
+
 	return nil
 }
 
+// filter runs the operation filters for each unique suffix and returns the remaining operations.
+func (p *TxnProcessor) filter(ops []*operation.AnchoredOperation) ([]*operation.AnchoredOperation, error) {
+	if len(p.filters) == 0 {
+		return ops, nil
+	}
+
+	var suffixes []string
+
+	opsBySuffix := make(map[string][]*operation.AnchoredOperation)
+
+	for _, op := range ops {
+		if _, ok := opsBySuffix[op.UniqueSuffix]; !ok {
+			suffixes = append(suffixes, op.UniqueSuffix)
+		}
+
+		opsBySuffix[op.UniqueSuffix] = append(opsBySuffix[op.UniqueSuffix], op)
+	}
+
+	var filtered []*operation.AnchoredOperation
+
+	for _, suffix := range suffixes {
+		suffixOps := opsBySuffix[suffix]
+
+		for _, f := range p.filters {
+			var err error
+
+			suffixOps, err = f.Filter(suffix, suffixOps)
+			if err != nil {
+				return nil, fmt.Errorf("filter operations for suffix[%s]: %w", suffix, err)
+			}
+
+			if len(suffixOps) == 0 {
+				logger.Debugf("operations for suffix[%s] were filtered out", suffix)
+
+				break
+			}
+		}
+
+		filtered = append(filtered, suffixOps...)
+	}
+
+	return filtered, nil
+}
+
 func updateAnchoredOperation(op *operation.AnchoredOperation, sidetreeTxn txn.SidetreeTxn) *operation.AnchoredOperation {
 	//  The logical anchoring time that this operation was anchored on
 	op.TransactionTime = sidetreeTxn.TransactionTime
@@ -7,6 +7,7 @@ SPDX-License-Identifier: Apache-2.0
 package txnprocessor
 
 import (
+	"context"
 	"fmt"
 	"testing"
 
@@ -14,6 +15,7 @@ import (
 
 	"github.com/trustbloc/sidetree-core-go/pkg/api/operation"
 	"github.com/trustbloc/sidetree-core-go/pkg/api/txn"
+	"github.com/trustbloc/sidetree-core-go/pkg/mocks"
 )
 
 const anchorString = "1.coreIndexURI"
@@ -36,6 +38,37 @@ func TestTxnProcessor_Process(t *testing.T) {
 		require.Error(t, err)
 		require.Contains(t, err.Error(), errExpected.Error())
 	})
+
+	t.Run("suffixes are passed to the operation provider", func(t *testing.T) {
+		// the operation provider only returns the operations for the given suffixes
+		opp := &mocks.OperationProvider{}
+		opp.GetTxnOperationsReturns([]*operation.AnchoredOperation{
+			{UniqueSuffix: "def"}, {UniqueSuffix: "ghi"},
+		}, nil)
+
+		var stored []*operation.AnchoredOperation
+
+		providers := &Providers{
+			OpStore: &mockOperationStore{putFunc: func(ops []*operation.AnchoredOperation) error {
+				stored = ops
+
+				return nil
+			}},
+			OperationProtocolProvider: opp,
+		}
+
+		p := New(providers)
+		err := p.Process(txn.SidetreeTxn{AnchorString: anchorString}, "def", "ghi")
+		require.NoError(t, err)
+
+		require.Len(t, stored, 2)
+		require.Equal(t, "def", stored[0].UniqueSuffix)
+		require.Equal(t, "ghi", stored[1].UniqueSuffix)
+
+		_, sidetreeTxn, suffixes := opp.GetTxnOperationsArgsForCall(0)
+		require.Equal(t, anchorString, sidetreeTxn.AnchorString)
+		require.Equal(t, []string{"def", "ghi"}, suffixes)
+	})
 }
 
 func TestProcessTxnOperations(t *testing.T) {
@@ -59,7 +92,7 @@ func TestProcessTxnOperations(t *testing.T) {
 		}
 
 		p := New(providers)
-		batchOps, err := p.OperationProtocolProvider.GetTxnOperations(&txn.SidetreeTxn{AnchorString: anchorString})
+		batchOps, err := p.OperationProtocolProvider.GetTxnOperations(context.Background(), &txn.SidetreeTxn{AnchorString: anchorString})
 		require.NoError(t, err)
 
 		err = p.processTxnOperations(batchOps, txn.SidetreeTxn{AnchorString: anchorString})
@@ -73,7 +106,7 @@ func TestProcessTxnOperations(t *testing.T) {
 		}
 
 		p := New(providers)
-		batchOps, err := p.OperationProtocolProvider.GetTxnOperations(&txn.SidetreeTxn{AnchorString: anchorString})
+		batchOps, err := p.OperationProtocolProvider.GetTxnOperations(context.Background(), &txn.SidetreeTxn{AnchorString: anchorString})
 		require.NoError(t, err)
 
 		// add same operations again to create scenario where batch has multiple operations with same suffix
@@ -85,6 +118,128 @@ func TestProcessTxnOperations(t *testing.T) {
 	})
 }
 
+func TestProcessTxnOperations_Filters(t *testing.T) {
+	ops := []*operation.AnchoredOperation{
+		{UniqueSuffix: "abc", Type: operation.TypeCreate},
+		{UniqueSuffix: "def", Type: operation.TypeUpdate},
+		{UniqueSuffix: "ghi", Type: operation.TypeRecover},
+	}
+
+	t.Run("success", func(t *testing.T) {
+		var stored []*operation.AnchoredOperation
+
+		var filtered []string
+
+		store := &mockOperationStore{putFunc: func(ops []*operation.AnchoredOperation) error {
+			stored = ops
+
+			return nil
+		}}
+
+		p := New(&Providers{OpStore: store},
+			WithOperationFilters(
+				&mockFilter{filterFunc: func(suffix string, ops []*operation.AnchoredOperation) ([]*operation.AnchoredOperation, error) {
+					filtered = append(filtered, suffix)
+
+					if suffix == "def" {
+						return nil, nil
+					}
+
+					return ops, nil
+				}},
+				&mockFilter{filterFunc: func(suffix string, ops []*operation.AnchoredOperation) ([]*operation.AnchoredOperation, error) {
+					filtered = append(filtered, suffix)
+
+					return ops, nil
+				}},
+			),
+		)
+
+		err := p.processTxnOperations(ops, txn.SidetreeTxn{AnchorString: anchorString})
+		require.NoError(t, err)
+
+		// the second filter isn't invoked for suffix "def" since all of its operations were filtered out
+		require.Equal(t, []string{"abc", "abc", "def", "ghi", "ghi"}, filtered)
+
+		require.Len(t, stored, 2)
+		require.Equal(t, "abc", stored[0].UniqueSuffix)
+		require.Equal(t, "ghi", stored[1].UniqueSuffix)
+	})
+
+	t.Run("all operations filtered out", func(t *testing.T) {
+		store := &mockOperationStore{putFunc: func(ops []*operation.AnchoredOperation) error {
+			return fmt.Errorf("put should not be called")
+		}}
+
+		p := New(&Providers{OpStore: store},
+			WithOperationFilters(&mockFilter{filterFunc: func(string, []*operation.AnchoredOperation) ([]*operation.AnchoredOperation, error) {
+				return nil, nil
+			}}),
+		)
+
+		err := p.processTxnOperations(ops, txn.SidetreeTxn{AnchorString: anchorString})
+		require.NoError(t, err)
+	})
+
+	t.Run("filter error", func(t *testing.T) {
+		p := New(&Providers{OpStore: &mockOperationStore{}},
+			WithOperationFilters(&mockFilter{filterFunc: func(string, []*operation.AnchoredOperation) ([]*operation.AnchoredOperation, error) {
+				return nil, fmt.Errorf("injected filter error")
+			}}),
+		)
+
+		err := p.processTxnOperations(ops, txn.SidetreeTxn{AnchorString: anchorString})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to filter operations from anchor string")
+		require.Contains(t, err.Error(), "filter operations for suffix[abc]: injected filter error")
+	})
+}
+
+func TestProcessTxnOperations_Listeners(t *testing.T) {
+	ops := []*operation.AnchoredOperation{
+		{UniqueSuffix: "abc", Type: operation.TypeCreate},
+		{UniqueSuffix: "def", Type: operation.TypeUpdate},
+	}
+
+	t.Run("success", func(t *testing.T) {
+		l1 := &mockListener{}
+		l2 := &mockListener{}
+
+		p := New(&Providers{OpStore: &mockOperationStore{}},
+			WithOperationFilters(&mockFilter{filterFunc: func(suffix string, ops []*operation.AnchoredOperation) ([]*operation.AnchoredOperation, error) {
+				if suffix == "def" {
+					return nil, nil
+				}
+
+				return ops, nil
+			}}),
+			WithOperationsStoredListeners(l1, l2),
+		)
+
+		err := p.processTxnOperations(ops, txn.SidetreeTxn{AnchorString: anchorString})
+		require.NoError(t, err)
+
+		// listeners are only notified about stored operations
+		for _, l := range []*mockListener{l1, l2} {
+			require.Len(t, l.ops, 1)
+			require.Equal(t, "abc", l.ops[0].UniqueSuffix)
+		}
+	})
+
+	t.Run("store error", func(t *testing.T) {
+		l := &mockListener{}
+
+		p := New(&Providers{OpStore: &mockOperationStore{putFunc: func(ops []*operation.AnchoredOperation) error {
+			return fmt.Errorf("injected store error")
+		}}}, WithOperationsStoredListeners(l))
+
+		err := p.processTxnOperations(ops, txn.SidetreeTxn{AnchorString: anchorString})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "injected store error")
+		require.Empty(t, l.ops)
+	})
+}
+
 func TestUpdateOperation(t *testing.T) {
 	t.Run("test success", func(t *testing.T) {
 		updatedOps := updateAnchoredOperation(&operation.AnchoredOperation{UniqueSuffix: "abc"},
@@ -115,11 +270,27 @@ func (m *mockOperationStore) Get(suffix string) ([]*operation.AnchoredOperation,
 	return nil, nil
 }
 
+type mockFilter struct {
+	filterFunc func(suffix string, ops []*operation.AnchoredOperation) ([]*operation.AnchoredOperation, error)
+}
+
+func (m *mockFilter) Filter(suffix string, ops []*operation.AnchoredOperation) ([]*operation.AnchoredOperation, error) {
+	return m.filterFunc(suffix, ops)
+}
+
+type mockListener struct {
+	ops []*operation.AnchoredOperation
+}
+
+func (m *mockListener) OperationsStored(ops []*operation.AnchoredOperation) {
+	m.ops = append(m.ops, ops...)
+}
+
 type mockTxnOpsProvider struct {
 	err error
 }
 
-func (m *mockTxnOpsProvider) GetTxnOperations(txn *txn.SidetreeTxn) ([]*operation.AnchoredOperation, error) {
+func (m *mockTxnOpsProvider) GetTxnOperations(_ context.Context, txn *txn.SidetreeTxn, suffixes ...string) ([]*operation.AnchoredOperation, error) {
 	if m.err != nil {
 		return nil, m.err
 	}
@@ -0,0 +1,79 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package txnprovider
+
+import (
+	"context"
+	"sync"
+)
+
+// downloadGroup runs download tasks concurrently using a bounded number of workers. The first task that
+// fails cancels the context of the group so that pending tasks are not started. A task may add further
+// tasks to the group (e.g. for files that are referenced by the downloaded file).
+type downloadGroup struct {
+	ctx     context.Context
+	cancel  context.CancelFunc
+	workers chan struct{}
+	wg      sync.WaitGroup
+	errOnce sync.Once
+	err     error
+}
+
+func newDownloadGroup(ctx context.Context, maxWorkers int) *downloadGroup {
+	ctx, cancel := context.WithCancel(ctx)
+
+	return &downloadGroup{
+		ctx:     ctx,
+		cancel:  cancel,
+		workers: make(chan struct{}, maxWorkers),
+	}
+}
+
+// run runs the given task as soon as a worker is available. The task is not run if the group was cancelled.
+func (g *downloadGroup) run(task func() error) {
+	g.wg.Add(1)
+
+	go func() {
+		defer g.wg.Done()
+
+		select {
+		case g.workers <- struct{}{}:
+		case <-g.ctx.Done():
+			g.setError(g.ctx.Err())
+
+			return
+		}
+
+		defer func() { <-g.workers }()
+
+		if err := g.ctx.Err(); err != nil {
+			g.setError(err)
+
+			return
+		}
+
+		if err := task(); err != nil {
+			g.setError(err)
+		}
+	}()
+}
+
+// wait waits for all tasks (including tasks that were added by other tasks) to complete and returns
+// the first error.
+func (g *downloadGroup) wait() error {
+	g.wg.Wait()
+	g.cancel()
+
+	return g.err
+}
+
+func (g *downloadGroup) setError(err error) {
+	g.errOnce.Do(func() {
+		g.err = err
+		g.cancel()
+	})
+}
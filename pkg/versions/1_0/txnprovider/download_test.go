@@ -0,0 +1,86 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package txnprovider
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDownloadGroup(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		g := newDownloadGroup(context.Background(), 2)
+
+		var count int32
+
+		for i := 0; i < 5; i++ {
+			g.run(func() error {
+				atomic.AddInt32(&count, 1)
+
+				// tasks may add further tasks
+				g.run(func() error {
+					atomic.AddInt32(&count, 1)
+
+					return nil
+				})
+
+				return nil
+			})
+		}
+
+		require.NoError(t, g.wait())
+		require.Equal(t, int32(10), count)
+	})
+
+	t.Run("first error cancels pending tasks", func(t *testing.T) {
+		g := newDownloadGroup(context.Background(), 1)
+
+		started := make(chan struct{})
+		release := make(chan struct{})
+
+		g.run(func() error {
+			close(started)
+			<-release
+
+			return errors.New("download error")
+		})
+
+		<-started
+
+		var count int32
+
+		for i := 0; i < 5; i++ {
+			g.run(func() error {
+				atomic.AddInt32(&count, 1)
+
+				return errors.New("other error")
+			})
+		}
+
+		close(release)
+
+		require.EqualError(t, g.wait(), "download error")
+		require.Zero(t, atomic.LoadInt32(&count))
+	})
+
+	t.Run("parent context cancelled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		g := newDownloadGroup(ctx, 1)
+
+		g.run(func() error {
+			return nil
+		})
+
+		require.True(t, errors.Is(g.wait(), context.Canceled))
+	})
+}
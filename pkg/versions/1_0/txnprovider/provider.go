@@ -7,11 +7,16 @@ SPDX-License-Identifier: Apache-2.0
 package txnprovider
 
 import (
+	"bytes"
+	"context"
 	"fmt"
+	"io"
+	"io/ioutil"
 
 	"github.com/pkg/errors"
 	"github.com/trustbloc/edge-core/pkg/log"
 
+	"github.com/trustbloc/sidetree-core-go/pkg/api/cas"
 	"github.com/trustbloc/sidetree-core-go/pkg/api/operation"
 	"github.com/trustbloc/sidetree-core-go/pkg/api/protocol"
 	"github.com/trustbloc/sidetree-core-go/pkg/api/txn"
@@ -21,6 +26,8 @@ import (
 
 var logger = log.New("sidetree-core-txnhandler")
 
+const defaultMaxConcurrentDownloads = 4
+
 // DCAS interface to access content addressable storage.
 type DCAS interface {
 	Read(key string) ([]byte, error)
@@ -30,12 +37,29 @@ type decompressionProvider interface {
 	Decompress(alg string, data []byte) ([]byte, error)
 }
 
+// streamingDecompressionProvider is implemented by decompression providers that support streaming decompression.
+type streamingDecompressionProvider interface {
+	NewDecompressionReader(alg string, reader io.Reader) (io.ReadCloser, error)
+}
+
 // OperationProvider is an operation provider.
 type OperationProvider struct {
 	protocol.Protocol
 	parser OperationParser
 	cas    DCAS
 	dp     decompressionProvider
+
+	maxConcurrentDownloads int
+}
+
+// Option is an operation provider option.
+type Option func(opts *OperationProvider)
+
+// WithMaxConcurrentDownloads sets the maximum number of batch files that are downloaded concurrently.
+func WithMaxConcurrentDownloads(maxDownloads int) Option {
+	return func(opts *OperationProvider) {
+		opts.maxConcurrentDownloads = maxDownloads
+	}
 }
 
 // OperationParser defines the functions for parsing operations.
@@ -49,30 +73,49 @@ type OperationParser interface {
 }
 
 // NewOperationProvider returns a new operation provider.
-func NewOperationProvider(p protocol.Protocol, parser OperationParser, cas DCAS, dp decompressionProvider) *OperationProvider {
-	return &OperationProvider{
-		Protocol: p,
-		parser:   parser,
-		cas:      cas,
-		dp:       dp,
+func NewOperationProvider(p protocol.Protocol, parser OperationParser, cas DCAS, dp decompressionProvider,
+	opts ...Option) *OperationProvider {
+	h := &OperationProvider{
+		Protocol:               p,
+		parser:                 parser,
+		cas:                    cas,
+		dp:                     dp,
+		maxConcurrentDownloads: defaultMaxConcurrentDownloads,
 	}
+
+	// apply options
+	for _, opt := range opts {
+		opt(h)
+	}
+
+	if h.maxConcurrentDownloads < 1 {
+		h.maxConcurrentDownloads = 1
+	}
+
+	return h
 }
 
 // GetTxnOperations will read batch files(core/provisional index, proof files and chunk file)
-// and assemble batch operations from those files.
-func (h *OperationProvider) GetTxnOperations(txn *txn.SidetreeTxn) ([]*operation.AnchoredOperation, error) {
+// and assemble batch operations from those files. If suffixes are provided then only the operations
+// for those suffixes are returned and the proof and chunk files are only read if they are required for
+// the operations of the given suffixes. (The index files are always read since they are needed for
+// determining which operations are contained in the batch.)
+//
+// Downloads are aborted when the given context is done (streaming reads are aborted while in progress).
+func (h *OperationProvider) GetTxnOperations(ctx context.Context, txn *txn.SidetreeTxn,
+	suffixes ...string) ([]*operation.AnchoredOperation, error) {
 	// parse core index file URI and number of operations from anchor string
 	anchorData, err := ParseAnchorData(txn.AnchorString)
 	if err != nil {
 		return nil, err
 	}
 
-	cif, err := h.getCoreIndexFile(anchorData.CoreIndexFileURI)
+	cif, err := h.getCoreIndexFile(ctx, anchorData.CoreIndexFileURI)
 	if err != nil {
 		return nil, err
 	}
 
-	batchFiles, err := h.getBatchFiles(cif)
+	batchFiles, err := h.getBatchFiles(ctx, cif, suffixes...)
 	if err != nil {
 		return nil, err
 	}
@@ -86,7 +129,55 @@ func (h *OperationProvider) GetTxnOperations(txn *txn.SidetreeTxn) ([]*operation
 		return nil, fmt.Errorf("number of txn ops[%d] doesn't match anchor string num of ops[%d]", len(txnOps), anchorData.NumberOfOperations)
 	}
 
-	return txnOps, nil
+	return newSuffixFilter(suffixes).filter(txnOps), nil
+}
+
+// suffixFilter contains the suffixes of the requested operations. A nil filter matches all suffixes.
+type suffixFilter map[string]struct{}
+
+func newSuffixFilter(suffixes []string) suffixFilter {
+	if len(suffixes) == 0 {
+		return nil
+	}
+
+	f := make(suffixFilter, len(suffixes))
+	for _, suffix := range suffixes {
+		f[suffix] = struct{}{}
+	}
+
+	return f
+}
+
+// matchesAny returns true if any of the given suffixes was requested.
+func (f suffixFilter) matchesAny(suffixes []string) bool {
+	if f == nil {
+		return true
+	}
+
+	for _, suffix := range suffixes {
+		if _, ok := f[suffix]; ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+// filter returns the operations for the requested suffixes.
+func (f suffixFilter) filter(ops []*operation.AnchoredOperation) []*operation.AnchoredOperation {
+	if f == nil {
+		return ops
+	}
+
+	var filtered []*operation.AnchoredOperation
+
+	for _, op := range ops {
+		if _, ok := f[op.UniqueSuffix]; ok {
+			filtered = append(filtered, op)
+		}
+	}
+
+	return filtered
 }
 
 // batchFiles contains the content of all batch files that are referenced in core index file.
@@ -98,76 +189,210 @@ type batchFiles struct {
 	Chunk            *models.ChunkFile
 }
 
-type provisionalFiles struct {
-	ProvisionalIndex *models.ProvisionalIndexFile
-	ProvisionalProof *models.ProvisionalProofFile
-	Chunk            *models.ChunkFile
-}
+// getBatchFiles retrieves the batch files that are referenced in core index file. If suffixes are provided then
+// proof and chunk files are only retrieved if they contain data for the operations of the given suffixes.
+// Files that don't depend on each other are downloaded concurrently. The first failed download (or the given
+// context being done) aborts all other downloads.
+func (h *OperationProvider) getBatchFiles(ctx context.Context, cif *models.CoreIndexFile, suffixes ...string) (*batchFiles, error) {
+	filter := newSuffixFilter(suffixes)
 
-// getBatchFiles retrieves all batch files that are referenced in core index file.
-func (h *OperationProvider) getBatchFiles(cif *models.CoreIndexFile) (*batchFiles, error) {
-	var err error
+	var coreDeltaSuffixes []string
 
-	files := &batchFiles{CoreIndex: cif}
+	if filter != nil && cif.ProvisionalIndexFileURI != "" {
+		var err error
 
-	// core proof file will not exist if we have only update operations in the batch
-	if cif.CoreProofFileURI != "" {
-		files.CoreProof, err = h.getCoreProofFile(cif.CoreProofFileURI)
+		coreDeltaSuffixes, err = h.getCoreDeltaSuffixes(cif)
 		if err != nil {
 			return nil, err
 		}
 	}
 
+	files := &batchFiles{CoreIndex: cif}
+
+	// core proof file will not exist if we have only update operations in the batch
+	readCoreProof := cif.CoreProofFileURI != "" && filter.matchesAny(getCoreProofSuffixes(cif))
+
+	h.prefetch(cif.ProvisionalIndexFileURI)
+
+	if readCoreProof {
+		h.prefetch(cif.CoreProofFileURI)
+	}
+
+	g := newDownloadGroup(ctx, h.maxConcurrentDownloads)
+
+	if readCoreProof {
+		g.run(func() error {
+			cpf, err := h.getCoreProofFile(g.ctx, cif.CoreProofFileURI)
+			if err != nil {
+				return err
+			}
+
+			files.CoreProof = cpf
+
+			return nil
+		})
+	}
+
 	if cif.ProvisionalIndexFileURI != "" {
-		provisionalFiles, innerErr := h.getProvisionalFiles(cif.ProvisionalIndexFileURI)
-		if innerErr != nil {
-			return nil, innerErr
-		}
+		g.run(func() error {
+			return h.getProvisionalFiles(g, files, cif.ProvisionalIndexFileURI, filter, coreDeltaSuffixes)
+		})
+	}
 
-		files.ProvisionalIndex = provisionalFiles.ProvisionalIndex
-		files.ProvisionalProof = provisionalFiles.ProvisionalProof
-		files.Chunk = provisionalFiles.Chunk
+	if err := g.wait(); err != nil {
+		return nil, err
 	}
 
 	// validate batch file counts
-	err = validateBatchFileCounts(files)
+	err := validateBatchFileCounts(files)
 	if err != nil {
 		return nil, err
 	}
 
-	logger.Debugf("successfully downloaded and validated all batch files")
+	logger.Debugf("successfully downloaded and validated batch files")
 
 	return files, nil
 }
 
-func (h *OperationProvider) getProvisionalFiles(provisionalIndexURI string) (*provisionalFiles, error) {
-	var err error
-	files := &provisionalFiles{}
+// getCoreProofSuffixes returns the suffixes of the operations in the core index file that have signed data in the core proof file.
+func getCoreProofSuffixes(cif *models.CoreIndexFile) []string {
+	if cif.Operations == nil {
+		return nil
+	}
 
-	files.ProvisionalIndex, err = h.getProvisionalIndexFile(provisionalIndexURI)
-	if err != nil {
-		return nil, err
+	var suffixes []string
+
+	for _, op := range cif.Operations.Recover {
+		suffixes = append(suffixes, op.DidSuffix)
 	}
 
-	// provisional proof file will not exist if we don't have any update operations in the batch
-	if files.ProvisionalIndex.ProvisionalProofFileURI != "" {
-		files.ProvisionalProof, err = h.getProvisionalProofFile(files.ProvisionalIndex.ProvisionalProofFileURI)
+	for _, op := range cif.Operations.Deactivate {
+		suffixes = append(suffixes, op.DidSuffix)
+	}
+
+	return suffixes
+}
+
+// getCoreDeltaSuffixes returns the suffixes of the operations in the core index file that have a delta in the chunk file.
+func (h *OperationProvider) getCoreDeltaSuffixes(cif *models.CoreIndexFile) ([]string, error) {
+	if cif.Operations == nil {
+		return nil, nil
+	}
+
+	var suffixes []string
+
+	for _, op := range cif.Operations.Create {
+		suffix, err := model.GetUniqueSuffix(op.SuffixData, h.MultihashAlgorithms)
 		if err != nil {
 			return nil, err
 		}
+
+		suffixes = append(suffixes, suffix)
+	}
+
+	for _, op := range cif.Operations.Recover {
+		suffixes = append(suffixes, op.DidSuffix)
+	}
+
+	return suffixes, nil
+}
+
+// getUpdateSuffixes returns the suffixes of the update operations in the provisional index file.
+func getUpdateSuffixes(pif *models.ProvisionalIndexFile) []string {
+	if pif.Operations == nil {
+		return nil
 	}
 
-	if len(files.ProvisionalIndex.Chunks) == 0 {
-		return nil, errors.Errorf("provisional index file is missing chunk file URI")
+	var suffixes []string
+
+	for _, op := range pif.Operations.Update {
+		suffixes = append(suffixes, op.DidSuffix)
 	}
 
-	chunkURI := files.ProvisionalIndex.Chunks[0].ChunkFileURI
-	files.Chunk, err = h.getChunkFile(chunkURI)
+	return suffixes
+}
+
+// getProvisionalFiles retrieves the provisional index file and adds download tasks to the given group for the
+// provisional proof and chunk files that are referenced in the provisional index file.
+func (h *OperationProvider) getProvisionalFiles(g *downloadGroup, files *batchFiles, provisionalIndexURI string,
+	filter suffixFilter, coreDeltaSuffixes []string) error {
+	pif, err := h.getProvisionalIndexFile(g.ctx, provisionalIndexURI)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
-	return files, nil
+	files.ProvisionalIndex = pif
+
+	if len(pif.Chunks) == 0 {
+		return errors.Errorf("provisional index file is missing chunk file URI")
+	}
+
+	updateSuffixes := getUpdateSuffixes(pif)
+
+	// provisional proof file will not exist if we don't have any update operations in the batch
+	readProvisionalProof := pif.ProvisionalProofFileURI != "" && filter.matchesAny(updateSuffixes)
+	readChunk := filter.matchesAny(append(coreDeltaSuffixes, updateSuffixes...))
+
+	if readProvisionalProof {
+		h.prefetch(pif.ProvisionalProofFileURI)
+	}
+
+	if readChunk {
+		h.prefetch(pif.Chunks[0].ChunkFileURI)
+	}
+
+	if readProvisionalProof {
+		g.run(func() error {
+			ppf, e := h.getProvisionalProofFile(g.ctx, pif.ProvisionalProofFileURI)
+			if e != nil {
+				return e
+			}
+
+			files.ProvisionalProof = ppf
+
+			return nil
+		})
+	}
+
+	if !readChunk {
+		logger.Debugf("chunk file is not required for the requested suffixes")
+
+		return nil
+	}
+
+	g.run(func() error {
+		chunk, e := h.getChunkFile(g.ctx, pif.Chunks[0].ChunkFileURI)
+		if e != nil {
+			return e
+		}
+
+		files.Chunk = chunk
+
+		return nil
+	})
+
+	return nil
+}
+
+// prefetch starts retrieving the content of the given (non-empty) URIs in the background if the CAS
+// client supports prefetching (e.g. a caching CAS client), so that subsequent reads are served from the cache.
+func (h *OperationProvider) prefetch(uris ...string) {
+	p, ok := h.cas.(cas.Prefetcher)
+	if !ok {
+		return
+	}
+
+	var addresses []string
+
+	for _, uri := range uris {
+		if uri != "" {
+			addresses = append(addresses, uri)
+		}
+	}
+
+	if len(addresses) > 0 {
+		p.Prefetch(addresses...)
+	}
 }
 
 // validateBatchFileCounts validates that operation numbers match in batch files.
@@ -182,7 +407,8 @@ func validateBatchFileCounts(batchFiles *batchFiles) error {
 		coreDeactivateNum = len(batchFiles.CoreIndex.Operations.Deactivate)
 	}
 
-	if batchFiles.CoreIndex.CoreProofFileURI != "" {
+	// core proof file is not retrieved if it's not required for the requested suffixes
+	if batchFiles.CoreProof != nil {
 		coreProofRecoverNum := len(batchFiles.CoreProof.Operations.Recover)
 		coreProofDeactivateNum := len(batchFiles.CoreProof.Operations.Deactivate)
 
@@ -197,27 +423,35 @@ func validateBatchFileCounts(batchFiles *batchFiles) error {
 		}
 	}
 
-	if batchFiles.CoreIndex.ProvisionalIndexFileURI != "" { //nolint:nestif
-		provisionalUpdateNum := 0
-		if batchFiles.ProvisionalIndex.Operations != nil {
-			provisionalUpdateNum = len(batchFiles.ProvisionalIndex.Operations.Update)
-		}
+	if batchFiles.CoreIndex.ProvisionalIndexFileURI != "" {
+		return validateProvisionalFileCounts(batchFiles, coreCreateNum+coreRecoverNum)
+	}
+
+	return nil
+}
+
+// validateProvisionalFileCounts validates that operation numbers match in provisional index, provisional proof
+// and chunk files. Proof and chunk files are not validated if they were not retrieved.
+func validateProvisionalFileCounts(batchFiles *batchFiles, coreDeltaNum int) error {
+	provisionalUpdateNum := 0
+	if batchFiles.ProvisionalIndex.Operations != nil {
+		provisionalUpdateNum = len(batchFiles.ProvisionalIndex.Operations.Update)
+	}
 
-		if batchFiles.ProvisionalIndex.ProvisionalProofFileURI != "" {
-			provisionalProofUpdateNum := len(batchFiles.ProvisionalProof.Operations.Update)
+	if batchFiles.ProvisionalProof != nil {
+		provisionalProofUpdateNum := len(batchFiles.ProvisionalProof.Operations.Update)
 
-			if provisionalUpdateNum != provisionalProofUpdateNum {
-				return fmt.Errorf("number of update ops[%d] in provisional index doesn't match number of update ops[%d] in provisional proof",
-					provisionalUpdateNum, provisionalProofUpdateNum)
-			}
+		if provisionalUpdateNum != provisionalProofUpdateNum {
+			return fmt.Errorf("number of update ops[%d] in provisional index doesn't match number of update ops[%d] in provisional proof",
+				provisionalUpdateNum, provisionalProofUpdateNum)
 		}
+	}
 
-		expectedDeltaCount := coreCreateNum + coreRecoverNum + provisionalUpdateNum
+	expectedDeltaCount := coreDeltaNum + provisionalUpdateNum
 
-		if expectedDeltaCount != len(batchFiles.Chunk.Deltas) {
-			return fmt.Errorf("number of create+recover+update operations[%d] doesn't match number of deltas[%d]",
-				expectedDeltaCount, len(batchFiles.Chunk.Deltas))
-		}
+	if batchFiles.Chunk != nil && expectedDeltaCount != len(batchFiles.Chunk.Deltas) {
+		return fmt.Errorf("number of create+recover+update operations[%d] doesn't match number of deltas[%d]",
+			expectedDeltaCount, len(batchFiles.Chunk.Deltas))
 	}
 
 	return nil
@@ -246,8 +480,10 @@ func (h *OperationProvider) assembleAnchoredOperations(batchFiles *batchFiles, t
 		len(cifOps.Create), len(cifOps.Recover), len(cifOps.Deactivate))
 
 	// add signed data from core proof file to deactivate operations
-	for i := range cifOps.Deactivate {
-		cifOps.Deactivate[i].SignedData = batchFiles.CoreProof.Operations.Deactivate[i]
+	if batchFiles.CoreProof != nil {
+		for i := range cifOps.Deactivate {
+			cifOps.Deactivate[i].SignedData = batchFiles.CoreProof.Operations.Deactivate[i]
+		}
 	}
 
 	// deactivate operations only
@@ -270,35 +506,30 @@ func (h *OperationProvider) assembleAnchoredOperations(batchFiles *batchFiles, t
 	operations = append(operations, cifOps.Create...)
 
 	// add signed data from core proof file
-	for i := range cifOps.Recover {
-		cifOps.Recover[i].SignedData = batchFiles.CoreProof.Operations.Recover[i]
-
-		// parse signed data to extract anchor origin
-		signedDataModel, err := h.parser.ParseSignedDataForRecover(cifOps.Recover[i].SignedData)
+	if batchFiles.CoreProof != nil {
+		err = h.addRecoverSignedData(cifOps.Recover, batchFiles.CoreProof)
 		if err != nil {
-			return nil, fmt.Errorf("failed to validate signed data for recover[%d]: %s", i, err.Error())
+			return nil, err
 		}
-
-		cifOps.Recover[i].AnchorOrigin = signedDataModel.AnchorOrigin
 	}
 
 	operations = append(operations, cifOps.Recover...)
 
 	// add signed data from provisional proof file
-	for i := range pifOps.Update {
-		pifOps.Update[i].SignedData = batchFiles.ProvisionalProof.Operations.Update[i]
+	if batchFiles.ProvisionalProof != nil {
+		for i := range pifOps.Update {
+			pifOps.Update[i].SignedData = batchFiles.ProvisionalProof.Operations.Update[i]
+		}
 	}
 
 	operations = append(operations, pifOps.Update...)
 
-	if len(operations) != len(batchFiles.Chunk.Deltas) {
-		// this should never happen since we are assembling batch files
-		return nil, fmt.Errorf("number of create+recover+update operations[%d] doesn't match number of deltas[%d]",
-			len(operations), len(batchFiles.Chunk.Deltas))
-	}
-
-	for i, delta := range batchFiles.Chunk.Deltas {
-		operations[i].Delta = delta
+	// chunk file is not retrieved if it's not required for the requested suffixes
+	if batchFiles.Chunk != nil {
+		err = addDeltas(operations, batchFiles.Chunk)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	operations = append(operations, cifOps.Deactivate...)
@@ -306,6 +537,36 @@ func (h *OperationProvider) assembleAnchoredOperations(batchFiles *batchFiles, t
 	return createAnchoredOperations(operations)
 }
 
+func (h *OperationProvider) addRecoverSignedData(recoverOps []*model.Operation, cpf *models.CoreProofFile) error {
+	for i := range recoverOps {
+		recoverOps[i].SignedData = cpf.Operations.Recover[i]
+
+		// parse signed data to extract anchor origin
+		signedDataModel, err := h.parser.ParseSignedDataForRecover(recoverOps[i].SignedData)
+		if err != nil {
+			return fmt.Errorf("failed to validate signed data for recover[%d]: %s", i, err.Error())
+		}
+
+		recoverOps[i].AnchorOrigin = signedDataModel.AnchorOrigin
+	}
+
+	return nil
+}
+
+func addDeltas(ops []*model.Operation, cf *models.ChunkFile) error {
+	if len(ops) != len(cf.Deltas) {
+		// this should never happen since we are assembling batch files
+		return fmt.Errorf("number of create+recover+update operations[%d] doesn't match number of deltas[%d]",
+			len(ops), len(cf.Deltas))
+	}
+
+	for i, delta := range cf.Deltas {
+		ops[i].Delta = delta
+	}
+
+	return nil
+}
+
 func checkForDuplicates(values []string) error {
 	var duplicates []string
 
@@ -327,8 +588,8 @@ func checkForDuplicates(values []string) error {
 }
 
 // getCoreIndexFile will download core index file from cas and parse it into core index file model.
-func (h *OperationProvider) getCoreIndexFile(uri string) (*models.CoreIndexFile, error) { //nolint:dupl
-	content, err := h.readFromCAS(uri, h.MaxCoreIndexFileSize)
+func (h *OperationProvider) getCoreIndexFile(ctx context.Context, uri string) (*models.CoreIndexFile, error) { //nolint:dupl
+	content, err := h.readFromCAS(ctx, uri, h.MaxCoreIndexFileSize)
 	if err != nil {
 		return nil, errors.Wrapf(err, "error reading core index file")
 	}
@@ -435,8 +696,8 @@ func (h *OperationProvider) validateRequiredMultihash(mh, alias string) error {
 }
 
 // getCoreProofFile will download core proof file from cas and parse it into core proof file model.
-func (h *OperationProvider) getCoreProofFile(uri string) (*models.CoreProofFile, error) { //nolint:dupl
-	content, err := h.readFromCAS(uri, h.MaxProofFileSize)
+func (h *OperationProvider) getCoreProofFile(ctx context.Context, uri string) (*models.CoreProofFile, error) { //nolint:dupl
+	content, err := h.readFromCAS(ctx, uri, h.MaxProofFileSize)
 	if err != nil {
 		return nil, errors.Wrapf(err, "error reading core proof file")
 	}
@@ -475,8 +736,8 @@ func (h *OperationProvider) validateCoreProofFile(cpf *models.CoreProofFile) err
 }
 
 // getProvisionalProofFile will download provisional proof file from cas and parse it into provisional proof file model.
-func (h *OperationProvider) getProvisionalProofFile(uri string) (*models.ProvisionalProofFile, error) { //nolint:dupl
-	content, err := h.readFromCAS(uri, h.MaxProofFileSize)
+func (h *OperationProvider) getProvisionalProofFile(ctx context.Context, uri string) (*models.ProvisionalProofFile, error) { //nolint:dupl
+	content, err := h.readFromCAS(ctx, uri, h.MaxProofFileSize)
 	if err != nil {
 		return nil, errors.Wrapf(err, "error reading provisional proof file")
 	}
@@ -508,8 +769,8 @@ func (h *OperationProvider) validateProvisionalProofFile(ppf *models.Provisional
 }
 
 // getProvisionalIndexFile will download provisional index file from cas and parse it into provisional index file model.
-func (h *OperationProvider) getProvisionalIndexFile(uri string) (*models.ProvisionalIndexFile, error) { //nolint:dupl
-	content, err := h.readFromCAS(uri, h.MaxProvisionalIndexFileSize)
+func (h *OperationProvider) getProvisionalIndexFile(ctx context.Context, uri string) (*models.ProvisionalIndexFile, error) { //nolint:dupl
+	content, err := h.readFromCAS(ctx, uri, h.MaxProvisionalIndexFileSize)
 	if err != nil {
 		return nil, errors.Wrapf(err, "error reading provisional index file")
 	}
@@ -582,8 +843,8 @@ func (h *OperationProvider) validateProvisionalIndexOperations(ops *models.Provi
 }
 
 // getChunkFile will download chunk file from cas and parse it into chunk file model.
-func (h *OperationProvider) getChunkFile(uri string) (*models.ChunkFile, error) { //nolint:dupl
-	content, err := h.readFromCAS(uri, h.MaxChunkFileSize)
+func (h *OperationProvider) getChunkFile(ctx context.Context, uri string) (*models.ChunkFile, error) { //nolint:dupl
+	content, err := h.readFromCAS(ctx, uri, h.MaxChunkFileSize)
 	if err != nil {
 		return nil, errors.Wrapf(err, "error reading chunk file")
 	}
@@ -614,29 +875,134 @@ func (h *OperationProvider) validateChunkFile(cf *models.ChunkFile) error {
 	return nil
 }
 
-func (h *OperationProvider) readFromCAS(uri string, maxSize uint) ([]byte, error) {
-	bytes, err := h.cas.Read(uri)
+// readFromCAS reads and decompresses the content at the given URI. The size limits are enforced while the content
+// is being read (and decompressed) so that no more than the maximum (decompressed) size is ever held in memory.
+// The read is aborted when the given context is done.
+func (h *OperationProvider) readFromCAS(ctx context.Context, uri string, maxSize uint) ([]byte, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, errors.Wrapf(err, "retrieve CAS content at uri[%s]", uri)
+	}
+
+	reader, err := h.openCASReader(ctx, uri)
 	if err != nil {
 		return nil, errors.Wrapf(err, "retrieve CAS content at uri[%s]", uri)
 	}
 
-	if len(bytes) > int(maxSize) {
-		return nil, fmt.Errorf("uri[%s]: content size %d exceeded maximum size %d", uri, len(bytes), maxSize)
+	defer func() {
+		if e := reader.Close(); e != nil {
+			logger.Warnf("error closing CAS reader for uri[%s]: %s", uri, e)
+		}
+	}()
+
+	compressed := &countingReader{reader: io.LimitReader(reader, int64(maxSize)+1)}
+
+	maxDecompressedSize := maxSize * h.MaxMemoryDecompressionFactor
+
+	content, err := h.decompress(compressed, maxDecompressedSize)
+
+	if compressed.count > int64(maxSize) {
+		return nil, fmt.Errorf("uri[%s]: content size exceeded maximum size %d", uri, maxSize)
 	}
 
-	content, err := h.dp.Decompress(h.CompressionAlgorithm, bytes)
 	if err != nil {
 		return nil, errors.Wrapf(err, "decompress CAS uri[%s] using '%s'", uri, h.CompressionAlgorithm)
 	}
 
-	maxDecompressedSize := maxSize * h.MaxMemoryDecompressionFactor
 	if len(content) > int(maxDecompressedSize) {
-		return nil, fmt.Errorf("uri[%s]: decompressed content size %d exceeded maximum decompressed content size %d", uri, len(content), maxDecompressedSize)
+		return nil, fmt.Errorf("uri[%s]: decompressed content size exceeded maximum decompressed content size %d", uri, maxDecompressedSize)
+	}
+
+	// Read any remaining compressed content so that the content is verified against the URI.
+	if _, err := io.Copy(ioutil.Discard, compressed); err != nil {
+		return nil, errors.Wrapf(err, "retrieve CAS content at uri[%s]", uri)
+	}
+
+	if compressed.count > int64(maxSize) {
+		return nil, fmt.Errorf("uri[%s]: content size exceeded maximum size %d", uri, maxSize)
 	}
 
 	return content, nil
 }
 
+// openCASReader returns a reader for the content at the given URI that fails once the given context is done.
+// If the CAS client doesn't support streaming then the content is read fully (and the read can't be aborted).
+func (h *OperationProvider) openCASReader(ctx context.Context, uri string) (io.ReadCloser, error) {
+	if sr, ok := h.cas.(cas.StreamReader); ok {
+		reader, err := sr.ReadStream(uri)
+		if err != nil {
+			return nil, err
+		}
+
+		return &contextReader{ctx: ctx, ReadCloser: reader}, nil
+	}
+
+	content, err := h.cas.Read(uri)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	return ioutil.NopCloser(bytes.NewReader(content)), nil
+}
+
+// contextReader fails reads once the context is done.
+type contextReader struct {
+	io.ReadCloser
+
+	ctx context.Context
+}
+
+func (r *contextReader) Read(p []byte) (int, error) {
+	if err := r.ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	return r.ReadCloser.Read(p)
+}
+
+// decompress decompresses the content of the given reader. At most maxSize+1 decompressed bytes are returned
+// so that the caller is able to detect that the maximum size was exceeded.
+func (h *OperationProvider) decompress(reader io.Reader, maxSize uint) ([]byte, error) {
+	sdp, ok := h.dp.(streamingDecompressionProvider)
+	if !ok {
+		data, err := ioutil.ReadAll(reader)
+		if err != nil {
+			return nil, err
+		}
+
+		return h.dp.Decompress(h.CompressionAlgorithm, data)
+	}
+
+	dr, err := sdp.NewDecompressionReader(h.CompressionAlgorithm, reader)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		if e := dr.Close(); e != nil {
+			logger.Warnf("error closing decompression reader: %s", e)
+		}
+	}()
+
+	return ioutil.ReadAll(io.LimitReader(dr, int64(maxSize)+1))
+}
+
+// countingReader counts the number of bytes that were read from the underlying reader.
+type countingReader struct {
+	reader io.Reader
+	count  int64
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	r.count += int64(n)
+
+	return n, err
+}
+
 // coreOperations contains operations in core index file.
 type coreOperations struct {
 	Create     []*model.Operation
@@ -7,9 +7,16 @@ SPDX-License-Identifier: Apache-2.0
 package txnprovider
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
@@ -70,7 +77,7 @@ func TestHandler_GetTxnOperations(t *testing.T) {
 
 		provider := NewOperationProvider(pc.Protocol, parser, cas, cp)
 
-		txnOps, err := provider.GetTxnOperations(&txn.SidetreeTxn{
+		txnOps, err := provider.GetTxnOperations(context.Background(), &txn.SidetreeTxn{
 			Namespace:         defaultNS,
 			AnchorString:      anchorString,
 			TransactionNumber: 1,
@@ -97,7 +104,7 @@ func TestHandler_GetTxnOperations(t *testing.T) {
 
 		provider := NewOperationProvider(smallDeltaProofSize, operationparser.New(smallDeltaProofSize), cas, cp)
 
-		txnOps, err := provider.GetTxnOperations(&txn.SidetreeTxn{
+		txnOps, err := provider.GetTxnOperations(context.Background(), &txn.SidetreeTxn{
 			Namespace:         defaultNS,
 			AnchorString:      anchorString,
 			TransactionNumber: 1,
@@ -128,7 +135,7 @@ func TestHandler_GetTxnOperations(t *testing.T) {
 
 		provider := NewOperationProvider(mocks.NewMockProtocolClient().Protocol, operationparser.New(pc.Protocol), cas, cp)
 
-		txnOps, err := provider.GetTxnOperations(&txn.SidetreeTxn{
+		txnOps, err := provider.GetTxnOperations(context.Background(), &txn.SidetreeTxn{
 			Namespace:         defaultNS,
 			AnchorString:      anchorString,
 			TransactionNumber: 1,
@@ -144,7 +151,7 @@ func TestHandler_GetTxnOperations(t *testing.T) {
 		protocolClient := mocks.NewMockProtocolClient()
 		handler := NewOperationProvider(protocolClient.Protocol, operationparser.New(protocolClient.Protocol), mocks.NewMockCasClient(errors.New("CAS error")), cp)
 
-		txnOps, err := handler.GetTxnOperations(&txn.SidetreeTxn{
+		txnOps, err := handler.GetTxnOperations(context.Background(), &txn.SidetreeTxn{
 			Namespace:         defaultNS,
 			AnchorString:      "1" + delimiter + "coreIndexURI",
 			TransactionNumber: 1,
@@ -171,7 +178,7 @@ func TestHandler_GetTxnOperations(t *testing.T) {
 
 		provider := NewOperationProvider(invalid, operationparser.New(invalid), cas, cp)
 
-		txnOps, err := provider.GetTxnOperations(&txn.SidetreeTxn{
+		txnOps, err := provider.GetTxnOperations(context.Background(), &txn.SidetreeTxn{
 			Namespace:         mocks.DefaultNS,
 			AnchorString:      anchorString,
 			TransactionNumber: 1,
@@ -187,7 +194,7 @@ func TestHandler_GetTxnOperations(t *testing.T) {
 		p := mocks.NewMockProtocolClient().Protocol
 		provider := NewOperationProvider(p, operationparser.New(p), mocks.NewMockCasClient(nil), cp)
 
-		txnOps, err := provider.GetTxnOperations(&txn.SidetreeTxn{
+		txnOps, err := provider.GetTxnOperations(context.Background(), &txn.SidetreeTxn{
 			AnchorString:      "abc.anchor",
 			TransactionNumber: 1,
 			TransactionTime:   1,
@@ -218,7 +225,7 @@ func TestHandler_GetTxnOperations(t *testing.T) {
 		p := mocks.NewMockProtocolClient().Protocol
 		provider := NewOperationProvider(p, operationparser.New(p), cas, cp)
 
-		txnOps, err := provider.GetTxnOperations(&txn.SidetreeTxn{
+		txnOps, err := provider.GetTxnOperations(context.Background(), &txn.SidetreeTxn{
 			Namespace:         defaultNS,
 			AnchorString:      anchorString,
 			TransactionNumber: 1,
@@ -249,7 +256,7 @@ func TestHandler_GetTxnOperations(t *testing.T) {
 		p := mocks.NewMockProtocolClient().Protocol
 		provider := NewOperationProvider(p, operationparser.New(p), cas, cp)
 
-		txnOps, err := provider.GetTxnOperations(&txn.SidetreeTxn{
+		txnOps, err := provider.GetTxnOperations(context.Background(), &txn.SidetreeTxn{
 			Namespace:         defaultNS,
 			AnchorString:      anchorString,
 			TransactionNumber: 1,
@@ -280,7 +287,7 @@ func TestHandler_GetTxnOperations(t *testing.T) {
 		p := mocks.NewMockProtocolClient().Protocol
 		provider := NewOperationProvider(p, operationparser.New(p), cas, cp)
 
-		txnOps, err := provider.GetTxnOperations(&txn.SidetreeTxn{
+		txnOps, err := provider.GetTxnOperations(context.Background(), &txn.SidetreeTxn{
 			Namespace:         defaultNS,
 			AnchorString:      anchorString,
 			TransactionNumber: 1,
@@ -311,7 +318,7 @@ func TestHandler_GetTxnOperations(t *testing.T) {
 		p := mocks.NewMockProtocolClient().Protocol
 		provider := NewOperationProvider(p, operationparser.New(p), cas, cp)
 
-		txnOps, err := provider.GetTxnOperations(&txn.SidetreeTxn{
+		txnOps, err := provider.GetTxnOperations(context.Background(), &txn.SidetreeTxn{
 			Namespace:         defaultNS,
 			AnchorString:      anchorString,
 			TransactionNumber: 1,
@@ -323,6 +330,190 @@ func TestHandler_GetTxnOperations(t *testing.T) {
 	})
 }
 
+func TestHandler_GetTxnOperations_ConcurrentDownloads(t *testing.T) {
+	pc := mocks.NewMockProtocolClient()
+	parser := operationparser.New(pc.Protocol)
+	cp := compression.New(compression.WithDefaultAlgorithms())
+	cas := mocks.NewMockCasClient(nil)
+
+	handler := NewOperationHandler(pc.Protocol, cas, cp, parser)
+
+	anchorString, _, _, err := handler.PrepareTxnFiles(getTestOperations(2, 2, 2, 2))
+	require.NoError(t, err)
+
+	sidetreeTxn := &txn.SidetreeTxn{
+		Namespace:         defaultNS,
+		AnchorString:      anchorString,
+		TransactionNumber: 1,
+		TransactionTime:   1,
+	}
+
+	t.Run("independent files are downloaded concurrently", func(t *testing.T) {
+		ccas := &concurrencyTrackingCAS{DCAS: cas, delay: 20 * time.Millisecond}
+
+		txnOps, err := NewOperationProvider(pc.Protocol, parser, ccas, cp).GetTxnOperations(context.Background(), sidetreeTxn)
+		require.NoError(t, err)
+		require.Len(t, txnOps, 8)
+		require.Equal(t, int32(2), ccas.maxConcurrentReads())
+	})
+
+	t.Run("maximum concurrent downloads", func(t *testing.T) {
+		ccas := &concurrencyTrackingCAS{DCAS: cas, delay: 20 * time.Millisecond}
+
+		provider := NewOperationProvider(pc.Protocol, parser, ccas, cp, WithMaxConcurrentDownloads(1))
+
+		txnOps, err := provider.GetTxnOperations(context.Background(), sidetreeTxn)
+		require.NoError(t, err)
+		require.Len(t, txnOps, 8)
+		require.Equal(t, int32(1), ccas.maxConcurrentReads())
+	})
+
+	t.Run("context cancelled before download", func(t *testing.T) {
+		rcas := &readTrackingCAS{DCAS: cas}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		txnOps, err := NewOperationProvider(pc.Protocol, parser, rcas, cp).GetTxnOperations(ctx, sidetreeTxn)
+		require.Error(t, err)
+		require.Nil(t, txnOps)
+		require.True(t, errors.Is(err, context.Canceled))
+		require.Empty(t, rcas.uris)
+	})
+
+	t.Run("context cancelled during download", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+
+		// the context is cancelled once the core index file has been read
+		ccas := &cancellingCAS{DCAS: cas, cancel: cancel}
+
+		txnOps, err := NewOperationProvider(pc.Protocol, parser, ccas, cp).GetTxnOperations(ctx, sidetreeTxn)
+		require.Error(t, err)
+		require.Nil(t, txnOps)
+		require.True(t, errors.Is(err, context.Canceled))
+		require.Equal(t, 1, ccas.reads)
+	})
+
+	t.Run("invalid maximum concurrent downloads", func(t *testing.T) {
+		provider := NewOperationProvider(pc.Protocol, parser, cas, cp, WithMaxConcurrentDownloads(0))
+
+		txnOps, err := provider.GetTxnOperations(context.Background(), sidetreeTxn)
+		require.NoError(t, err)
+		require.Len(t, txnOps, 8)
+	})
+}
+
+func TestHandler_GetTxnOperations_Suffixes(t *testing.T) {
+	pc := mocks.NewMockProtocolClient()
+	parser := operationparser.New(pc.Protocol)
+	cp := compression.New(compression.WithDefaultAlgorithms())
+	cas := mocks.NewMockCasClient(nil)
+
+	handler := NewOperationHandler(pc.Protocol, cas, cp, parser)
+
+	anchorString, artifacts, _, err := handler.PrepareTxnFiles(getTestOperations(2, 2, 2, 2))
+	require.NoError(t, err)
+
+	uris := make(map[string]string)
+	for _, artifact := range artifacts {
+		uris[artifact.ID] = artifact.Desc
+	}
+
+	sidetreeTxn := &txn.SidetreeTxn{
+		Namespace:         defaultNS,
+		AnchorString:      anchorString,
+		TransactionNumber: 1,
+		TransactionTime:   1,
+	}
+
+	allOps, err := NewOperationProvider(pc.Protocol, parser, cas, cp).GetTxnOperations(context.Background(), sidetreeTxn)
+	require.NoError(t, err)
+	require.Len(t, allOps, 8)
+
+	suffixes := make(map[operation.Type]string)
+	opsBySuffix := make(map[string]*operation.AnchoredOperation)
+
+	for _, op := range allOps {
+		suffixes[op.Type] = op.UniqueSuffix
+		opsBySuffix[op.UniqueSuffix] = op
+	}
+
+	tests := []struct {
+		name      string
+		suffixes  []string
+		expected  []string
+		filesRead []string
+	}{
+		{
+			name:      "create",
+			suffixes:  []string{suffixes[operation.TypeCreate]},
+			expected:  []string{suffixes[operation.TypeCreate]},
+			filesRead: []string{"core index file", "provisional index file", "chunk file"},
+		},
+		{
+			name:      "update",
+			suffixes:  []string{suffixes[operation.TypeUpdate]},
+			expected:  []string{suffixes[operation.TypeUpdate]},
+			filesRead: []string{"core index file", "provisional index file", "provisional proof file", "chunk file"},
+		},
+		{
+			name:      "recover",
+			suffixes:  []string{suffixes[operation.TypeRecover]},
+			expected:  []string{suffixes[operation.TypeRecover]},
+			filesRead: []string{"core index file", "core proof file", "provisional index file", "chunk file"},
+		},
+		{
+			name:      "deactivate",
+			suffixes:  []string{suffixes[operation.TypeDeactivate]},
+			expected:  []string{suffixes[operation.TypeDeactivate]},
+			filesRead: []string{"core index file", "core proof file", "provisional index file"},
+		},
+		{
+			name:      "create and deactivate",
+			suffixes:  []string{suffixes[operation.TypeCreate], suffixes[operation.TypeDeactivate]},
+			expected:  []string{suffixes[operation.TypeCreate], suffixes[operation.TypeDeactivate]},
+			filesRead: []string{"core index file", "core proof file", "provisional index file", "chunk file"},
+		},
+		{
+			name:      "suffix not in batch",
+			suffixes:  []string{"unknown"},
+			filesRead: []string{"core index file", "provisional index file"},
+		},
+	}
+
+	for _, tc := range tests {
+		test := tc
+
+		t.Run(test.name, func(t *testing.T) {
+			reader := &prefetchTrackingCAS{readTrackingCAS: readTrackingCAS{DCAS: cas}}
+
+			txnOps, err := NewOperationProvider(pc.Protocol, parser, reader, cp).GetTxnOperations(context.Background(), sidetreeTxn, test.suffixes...)
+			require.NoError(t, err)
+			require.Len(t, txnOps, len(test.expected))
+
+			for i, op := range txnOps {
+				require.Equal(t, test.expected[i], op.UniqueSuffix)
+				require.Equal(t, opsBySuffix[op.UniqueSuffix], op)
+			}
+
+			var filesRead []string
+			for _, uri := range reader.uris {
+				filesRead = append(filesRead, uris[uri])
+			}
+
+			require.ElementsMatch(t, test.filesRead, filesRead)
+
+			// all files except for the core index file are prefetched
+			var filesPrefetched []string
+			for _, uri := range reader.prefetched {
+				filesPrefetched = append(filesPrefetched, uris[uri])
+			}
+
+			require.ElementsMatch(t, test.filesRead[1:], filesPrefetched)
+		})
+	}
+}
+
 func TestHandler_GetCoreIndexFile(t *testing.T) {
 	cp := compression.New(compression.WithDefaultAlgorithms())
 	p := protocol.Protocol{
@@ -344,7 +535,7 @@ func TestHandler_GetCoreIndexFile(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		provider := NewOperationProvider(p, parser, cas, cp)
 
-		file, err := provider.getCoreIndexFile(address)
+		file, err := provider.getCoreIndexFile(context.Background(), address)
 		require.NoError(t, err)
 		require.NotNil(t, file)
 	})
@@ -352,7 +543,7 @@ func TestHandler_GetCoreIndexFile(t *testing.T) {
 	t.Run("error - core index file exceeds maximum size", func(t *testing.T) {
 		provider := NewOperationProvider(protocol.Protocol{MaxCoreIndexFileSize: 15, CompressionAlgorithm: compressionAlgorithm}, parser, cas, cp)
 
-		file, err := provider.getCoreIndexFile(address)
+		file, err := provider.getCoreIndexFile(context.Background(), address)
 		require.Error(t, err)
 		require.Nil(t, file)
 		require.Contains(t, err.Error(), "exceeded maximum size 15")
@@ -365,7 +556,7 @@ func TestHandler_GetCoreIndexFile(t *testing.T) {
 		address, err := cas.Write(content)
 
 		provider := NewOperationProvider(p, parser, cas, cp)
-		file, err := provider.getCoreIndexFile(address)
+		file, err := provider.getCoreIndexFile(context.Background(), address)
 		require.Error(t, err)
 		require.Nil(t, file)
 		require.Contains(t, err.Error(), "failed to parse content for core index file")
@@ -390,7 +581,7 @@ func TestHandler_GetCoreIndexFile(t *testing.T) {
 		address, err := cas.Write(content)
 
 		provider := NewOperationProvider(p, parser, cas, cp)
-		file, err := provider.getCoreIndexFile(address)
+		file, err := provider.getCoreIndexFile(context.Background(), address)
 		require.Error(t, err)
 		require.Nil(t, file)
 		require.Contains(t, err.Error(), "failed to validate suffix data for create[0]")
@@ -545,7 +736,7 @@ func TestHandler_GetProvisionalIndexFile(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		provider := NewOperationProvider(p, operationparser.New(p), cas, cp)
 
-		file, err := provider.getProvisionalIndexFile(address)
+		file, err := provider.getProvisionalIndexFile(context.Background(), address)
 		require.NoError(t, err)
 		require.NotNil(t, file)
 	})
@@ -555,7 +746,7 @@ func TestHandler_GetProvisionalIndexFile(t *testing.T) {
 		parser := operationparser.New(lowMaxFileSize)
 		provider := NewOperationProvider(lowMaxFileSize, parser, cas, cp)
 
-		file, err := provider.getProvisionalIndexFile(address)
+		file, err := provider.getProvisionalIndexFile(context.Background(), address)
 		require.Error(t, err)
 		require.Nil(t, file)
 		require.Contains(t, err.Error(), "exceeded maximum size 5")
@@ -569,7 +760,7 @@ func TestHandler_GetProvisionalIndexFile(t *testing.T) {
 
 		parser := operationparser.New(p)
 		provider := NewOperationProvider(p, parser, cas, cp)
-		file, err := provider.getProvisionalIndexFile(address)
+		file, err := provider.getProvisionalIndexFile(context.Background(), address)
 		require.Error(t, err)
 		require.Nil(t, file)
 		require.Contains(t, err.Error(), "failed to parse content for provisional index file")
@@ -694,7 +885,7 @@ func TestHandler_GetChunkFile(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		provider := NewOperationProvider(p, operationparser.New(p), cas, cp)
 
-		file, err := provider.getChunkFile(address)
+		file, err := provider.getChunkFile(context.Background(), address)
 		require.NoError(t, err)
 		require.NotNil(t, file)
 	})
@@ -703,7 +894,7 @@ func TestHandler_GetChunkFile(t *testing.T) {
 		lowMaxFileSize := protocol.Protocol{MaxChunkFileSize: 10, CompressionAlgorithm: compressionAlgorithm}
 		provider := NewOperationProvider(lowMaxFileSize, operationparser.New(p), cas, cp)
 
-		file, err := provider.getChunkFile(address)
+		file, err := provider.getChunkFile(context.Background(), address)
 		require.Error(t, err)
 		require.Nil(t, file)
 		require.Contains(t, err.Error(), "exceeded maximum size 10")
@@ -715,7 +906,7 @@ func TestHandler_GetChunkFile(t *testing.T) {
 		address, err := cas.Write(content)
 
 		provider := NewOperationProvider(p, operationparser.New(p), cas, cp)
-		file, err := provider.getChunkFile(address)
+		file, err := provider.getChunkFile(context.Background(), address)
 		require.Error(t, err)
 		require.Nil(t, file)
 		require.Contains(t, err.Error(), "failed to parse content for chunk file")
@@ -737,7 +928,7 @@ func TestHandler_GetChunkFile(t *testing.T) {
 		address, err := cas.Write(content)
 
 		provider := NewOperationProvider(p, operationparser.New(p), cas, cp)
-		file, err := provider.getChunkFile(address)
+		file, err := provider.getChunkFile(context.Background(), address)
 		require.Error(t, err)
 		require.Nil(t, file)
 		require.Contains(t, err.Error(), "failed to validate delta[0]")
@@ -787,7 +978,7 @@ func TestHandler_readFromCAS(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		provider := NewOperationProvider(p, operationparser.New(p), cas, cp)
 
-		file, err := provider.readFromCAS(address, maxFileSize)
+		file, err := provider.readFromCAS(context.Background(), address, maxFileSize)
 		require.NoError(t, err)
 		require.NotNil(t, file)
 	})
@@ -795,7 +986,7 @@ func TestHandler_readFromCAS(t *testing.T) {
 	t.Run("error - read from CAS error", func(t *testing.T) {
 		provider := NewOperationProvider(p, operationparser.New(p), mocks.NewMockCasClient(errors.New("CAS error")), cp)
 
-		file, err := provider.getChunkFile("address")
+		file, err := provider.getChunkFile(context.Background(), "address")
 		require.Error(t, err)
 		require.Nil(t, file)
 		require.Contains(t, err.Error(), " retrieve CAS content at uri[address]: CAS error")
@@ -804,7 +995,7 @@ func TestHandler_readFromCAS(t *testing.T) {
 	t.Run("error - content exceeds maximum size", func(t *testing.T) {
 		provider := NewOperationProvider(p, operationparser.New(p), cas, cp)
 
-		file, err := provider.readFromCAS(address, 20)
+		file, err := provider.readFromCAS(context.Background(), address, 20)
 		require.Error(t, err)
 		require.Nil(t, file)
 		require.Contains(t, err.Error(), "exceeded maximum size 20")
@@ -823,10 +1014,10 @@ func TestHandler_readFromCAS(t *testing.T) {
 		testAddress, err := cas.Write(testContent)
 		require.NoError(t, err)
 
-		file, err := provider.readFromCAS(testAddress, 247)
+		file, err := provider.readFromCAS(context.Background(), testAddress, 247)
 		require.Error(t, err)
 		require.Nil(t, file)
-		require.Contains(t, err.Error(), "decompressed content size 267 exceeded maximum decompressed content size 247")
+		require.Contains(t, err.Error(), "decompressed content size exceeded maximum decompressed content size 247")
 	})
 
 	t.Run("error - decompression error", func(t *testing.T) {
@@ -838,11 +1029,124 @@ func TestHandler_readFromCAS(t *testing.T) {
 
 		provider := NewOperationProvider(p2, operationparser.New(p2), cas, cp)
 
-		file, err := provider.readFromCAS(address, maxFileSize)
+		file, err := provider.readFromCAS(context.Background(), address, maxFileSize)
 		require.Error(t, err)
 		require.Nil(t, file)
 		require.Contains(t, err.Error(), "compression algorithm 'alg' not supported")
 	})
+
+	t.Run("success - streaming CAS", func(t *testing.T) {
+		provider := NewOperationProvider(p, operationparser.New(p), &streamingCAS{content: content}, cp)
+
+		file, err := provider.readFromCAS(context.Background(), address, maxFileSize)
+		require.NoError(t, err)
+		require.Equal(t, []byte("{}"), file)
+	})
+
+	t.Run("success - decompression provider doesn't support streaming", func(t *testing.T) {
+		provider := NewOperationProvider(p, operationparser.New(p), cas, &nonStreamingDecompressor{cp: cp})
+
+		file, err := provider.readFromCAS(context.Background(), address, maxFileSize)
+		require.NoError(t, err)
+		require.Equal(t, []byte("{}"), file)
+	})
+
+	t.Run("error - streaming CAS content exceeds maximum size", func(t *testing.T) {
+		largeContent := make([]byte, 1024*1024)
+
+		scas := &streamingCAS{content: largeContent}
+
+		provider := NewOperationProvider(p, operationparser.New(p), scas, cp)
+
+		file, err := provider.readFromCAS(context.Background(), address, 20)
+		require.Error(t, err)
+		require.Nil(t, file)
+		require.Contains(t, err.Error(), "exceeded maximum size 20")
+		require.LessOrEqual(t, scas.bytesRead, 21)
+		require.True(t, scas.closed)
+	})
+
+	t.Run("error - decompressed content exceeds maximum size (decompression bomb)", func(t *testing.T) {
+		bomb, err := cp.Compress(compressionAlgorithm, make([]byte, 10*1024*1024))
+		require.NoError(t, err)
+
+		scas := &streamingCAS{content: bomb}
+
+		provider := NewOperationProvider(p, operationparser.New(p), scas, cp)
+
+		file, err := provider.readFromCAS(context.Background(), address, uint(len(bomb)))
+		require.Error(t, err)
+		require.Nil(t, file)
+		require.Contains(t, err.Error(), fmt.Sprintf("decompressed content size exceeded maximum decompressed content size %d", 3*len(bomb)))
+	})
+
+	t.Run("error - decompressed content exceeds maximum size (non-streaming decompression provider)", func(t *testing.T) {
+		testContent, err := cp.Compress(compressionAlgorithm, []byte(sampleChunkFile))
+		require.NoError(t, err)
+
+		p2 := protocol.Protocol{
+			CompressionAlgorithm:         compressionAlgorithm,
+			MaxMemoryDecompressionFactor: 1,
+		}
+
+		provider := NewOperationProvider(p2, operationparser.New(p2), &streamingCAS{content: testContent},
+			&nonStreamingDecompressor{cp: cp})
+
+		file, err := provider.readFromCAS(context.Background(), address, 247)
+		require.Error(t, err)
+		require.Nil(t, file)
+		require.Contains(t, err.Error(), "decompressed content size exceeded maximum decompressed content size 247")
+	})
+
+	t.Run("error - streaming CAS open error", func(t *testing.T) {
+		provider := NewOperationProvider(p, operationparser.New(p), &streamingCAS{err: errors.New("open error")}, cp)
+
+		file, err := provider.readFromCAS(context.Background(), address, maxFileSize)
+		require.Error(t, err)
+		require.Nil(t, file)
+		require.Contains(t, err.Error(), "retrieve CAS content at uri["+address+"]: open error")
+	})
+
+	t.Run("error - context cancelled during streaming read", func(t *testing.T) {
+		largeContent, err := cp.Compress(compressionAlgorithm, make([]byte, 10*1024*1024))
+		require.NoError(t, err)
+
+		ctx, cancel := context.WithCancel(context.Background())
+
+		// the context is cancelled while the content is being read
+		scas := &streamingCAS{content: largeContent, onRead: cancel}
+
+		provider := NewOperationProvider(p, operationparser.New(p), scas, cp)
+
+		file, err := provider.readFromCAS(ctx, address, 100*1024*1024)
+		require.Error(t, err)
+		require.Nil(t, file)
+		require.True(t, errors.Is(err, context.Canceled), err.Error())
+		require.Less(t, scas.bytesRead, len(largeContent))
+		require.True(t, scas.closed)
+	})
+
+	t.Run("error - context cancelled during non-streaming read", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+
+		provider := NewOperationProvider(p, operationparser.New(p), &cancellingCAS{DCAS: cas, cancel: cancel}, cp)
+
+		file, err := provider.readFromCAS(ctx, address, maxFileSize)
+		require.Error(t, err)
+		require.Nil(t, file)
+		require.True(t, errors.Is(err, context.Canceled))
+	})
+
+	t.Run("error - streaming CAS read error", func(t *testing.T) {
+		scas := &streamingCAS{content: content, readErr: errors.New("hash of content doesn't match address")}
+
+		provider := NewOperationProvider(p, operationparser.New(p), scas, cp)
+
+		file, err := provider.readFromCAS(context.Background(), address, maxFileSize)
+		require.Error(t, err)
+		require.Nil(t, file)
+		require.Contains(t, err.Error(), "hash of content doesn't match address")
+	})
 }
 
 func TestHandler_GetCorePoofFile(t *testing.T) {
@@ -862,7 +1166,7 @@ func TestHandler_GetCorePoofFile(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		provider := NewOperationProvider(p, operationparser.New(p), cas, cp)
 
-		file, err := provider.getCoreProofFile(uri)
+		file, err := provider.getCoreProofFile(context.Background(), uri)
 		require.NoError(t, err)
 		require.NotNil(t, file)
 	})
@@ -871,7 +1175,7 @@ func TestHandler_GetCorePoofFile(t *testing.T) {
 		lowMaxFileSize := protocol.Protocol{MaxProofFileSize: 10, CompressionAlgorithm: compressionAlgorithm}
 		provider := NewOperationProvider(lowMaxFileSize, operationparser.New(p), cas, cp)
 
-		file, err := provider.getCoreProofFile(uri)
+		file, err := provider.getCoreProofFile(context.Background(), uri)
 		require.Error(t, err)
 		require.Nil(t, file)
 		require.Contains(t, err.Error(), "exceeded maximum size 10")
@@ -883,7 +1187,7 @@ func TestHandler_GetCorePoofFile(t *testing.T) {
 		address, err := cas.Write(content)
 
 		provider := NewOperationProvider(p, operationparser.New(p), cas, cp)
-		file, err := provider.getCoreProofFile(address)
+		file, err := provider.getCoreProofFile(context.Background(), address)
 		require.Error(t, err)
 		require.Nil(t, file)
 		require.Contains(t, err.Error(), "failed to parse content for core proof file")
@@ -905,7 +1209,7 @@ func TestHandler_GetCorePoofFile(t *testing.T) {
 		address, err := cas.Write(content)
 
 		provider := NewOperationProvider(p, operationparser.New(p), cas, cp)
-		file, err := provider.getCoreProofFile(address)
+		file, err := provider.getCoreProofFile(context.Background(), address)
 		require.Error(t, err)
 		require.Nil(t, file)
 		require.Contains(t, err.Error(), "failed to validate signed data for recover[0]")
@@ -1004,7 +1308,7 @@ func TestHandler_GetProvisionalPoofFile(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		provider := NewOperationProvider(p, operationparser.New(p), cas, cp)
 
-		file, err := provider.getProvisionalProofFile(uri)
+		file, err := provider.getProvisionalProofFile(context.Background(), uri)
 		require.NoError(t, err)
 		require.NotNil(t, file)
 	})
@@ -1013,7 +1317,7 @@ func TestHandler_GetProvisionalPoofFile(t *testing.T) {
 		lowMaxFileSize := protocol.Protocol{MaxProofFileSize: 10, CompressionAlgorithm: compressionAlgorithm}
 		provider := NewOperationProvider(lowMaxFileSize, operationparser.New(p), cas, cp)
 
-		file, err := provider.getProvisionalProofFile(uri)
+		file, err := provider.getProvisionalProofFile(context.Background(), uri)
 		require.Error(t, err)
 		require.Nil(t, file)
 		require.Contains(t, err.Error(), "exceeded maximum size 10")
@@ -1025,7 +1329,7 @@ func TestHandler_GetProvisionalPoofFile(t *testing.T) {
 		address, err := cas.Write(content)
 
 		provider := NewOperationProvider(p, operationparser.New(p), cas, cp)
-		file, err := provider.getProvisionalProofFile(address)
+		file, err := provider.getProvisionalProofFile(context.Background(), address)
 		require.Error(t, err)
 		require.Nil(t, file)
 		require.Contains(t, err.Error(), "failed to parse content for provisional proof file")
@@ -1047,7 +1351,7 @@ func TestHandler_GetProvisionalPoofFile(t *testing.T) {
 		address, err := cas.Write(content)
 
 		provider := NewOperationProvider(p, operationparser.New(p), cas, cp)
-		file, err := provider.getProvisionalProofFile(address)
+		file, err := provider.getProvisionalProofFile(context.Background(), address)
 		require.Error(t, err)
 		require.Nil(t, file)
 		require.Contains(t, err.Error(), "failed to validate signed data for update[0]")
@@ -1146,7 +1450,7 @@ func TestHandler_GetBatchFiles(t *testing.T) {
 		p := newMockProtocolClient().Protocol
 		provider := NewOperationProvider(p, operationparser.New(p), cas, cp)
 
-		file, err := provider.getBatchFiles(af)
+		file, err := provider.getBatchFiles(context.Background(), af)
 		require.NoError(t, err)
 		require.NotNil(t, file)
 	})
@@ -1157,7 +1461,7 @@ func TestHandler_GetBatchFiles(t *testing.T) {
 
 		provider := NewOperationProvider(p, operationparser.New(p), cas, cp)
 
-		file, err := provider.getBatchFiles(af)
+		file, err := provider.getBatchFiles(context.Background(), af)
 		require.Error(t, err)
 		require.Nil(t, file)
 		require.Contains(t, err.Error(), "exceeded maximum size 10")
@@ -1169,7 +1473,7 @@ func TestHandler_GetBatchFiles(t *testing.T) {
 
 		provider := NewOperationProvider(p, operationparser.New(p), cas, cp)
 
-		file, err := provider.getBatchFiles(af)
+		file, err := provider.getBatchFiles(context.Background(), af)
 		require.Error(t, err)
 		require.Nil(t, file)
 		require.Contains(t, err.Error(), "exceeded maximum size 7")
@@ -1205,7 +1509,7 @@ func TestHandler_GetBatchFiles(t *testing.T) {
 			CoreProofFileURI:        "",
 		}
 
-		file, err := provider.getBatchFiles(af2)
+		file, err := provider.getBatchFiles(context.Background(), af2)
 		require.Error(t, err)
 		require.Nil(t, file)
 		require.Contains(t, err.Error(), "failed to unmarshal provisional proof file: invalid character")
@@ -1217,7 +1521,7 @@ func TestHandler_GetBatchFiles(t *testing.T) {
 
 		provider := NewOperationProvider(p, operationparser.New(p), cas, cp)
 
-		file, err := provider.getBatchFiles(af)
+		file, err := provider.getBatchFiles(context.Background(), af)
 		require.Error(t, err)
 		require.Nil(t, file)
 		require.Contains(t, err.Error(), "exceeded maximum size 10")
@@ -1249,7 +1553,7 @@ func TestHandler_GetBatchFiles(t *testing.T) {
 			ProvisionalIndexFileURI: pif2URI,
 		}
 
-		file, err := provider.getBatchFiles(cif)
+		file, err := provider.getBatchFiles(context.Background(), cif)
 		require.Error(t, err)
 		require.Nil(t, file)
 		require.Contains(t, err.Error(), "missing provisional proof file URI")
@@ -1288,7 +1592,7 @@ func TestHandler_GetBatchFiles(t *testing.T) {
 			},
 		}
 
-		file, err := provider.getBatchFiles(cif)
+		file, err := provider.getBatchFiles(context.Background(), cif)
 		require.Error(t, err)
 		require.Nil(t, file)
 		require.Contains(t, err.Error(), "number of recover ops[1] in core index doesn't match number of recover ops[0] in core proof")
@@ -1301,7 +1605,7 @@ func TestHandler_GetBatchFiles(t *testing.T) {
 		missingChunkURI, err := writeToCAS(&models.ProvisionalIndexFile{}, cas)
 		require.NoError(t, err)
 
-		file, err := provider.getBatchFiles(&models.CoreIndexFile{
+		file, err := provider.getBatchFiles(context.Background(), &models.CoreIndexFile{
 			ProvisionalIndexFileURI: missingChunkURI,
 		})
 		require.Error(t, err)
@@ -1666,3 +1970,132 @@ func newMockProtocolClient() *mocks.MockProtocolClient {
 }
 
 const sampleChunkFile = `{"chunks":[{"chunkFileUri":"EiDkiD-FuKC5mcsY4m0pd3OMTP7FAfo690gzN7-6JxcN1g"}],"operations":{"update":[{"didSuffix":"update-1","revealValue":"EiAdqFJ-x5QhwPq62DB9EfenKloqntykHJkZrwI6uxkoVQ"}]},"provisionalProofFileUri":"EiDdEHTL3VmFZO5hXoth8vTKnXgvfvW4lLJXyMjqs7ezUA"}`
+
+type readTrackingCAS struct {
+	DCAS
+
+	uris  []string
+	mutex sync.Mutex
+}
+
+func (m *readTrackingCAS) Read(uri string) ([]byte, error) {
+	m.mutex.Lock()
+	m.uris = append(m.uris, uri)
+	m.mutex.Unlock()
+
+	return m.DCAS.Read(uri)
+}
+
+// prefetchTrackingCAS implements a CAS client that supports prefetching and records the prefetched URIs.
+type prefetchTrackingCAS struct {
+	readTrackingCAS
+
+	prefetched []string
+}
+
+func (m *prefetchTrackingCAS) Prefetch(uris ...string) {
+	m.mutex.Lock()
+	m.prefetched = append(m.prefetched, uris...)
+	m.mutex.Unlock()
+}
+
+// streamingCAS implements a CAS client that supports streaming reads and records the number of bytes read.
+type streamingCAS struct {
+	content   []byte
+	err       error
+	readErr   error
+	bytesRead int
+	closed    bool
+	onRead    func()
+}
+
+func (m *streamingCAS) Read(string) ([]byte, error) {
+	return nil, errors.New("not expected to be called")
+}
+
+func (m *streamingCAS) ReadStream(string) (io.ReadCloser, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+
+	return &streamingCASReader{cas: m, reader: bytes.NewReader(m.content)}, nil
+}
+
+type streamingCASReader struct {
+	cas    *streamingCAS
+	reader io.Reader
+}
+
+func (r *streamingCASReader) Read(p []byte) (int, error) {
+	if r.cas.onRead != nil {
+		r.cas.onRead()
+	}
+
+	n, err := r.reader.Read(p)
+	r.cas.bytesRead += n
+
+	if err == io.EOF && r.cas.readErr != nil {
+		return n, r.cas.readErr
+	}
+
+	return n, err
+}
+
+func (r *streamingCASReader) Close() error {
+	r.cas.closed = true
+
+	return nil
+}
+
+// nonStreamingDecompressor implements a decompression provider that doesn't support streaming.
+type nonStreamingDecompressor struct {
+	cp *compression.Registry
+}
+
+func (d *nonStreamingDecompressor) Decompress(alg string, data []byte) ([]byte, error) {
+	return d.cp.Decompress(alg, data)
+}
+
+// cancellingCAS cancels a context after the first read.
+type cancellingCAS struct {
+	DCAS
+
+	cancel context.CancelFunc
+	reads  int
+}
+
+func (m *cancellingCAS) Read(uri string) ([]byte, error) {
+	m.reads++
+	m.cancel()
+
+	return m.DCAS.Read(uri)
+}
+
+// concurrencyTrackingCAS records the maximum number of concurrent reads.
+type concurrencyTrackingCAS struct {
+	DCAS
+
+	delay      time.Duration
+	concurrent int32
+	max        int32
+}
+
+func (m *concurrencyTrackingCAS) Read(uri string) ([]byte, error) {
+	n := atomic.AddInt32(&m.concurrent, 1)
+	defer atomic.AddInt32(&m.concurrent, -1)
+
+	for {
+		max := atomic.LoadInt32(&m.max)
+		if n <= max || atomic.CompareAndSwapInt32(&m.max, max, n) {
+			break
+		}
+	}
+
+	time.Sleep(m.delay)
+
+	return m.DCAS.Read(uri)
+}
+
+func (m *concurrencyTrackingCAS) maxConcurrentReads() int32 {
+	return atomic.LoadInt32(&m.max)
+}